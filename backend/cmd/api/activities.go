@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type ActivitiesResponse struct {
+	Activities []data.Activity `json:"activities"`
+	Metadata   data.Metadata   `json:"metadata"`
+}
+
+// recordActivity saves the activity for the followers of the user. The action it describes already succeeded,
+// so a failure is only logged.
+func (app *application) recordActivity(r *http.Request, activity *data.Activity) {
+	err := app.models.Activities.Insert(activity)
+	if err != nil {
+		app.logError(r, err)
+	}
+}
+
+// GetFeed godoc
+//
+// @Summary Get my activity feed
+// @Description Returns ratings, reviews and public list updates of followed users, the latest first.
+// @Description Users keeping their activity private are left out. Pages are read with cursors only
+// @Tags social
+// @Produce json
+// @Param page_size query int false "Page size" default(20)
+// @Param cursor query string false "Cursor from metadata.next_cursor of the previous page"
+// @Security BearerAuth
+// @Success 200 {object} ActivitiesResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/feed [get]
+func (app *application) feedHandler(w http.ResponseWriter, r *http.Request) {
+	app.listActivities(w, r, app.models.Activities.GetFeed, app.contextGetUser(r).ID)
+}
+
+// GetUserActivity godoc
+//
+// @Summary Get activity of a user
+// @Description Returns ratings, reviews and public list updates of the user, the latest first.
+// @Description Activity kept for followers is shown to followers only, private activity to the user only
+// @Tags social
+// @Produce json
+// @Param userID path int true "User ID"
+// @Param page_size query int false "Page size" default(20)
+// @Param cursor query string false "Cursor from metadata.next_cursor of the previous page"
+// @Security BearerAuth
+// @Success 200 {object} ActivitiesResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/{userID}/activity [get]
+func (app *application) userActivityHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "userID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	privacy, err := app.models.Users.GetPrivacy(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	viewerID := app.contextGetUser(r).ID
+
+	if viewerID != id {
+		switch privacy.ActivityVisibility {
+		case data.ActivityPrivate:
+			app.notPermittedResponse(w, r)
+			return
+		case data.ActivityFollowers:
+			following, err := app.models.Follows.IsFollowing(viewerID, id)
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+
+			if !following {
+				app.notPermittedResponse(w, r)
+				return
+			}
+		}
+	}
+
+	app.listActivities(w, r, app.models.Activities.GetForUser, id)
+}
+
+// listActivities responds with a page of activities loaded by getAll for the user.
+func (app *application) listActivities(w http.ResponseWriter, r *http.Request,
+	getAll func(int64, data.Filters) ([]*data.Activity, data.Metadata, error), userID int64) {
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.Cursor = app.readString(qs, "cursor", "")
+	filters.Sort = "-created_at"
+	filters.SortSafeList = []string{"-created_at"}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Cursor, validation.Length(0, 1000)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	activities, metadata, err := getAll(userID, filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrInvalidCursor):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"activities": activities, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestUserActivityHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockUsers := mocks.NewUsersInterface(t)
+	mockUsers.On("GetPrivacy", int64(1)).Return(&data.Privacy{ActivityVisibility: data.ActivityPrivate}, nil).Maybe()
+	mockUsers.On("GetPrivacy", int64(2)).Return(&data.Privacy{ActivityVisibility: data.ActivityPublic}, nil).Maybe()
+	mockUsers.On("GetPrivacy", int64(3)).Return(&data.Privacy{ActivityVisibility: data.ActivityFollowers}, nil).Maybe()
+	mockUsers.On("GetPrivacy", int64(4)).Return(&data.Privacy{ActivityVisibility: data.ActivityFollowers}, nil).Maybe()
+	mockUsers.On("GetPrivacy", int64(5)).Return(&data.Privacy{ActivityVisibility: data.ActivityPrivate}, nil).Maybe()
+	mockUsers.On("GetPrivacy", int64(6)).Return(nil, data.ErrRecordNotFound).Maybe()
+
+	mockFollows := mocks.NewFollowsInterface(t)
+	mockFollows.On("IsFollowing", int64(1), int64(3)).Return(true, nil).Maybe()
+	mockFollows.On("IsFollowing", int64(1), int64(4)).Return(false, nil).Maybe()
+
+	mockActivities := mocks.NewActivitiesInterface(t)
+	mockActivities.On("GetForUser", mock.AnythingOfType("int64"), mock.AnythingOfType("data.Filters")).
+		Return([]*data.Activity{}, data.Metadata{}, nil).Maybe()
+
+	app.models.Users = mockUsers
+	app.models.Follows = mockFollows
+	app.models.Activities = mockActivities
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+	}{
+		{
+			name:     "Own private activity",
+			urlPath:  "/v1/users/1/activity",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Public activity",
+			urlPath:  "/v1/users/2/activity",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Followers activity of a followed user",
+			urlPath:  "/v1/users/3/activity",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Followers activity of a user not followed",
+			urlPath:  "/v1/users/4/activity",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Private activity of another user",
+			urlPath:  "/v1/users/5/activity",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Non-existent user",
+			urlPath:  "/v1/users/6/activity",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestFeedHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockActivities := mocks.NewActivitiesInterface(t)
+	mockActivities.On("GetFeed", int64(1), mock.AnythingOfType("data.Filters")).
+		Return([]*data.Activity{{ID: 1, UserID: 2, UserName: "Anna", Type: data.ActivityRating}}, data.Metadata{}, nil).Once()
+
+	app.models.Activities = mockActivities
+
+	code, _, body := ts.get(t, "/v1/users/me/feed")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+	assert.Contains(t, string(body), `"user_name": "Anna"`)
+
+	code, _, _ = ts.get(t, "/v1/users/me/feed?page_size=500")
+	assert.Equal(t, http.StatusUnprocessableEntity, code, "status code should be 422")
+}
@@ -16,6 +16,7 @@ type application struct {
 	models   data.Models
 	mailer   mailer.Mailer
 	grpcConn *grpc.ClientConn
+	events   *eventBuffer
 	wg       sync.WaitGroup
 }
 
@@ -26,5 +27,6 @@ func newApplication(cfg config, logger *slog.Logger, db *sql.DB, mailer mailer.M
 		models:   data.NewModels(db),
 		mailer:   mailer,
 		grpcConn: grpcConn,
+		events:   newEventBuffer(cfg.events.bufferSize, cfg.events.batchSize),
 	}
 }
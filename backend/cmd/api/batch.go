@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/patch"
+)
+
+const (
+	batchModeAtomic  = "atomic"
+	batchModePartial = "partial"
+)
+
+type batchOperationInput struct {
+	Op      string          `json:"op" example:"update"`
+	ID      int64           `json:"id,omitempty" example:"1"`
+	Version int32           `json:"version,omitempty" example:"3"`
+	Force   bool            `json:"force,omitempty" example:"false"`
+	Movie   json.RawMessage `json:"movie,omitempty" swaggertype:"object"`
+}
+
+type batchInput struct {
+	Mode       string                `json:"mode" example:"atomic"`
+	Operations []batchOperationInput `json:"operations"`
+}
+
+type batchResult struct {
+	Index  int         `json:"index" example:"0"`
+	Op     string      `json:"op" example:"update"`
+	Status int         `json:"status" example:"200"`
+	Movie  *data.Movie `json:"movie,omitempty"`
+	Error  string      `json:"error,omitempty" example:"title: cannot be blank."`
+}
+
+type BatchResponse struct {
+	Results []batchResult `json:"results"`
+}
+
+// BatchMovies godoc
+//
+// @Summary Create, update and delete movies in a batch
+// @Description Applies up to 100 operations in one transaction (admin only). Create takes a movie, update takes a merge patch in movie
+// @Description and an expected version, delete takes an expected version. The versions are optional unless If-Match is required.
+// @Description Create fails with 409 when a movie with the same title and year exists, unless force is set.
+// @Description In atomic mode (default) nothing is saved if any operation fails, in partial mode failed operations are skipped.
+// @Description Every operation gets its own status and error, errors are shaped like in single movie endpoints.
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Param batch body batchInput true "Batch payload"
+// @Security BearerAuth
+// @Success 200 {object} BatchResponse
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 422 {object} BatchResponse "Unprocessable Entity, atomic batch was not saved"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/batch [post]
+func (app *application) batchMoviesHandler(w http.ResponseWriter, r *http.Request) {
+	var input batchInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Mode == "" {
+		input.Mode = batchModeAtomic
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Mode, validation.In(batchModeAtomic, batchModePartial)),
+		validation.Field(&input.Operations, validation.Required, validation.Length(1, 100)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	results := make([]batchResult, len(input.Operations))
+	ops := make([]*data.MovieOperation, len(input.Operations))
+	failed := false
+
+	// operations are prepared and validated before the transaction, so it is held only while saving
+	for i, opInput := range input.Operations {
+		results[i] = batchResult{Index: i, Op: opInput.Op}
+
+		ops[i], results[i].Status, err = app.prepareMovieOperation(opInput)
+		if err != nil {
+			if results[i].Status == http.StatusInternalServerError {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+
+			results[i].Error = err.Error()
+			failed = true
+		}
+	}
+
+	atomic := input.Mode == batchModeAtomic
+
+	if failed && atomic {
+		app.writeBatchResults(w, r, http.StatusUnprocessableEntity, results, true)
+		return
+	}
+
+	var valid []*data.MovieOperation
+	for _, op := range ops {
+		if op != nil {
+			valid = append(valid, op)
+		}
+	}
+
+	if len(valid) > 0 {
+		err = app.models.Movies.Batch(valid, atomic, app.contextGetUser(r).ID)
+		if err != nil && !errors.Is(err, data.ErrBatchRolledBack) {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		failed = failed || err != nil
+	}
+
+	for i, op := range ops {
+		if op == nil {
+			continue
+		}
+
+		switch {
+		case errors.Is(op.Err, data.ErrRecordNotFound):
+			results[i].Status = http.StatusNotFound
+			results[i].Error = "requested resource could not be found"
+			failed = true
+		case errors.Is(op.Err, data.ErrEditConflict):
+			results[i].Status = http.StatusConflict
+			results[i].Error = "unable to update the record due to an edit conflict, please try again"
+			failed = true
+		case op.Action != data.MovieOpDelete:
+			results[i].Movie = op.Movie
+		}
+	}
+
+	status := http.StatusOK
+	if failed && atomic {
+		status = http.StatusUnprocessableEntity
+	}
+
+	app.writeBatchResults(w, r, status, results, failed && atomic)
+}
+
+// prepareMovieOperation validates the batch operation and builds the model operation,
+// returning the status the operation gets when it succeeds or the status of the error.
+func (app *application) prepareMovieOperation(input batchOperationInput) (*data.MovieOperation, int, error) {
+	err := validation.ValidateStruct(&input,
+		validation.Field(&input.Op, validation.Required, validation.In(data.MovieOpCreate, data.MovieOpUpdate, data.MovieOpDelete)),
+		validation.Field(&input.ID, validation.When(input.Op == data.MovieOpCreate, validation.Empty).Else(validation.Required, validation.Min(int64(1)))),
+		// batches carry no If-Match headers, the expected versions take their place when they are required
+		validation.Field(&input.Version, validation.When(input.Op == data.MovieOpCreate, validation.Empty).Else(validation.Min(int32(0)),
+			validation.When(app.config.conditional.requireIfMatch, validation.Required))),
+		validation.Field(&input.Force, validation.When(input.Op != data.MovieOpCreate, validation.Empty)),
+		validation.Field(&input.Movie, validation.When(input.Op == data.MovieOpDelete, validation.Empty).Else(validation.Required)),
+	)
+	if err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+
+	op := &data.MovieOperation{Action: input.Op, ID: input.ID, Version: input.Version}
+
+	var movie *data.Movie
+
+	switch input.Op {
+	case data.MovieOpDelete:
+		return op, http.StatusOK, nil
+	case data.MovieOpCreate:
+		movie = &data.Movie{}
+	case data.MovieOpUpdate:
+		movie, err = app.models.Movies.Get(input.ID)
+		if err != nil {
+			switch {
+			case errors.Is(err, data.ErrRecordNotFound):
+				return nil, http.StatusNotFound, errors.New("requested resource could not be found")
+			default:
+				return nil, http.StatusInternalServerError, err
+			}
+		}
+
+		if input.Version != 0 {
+			movie.Version = input.Version
+		}
+	}
+
+	// created movie is the merge patch applied to an empty movie
+	movieInput := movieInput{
+		Title:   movie.Title,
+		Year:    movie.Year,
+		Runtime: movie.Runtime,
+		Genres:  movie.Genres,
+	}
+
+	err = applyPatch(&movieInput, input.Movie, patch.MergePatch)
+	if err != nil {
+		switch {
+		case errors.Is(err, patch.ErrInvalidPatch):
+			return nil, http.StatusBadRequest, err
+		default:
+			return nil, http.StatusUnprocessableEntity, err
+		}
+	}
+
+	movie.Title = movieInput.Title
+	movie.Year = movieInput.Year
+	movie.Runtime = movieInput.Runtime
+	movie.Genres = movieInput.Genres
+
+	err = app.validateMovie(movie)
+	if err != nil {
+		var validationErrors validation.Errors
+
+		switch {
+		case errors.As(err, &validationErrors):
+			return nil, http.StatusUnprocessableEntity, err
+		default:
+			return nil, http.StatusInternalServerError, err
+		}
+	}
+
+	op.Movie = movie
+
+	if input.Op == data.MovieOpCreate {
+		if !input.Force {
+			duplicate, err := app.models.Movies.FindDuplicate(movie)
+			if err != nil && !errors.Is(err, data.ErrRecordNotFound) {
+				return nil, http.StatusInternalServerError, err
+			}
+
+			if duplicate != nil {
+				return nil, http.StatusConflict, fmt.Errorf("a movie with the same title and year already exists (id %d)", duplicate.ID)
+			}
+		}
+
+		return op, http.StatusCreated, nil
+	}
+
+	return op, http.StatusOK, nil
+}
+
+// writeBatchResults sends batch results, when the batch was rolled back successful operations
+// are reported as not applied with 424 Failed Dependency.
+func (app *application) writeBatchResults(w http.ResponseWriter, r *http.Request, status int, results []batchResult, rolledBack bool) {
+	if rolledBack {
+		for i := range results {
+			if results[i].Error == "" {
+				results[i].Status = http.StatusFailedDependency
+				results[i].Movie = nil
+				results[i].Error = "not applied because another operation in the batch failed"
+			}
+		}
+	}
+
+	err := app.writeJSON(w, status, envelope{"results": results}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestBatchMoviesHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockGenres := mocks.NewGenresInterface(t)
+
+	mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+		return &data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3}, nil
+	}).Maybe()
+	mockMovies.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound).Maybe()
+	mockMovies.On("Batch", mock.Anything, mock.Anything, int64(1)).Return(func(ops []*data.MovieOperation, atomic bool, actorID int64) error {
+		for _, op := range ops {
+			switch {
+			case op.Action == data.MovieOpDelete && op.ID == 3:
+				op.Err = data.ErrEditConflict
+				if atomic {
+					return data.ErrBatchRolledBack
+				}
+			case op.Action == data.MovieOpCreate:
+				op.Movie.ID = 10
+				op.Movie.Version = 1
+			case op.Action == data.MovieOpUpdate:
+				op.Movie.Version++
+			}
+		}
+
+		return nil
+	}).Maybe()
+	mockMovies.On("FindDuplicate", mock.Anything).Return(func(movie *data.Movie) (*data.Movie, error) {
+		if movie.Title == "Test Movie" {
+			return &data.Movie{ID: 1, Title: "Test Movie", Year: 2024}, nil
+		}
+
+		return nil, data.ErrRecordNotFound
+	}).Maybe()
+	mockGenres.On("Resolve", mock.Anything).Return(func(genres []string) ([]string, []string, error) {
+		return genres, []string{}, nil
+	}).Maybe()
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Genres = mockGenres
+	app.models.Permissions = mockPermissions
+
+	create := `{"op": "create", "movie": {"title": "New Movie", "year": 2020, "runtime": 100, "genres": ["Drama"]}}`
+	update := `{"op": "update", "id": 1, "movie": {"title": "Updated Movie"}}`
+
+	tests := []struct {
+		name         string
+		body         string
+		wantCode     int
+		wantStatuses []int
+	}{
+		{
+			name:         "Atomic batch",
+			body:         `{"operations": [` + create + `, ` + update + `, {"op": "delete", "id": 4}]}`,
+			wantCode:     http.StatusOK,
+			wantStatuses: []int{http.StatusCreated, http.StatusOK, http.StatusOK},
+		},
+		{
+			name:         "Atomic batch with invalid operation",
+			body:         `{"operations": [` + create + `, {"op": "create", "movie": {"title": ""}}]}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantStatuses: []int{http.StatusFailedDependency, http.StatusUnprocessableEntity},
+		},
+		{
+			name:         "Atomic batch with conflict",
+			body:         `{"operations": [` + update + `, {"op": "delete", "id": 3, "version": 2}]}`,
+			wantCode:     http.StatusUnprocessableEntity,
+			wantStatuses: []int{http.StatusFailedDependency, http.StatusConflict},
+		},
+		{
+			name:         "Partial batch",
+			body:         `{"mode": "partial", "operations": [` + update + `, {"op": "update", "id": 2, "movie": {}}, {"op": "delete", "id": 3, "version": 2}, {"op": "rename"}]}`,
+			wantCode:     http.StatusOK,
+			wantStatuses: []int{http.StatusOK, http.StatusNotFound, http.StatusConflict, http.StatusUnprocessableEntity},
+		},
+		{
+			name:         "Partial batch with duplicate",
+			body:         `{"mode": "partial", "operations": [{"op": "create", "movie": {"title": "Test Movie", "year": 2024, "runtime": 125, "genres": ["Drama"]}}, {"op": "create", "force": true, "movie": {"title": "Test Movie", "year": 2024, "runtime": 125, "genres": ["Drama"]}}]}`,
+			wantCode:     http.StatusOK,
+			wantStatuses: []int{http.StatusConflict, http.StatusCreated},
+		},
+		{
+			name:     "Unknown mode",
+			body:     `{"mode": "best-effort", "operations": [` + create + `]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Empty batch",
+			body:     `{"operations": []}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.post(t, "/v1/movie/batch", strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantStatuses != nil {
+				var resp BatchResponse
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				statuses := []int{}
+				for _, result := range resp.Results {
+					statuses = append(statuses, result.Status)
+				}
+
+				assert.Equal(t, tt.wantStatuses, statuses, "operation statuses should match")
+			}
+		})
+	}
+}
+
+func TestBatchMoviesRequiresVersion(t *testing.T) {
+	app := newTestApplication(t)
+
+	app.config.conditional.requireIfMatch = true
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockMovies.On("Batch", mock.Anything, false, int64(1)).Return(nil)
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Permissions = mockPermissions
+
+	body := `{"mode": "partial", "operations": [{"op": "update", "id": 1, "movie": {"title": "Updated Movie"}}, {"op": "delete", "id": 4}, {"op": "delete", "id": 4, "version": 1}]}`
+
+	code, _, respBody := ts.post(t, "/v1/movie/batch", strings.NewReader(body))
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	var resp BatchResponse
+
+	err := json.Unmarshal(respBody, &resp)
+	assert.NoError(t, err)
+
+	if assert.Len(t, resp.Results, 3) {
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Results[0].Status, "update without version should fail")
+		assert.Contains(t, resp.Results[0].Error, "version")
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Results[1].Status, "delete without version should fail")
+		assert.Equal(t, http.StatusOK, resp.Results[2].Status, "delete with version should succeed")
+	}
+}
+
+func TestBatchMoviesPermissions(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{}, nil)
+
+	app.models.Permissions = mockPermissions
+
+	code, _, _ := ts.post(t, "/v1/movie/batch", strings.NewReader(`{"operations": [{"op": "delete", "id": 4, "version": 1}]}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
@@ -0,0 +1,389 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/validate"
+)
+
+type collectionInput struct {
+	Name     string `json:"name" example:"The Lord of the Rings"`
+	Overview string `json:"overview" example:"Frodo and the Fellowship set out to destroy the One Ring..."`
+}
+
+type collectionMoviesInput struct {
+	MovieIDs []int64 `json:"movie_ids" example:"120,121,122"`
+}
+
+type CollectionsListResponse struct {
+	Collections []data.Collection `json:"collections"`
+	Metadata    data.Metadata     `json:"metadata"`
+}
+
+func validateCollection(collection *data.Collection) error {
+	return validation.ValidateStruct(collection,
+		validation.Field(&collection.Name, validation.Required, validation.Length(1, 200)),
+		validation.Field(&collection.Overview, validation.Length(0, 5000)),
+	)
+}
+
+// ListCollections godoc
+//
+// @Summary List collections
+// @Description Returns a page of collections with the number of their movies
+// @Tags collections
+// @Produce json
+// @Param name query string false "Part of the collection name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by id, name (prefix with - for descending)" default(name)
+// @Security BearerAuth
+// @Success 200 {object} CollectionsListResponse
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /collections [get]
+func (app *application) listCollectionsHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	name := app.readString(qs, "name", "")
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.Sort = app.readString(qs, "sort", "name")
+	filters.SortSafeList = []string{"id", "name", "-id", "-name"}
+
+	err = validation.Validate(name, validation.Length(0, 200))
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("name: %w", err))
+		return
+	}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Sort, validation.Required, validation.In(filters.SortSafeList...)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	collections, metadata, err := app.models.Collections.GetAll(name, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"collections": collections, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GetCollection godoc
+//
+// @Summary Get a collection by ID
+// @Description Returns a collection with its movies in collection order
+// @Tags collections
+// @Produce json
+// @Param collectionID path int true "Collection ID"
+// @Security BearerAuth
+// @Success 200 {object} data.Collection
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /collections/{collectionID} [get]
+func (app *application) getCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "collectionID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	collection, err := app.models.Collections.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"collection": collection}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CreateCollection godoc
+//
+// @Summary Create a collection
+// @Description Adds an empty collection (admin only), movies are added with PUT /collections/{collectionID}/movies
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collection body collectionInput true "Collection payload"
+// @Security BearerAuth
+// @Success 201 {object} data.Collection
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /collections [post]
+func (app *application) createCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	var input collectionInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	collection := &data.Collection{
+		Name:     strings.TrimSpace(input.Name),
+		Overview: strings.TrimSpace(input.Overview),
+	}
+
+	err = validateCollection(collection)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Collections.Insert(collection)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"collection": collection}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateCollection godoc
+//
+// @Summary Update a collection
+// @Description Changes collection name and/or overview (admin only)
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collectionID path int true "Collection ID"
+// @Param collection body collectionInput true "Partial collection payload"
+// @Security BearerAuth
+// @Success 200 {object} data.Collection
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /collections/{collectionID} [patch]
+func (app *application) updateCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "collectionID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	collection, err := app.models.Collections.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	// using pointers here to be able to compare which field was empty
+	var input struct {
+		Name     *string `json:"name"`
+		Overview *string `json:"overview"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		collection.Name = strings.TrimSpace(*input.Name)
+	}
+
+	if input.Overview != nil {
+		collection.Overview = strings.TrimSpace(*input.Overview)
+	}
+
+	err = validateCollection(collection)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Collections.Update(collection)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"collection": collection}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteCollection godoc
+//
+// @Summary Delete a collection
+// @Description Deletes a collection (admin only), its movies are kept
+// @Tags collections
+// @Produce json
+// @Param collectionID path int true "Collection ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "collection successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /collections/{collectionID} [delete]
+func (app *application) deleteCollectionHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "collectionID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Collections.Delete(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "collection successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// SetCollectionMovies godoc
+//
+// @Summary Set collection movies
+// @Description Replaces movies of the collection (admin only), the order of ids is the order of the collection.
+// @Description A movie can belong to one collection only.
+// @Tags collections
+// @Accept json
+// @Produce json
+// @Param collectionID path int true "Collection ID"
+// @Param movies body collectionMoviesInput true "Ordered movie ids"
+// @Security BearerAuth
+// @Success 200 {object} data.Collection
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "movie_ids: movie already belongs to another collection"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /collections/{collectionID}/movies [put]
+func (app *application) setCollectionMoviesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "collectionID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input collectionMoviesInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.MovieIDs == nil {
+		input.MovieIDs = []int64{}
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.MovieIDs, validation.Length(0, 100), validation.Each(validation.Required, validation.Min(int64(1))),
+			validation.By(validate.Unique(input.MovieIDs))),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	collection, err := app.models.Collections.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.models.Collections.SetMovies(collection, input.MovieIDs)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrMovieInCollection), errors.Is(err, data.ErrUnknownMovie):
+			app.failedValidationResponse(w, r, fmt.Errorf("movie_ids: %w", err))
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	collection, err = app.models.Collections.Get(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"collection": collection}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestGetCollectionHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockCollections := mocks.NewCollectionsInterface(t)
+
+	collection := data.Collection{
+		ID:         1,
+		Name:       "The Lord of the Rings",
+		MovieCount: 2,
+		Movies: []*data.Movie{
+			{ID: 120, Title: "The Fellowship of the Ring", Year: 2001, Genres: []string{"Fantasy"}, Version: 1},
+			{ID: 121, Title: "The Two Towers", Year: 2002, Genres: []string{"Fantasy"}, Version: 1},
+		},
+		Version: 1,
+	}
+
+	mockCollections.On("Get", int64(1)).Return(&collection, nil)
+	mockCollections.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound)
+
+	app.models.Collections = mockCollections
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+		wantBody *data.Collection
+	}{
+		{
+			name:     "Valid ID",
+			urlPath:  "/v1/collections/1",
+			wantCode: http.StatusOK,
+			wantBody: &collection,
+		},
+		{
+			name:     "Non-existent ID",
+			urlPath:  "/v1/collections/2",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "String ID",
+			urlPath:  "/v1/collections/lotr",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != nil {
+				var resp map[string]data.Collection
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.wantBody, resp["collection"], "collection should be equal")
+			}
+		})
+	}
+}
+
+func TestSetCollectionMoviesHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockCollections := mocks.NewCollectionsInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	mockCollections.On("Get", int64(1)).Return(func(int64) (*data.Collection, error) {
+		return &data.Collection{ID: 1, Name: "The Lord of the Rings", Version: 1}, nil
+	}).Maybe()
+	mockCollections.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound).Maybe()
+	mockCollections.On("SetMovies", mock.Anything, []int64{120, 121, 122}).Return(nil).Maybe()
+	mockCollections.On("SetMovies", mock.Anything, []int64{5}).Return(data.ErrMovieInCollection).Maybe()
+	mockCollections.On("SetMovies", mock.Anything, []int64{999}).Return(data.ErrUnknownMovie).Maybe()
+
+	app.models.Collections = mockCollections
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+		wantBody string
+	}{
+		{
+			name:     "Valid movies",
+			urlPath:  "/v1/collections/1/movies",
+			body:     `{"movie_ids": [120, 121, 122]}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Movie in another collection",
+			urlPath:  "/v1/collections/1/movies",
+			body:     `{"movie_ids": [5]}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: "movie already belongs to another collection",
+		},
+		{
+			name:     "Unknown movie",
+			urlPath:  "/v1/collections/1/movies",
+			body:     `{"movie_ids": [999]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Repeated movie",
+			urlPath:  "/v1/collections/1/movies",
+			body:     `{"movie_ids": [120, 120]}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: "values must be unique",
+		},
+		{
+			name:     "Non-existent collection",
+			urlPath:  "/v1/collections/2/movies",
+			body:     `{"movie_ids": [120]}`,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.send(t, http.MethodPut, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != "" {
+				assert.Contains(t, string(body), tt.wantBody)
+			}
+		})
+	}
+
+	mockCollections.AssertNotCalled(t, "SetMovies", mock.Anything, []int64{120, 120})
+}
+
+func TestCollectionHandlersPermissions(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{}, nil)
+
+	app.models.Permissions = mockPermissions
+
+	code, _, _ := ts.post(t, "/v1/collections", strings.NewReader(`{"name": "The Lord of the Rings"}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+
+	code, _, _ = ts.send(t, http.MethodPut, "/v1/collections/1/movies", nil, strings.NewReader(`{"movie_ids": [120]}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
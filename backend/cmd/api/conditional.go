@@ -0,0 +1,125 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// movieETag returns a strong entity tag of the movie: the movie version followed by a hash of
+// the embedded state that changes without a version bump. The state includes flags of the
+// authenticated user, responses already vary by Authorization (see authentication).
+func movieETag(movie *data.Movie) string {
+	js, _ := json.Marshal(struct {
+		Collection  *data.MovieCollection
+		Rating      float64
+		RatingCount int32
+		InWatchlist *bool
+		Seen        *bool
+	}{movie.Collection, movie.Rating, movie.RatingCount, movie.InWatchlist, movie.Seen})
+
+	sum := sha256.Sum256(js)
+
+	return fmt.Sprintf(`"%d-%s"`, movie.Version, hex.EncodeToString(sum[:8]))
+}
+
+// versionMatches reports whether one of the movie entity tags in the If-Match header value has the version.
+// Only the version is compared, so state outside the version doesn't fail writes.
+func versionMatches(header string, version int32) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" {
+			return true
+		}
+
+		if strings.HasPrefix(tag, "W/") {
+			continue
+		}
+
+		tag, _, _ = strings.Cut(strings.Trim(tag, `"`), "-")
+		if tag == strconv.Itoa(int(version)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// envelopeETag returns a weak entity tag built from the response data,
+// used for lists where a single version can't describe the whole response.
+func envelopeETag(data envelope) (string, error) {
+	js, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(js)
+
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// etagMatches reports whether the If-Match or If-None-Match header value matches the entity tag.
+// Weak comparison ignores the W/ prefix, strong comparison never matches weak tags.
+func etagMatches(header, etag string, weak bool) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" {
+			return true
+		}
+
+		if weak {
+			if strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+
+			continue
+		}
+
+		if tag == etag && !strings.HasPrefix(tag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// notModified sets the ETag header and responds with 304 Not Modified when
+// the If-None-Match header matches it. Returns true if the response has been sent.
+func (app *application) notModified(w http.ResponseWriter, r *http.Request, etag string) bool {
+	w.Header().Set("ETag", etag)
+
+	header := r.Header.Get("If-None-Match")
+	if header == "" || !etagMatches(header, etag, true) {
+		return false
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+
+	return true
+}
+
+// preconditionMet checks the If-Match header against the current movie version and responds with
+// 412 Precondition Failed on mismatch, or 428 Precondition Required when the header is missing
+// but required by config. Returns false if the response has been sent.
+func (app *application) preconditionMet(w http.ResponseWriter, r *http.Request, version int32) bool {
+	header := r.Header.Get("If-Match")
+
+	switch {
+	case header == "" && app.config.conditional.requireIfMatch:
+		app.preconditionRequiredResponse(w, r)
+		return false
+	case header != "" && !versionMatches(header, version):
+		app.preconditionFailedResponse(w, r)
+		return false
+	}
+
+	return true
+}
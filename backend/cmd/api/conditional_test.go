@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		etag   string
+		weak   bool
+		want   bool
+	}{
+		{name: "Same tag", header: `"3"`, etag: `"3"`, want: true},
+		{name: "Different tag", header: `"2"`, etag: `"3"`, want: false},
+		{name: "List of tags", header: `"1", "3"`, etag: `"3"`, want: true},
+		{name: "Any tag", header: "*", etag: `"3"`, want: true},
+		{name: "Weak tag strong comparison", header: `W/"3"`, etag: `"3"`, want: false},
+		{name: "Weak tag weak comparison", header: `W/"3"`, etag: `"3"`, weak: true, want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, etagMatches(tt.header, tt.etag, tt.weak))
+		})
+	}
+}
+
+func TestVersionMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{name: "Same version", header: `"3-9f86d081884c7d65"`, want: true},
+		{name: "Same version other state", header: `"3-0000000000000000"`, want: true},
+		{name: "Version only", header: `"3"`, want: true},
+		{name: "Different version", header: `"2-9f86d081884c7d65"`, want: false},
+		{name: "List of tags", header: `"1", "3-9f86d081884c7d65"`, want: true},
+		{name: "Any tag", header: "*", want: true},
+		{name: "Weak tag", header: `W/"3"`, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, versionMatches(tt.header, 3))
+		})
+	}
+}
+
+func TestGetMovieNotModified(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	movie := data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3}
+
+	mockMovies.On("Get", int64(1)).Return(&movie, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Watchlist = newWatchlistMock(t)
+	app.models.History = newHistoryMock(t)
+
+	code, header, _ := ts.get(t, "/v1/movie/1")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+	assert.True(t, strings.HasPrefix(header.Get("ETag"), `"3-`), "etag should start with the movie version")
+
+	code, _, body := ts.send(t, http.MethodGet, "/v1/movie/1", http.Header{"If-None-Match": {header.Get("ETag")}}, nil)
+	assert.Equal(t, http.StatusNotModified, code, "status code should be 304")
+	assert.Empty(t, body, "body should be empty")
+
+	code, _, _ = ts.send(t, http.MethodGet, "/v1/movie/1", http.Header{"If-None-Match": {`"2"`}}, nil)
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+}
+
+func TestGetMovieETagChanges(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(movie *data.Movie)
+	}{
+		{
+			name: "Added to collection",
+			change: func(movie *data.Movie) {
+				movie.Collection = &data.MovieCollection{ID: 1, Name: "Test Collection", Position: 1, Total: 2}
+			},
+		},
+		{
+			name: "Rated",
+			change: func(movie *data.Movie) {
+				movie.Rating = 8
+				movie.RatingCount = 1
+			},
+		},
+		{
+			name: "Added to watchlist",
+			change: func(movie *data.Movie) {
+				inWatchlist := true
+				movie.InWatchlist = &inWatchlist
+			},
+		},
+		{
+			name: "Seen",
+			change: func(movie *data.Movie) {
+				seen := true
+				movie.Seen = &seen
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApplication(t)
+
+			ts := newTestServer(t, testRoutes(app))
+			defer ts.Close()
+
+			mockMovies := mocks.NewMoviesInterface(t)
+
+			changed := false
+
+			mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+				movie := &data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3}
+				if changed {
+					tt.change(movie)
+				}
+
+				return movie, nil
+			})
+
+			app.models.Movies = mockMovies
+			app.models.Watchlist = newWatchlistMock(t)
+			app.models.History = newHistoryMock(t)
+
+			_, header, _ := ts.get(t, "/v1/movie/1")
+			etag := header.Get("ETag")
+
+			changed = true
+
+			code, header, _ := ts.send(t, http.MethodGet, "/v1/movie/1", http.Header{"If-None-Match": {etag}}, nil)
+			assert.Equal(t, http.StatusOK, code, "status code should be 200")
+			assert.Contains(t, header.Values("Vary"), "Authorization", "response should vary by user")
+			assert.NotEqual(t, etag, header.Get("ETag"), "etag should change")
+			assert.True(t, strings.HasPrefix(header.Get("ETag"), `"3-`), "version should stay the same")
+		})
+	}
+}
+
+func TestListMoviesNotModified(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	movies := []*data.Movie{{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3}}
+
+	mockMovies.On("GetAll", mock.Anything, mock.Anything).Return(movies, data.Metadata{CurrentPage: 1, PageSize: 20}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Watchlist = newWatchlistMock(t)
+	app.models.History = newHistoryMock(t)
+
+	code, header, _ := ts.get(t, "/v1/movie")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	etag := header.Get("ETag")
+	assert.True(t, strings.HasPrefix(etag, `W/"`), "list etag should be weak")
+
+	code, _, _ = ts.send(t, http.MethodGet, "/v1/movie", http.Header{"If-None-Match": {etag}}, nil)
+	assert.Equal(t, http.StatusNotModified, code, "status code should be 304")
+}
+
+func TestConditionalMovieWrites(t *testing.T) {
+	app := newTestApplication(t)
+	app.config.conditional.requireIfMatch = true
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockGenres := mocks.NewGenresInterface(t)
+
+	mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+		return &data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3}, nil
+	})
+	mockMovies.On("Update", mock.AnythingOfType("*data.Movie"), int64(1)).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*data.Movie).Version = 4
+	})
+	mockMovies.On("Delete", int64(1), int32(3), int64(1)).Return(nil)
+	mockGenres.On("Resolve", []string{"Drama"}).Return([]string{"Drama"}, []string{}, nil)
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Genres = mockGenres
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		method   string
+		ifMatch  string
+		wantCode int
+		wantETag string
+	}{
+		{
+			name:     "Update without If-Match",
+			method:   http.MethodPatch,
+			wantCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:     "Update with stale If-Match",
+			method:   http.MethodPatch,
+			ifMatch:  `"2"`,
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "Update with current If-Match",
+			method:   http.MethodPatch,
+			ifMatch:  `"3"`,
+			wantCode: http.StatusOK,
+			wantETag: `"4-`,
+		},
+		{
+			name:     "Update with current If-Match of other state",
+			method:   http.MethodPatch,
+			ifMatch:  `"3-0000000000000000"`,
+			wantCode: http.StatusOK,
+			wantETag: `"4-`,
+		},
+		{
+			name:     "Delete without If-Match",
+			method:   http.MethodDelete,
+			wantCode: http.StatusPreconditionRequired,
+		},
+		{
+			name:     "Delete with stale If-Match",
+			method:   http.MethodDelete,
+			ifMatch:  `"2"`,
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "Delete with current If-Match",
+			method:   http.MethodDelete,
+			ifMatch:  `"3"`,
+			wantCode: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.ifMatch != "" {
+				header.Set("If-Match", tt.ifMatch)
+			}
+
+			code, respHeader, _ := ts.send(t, tt.method, "/v1/movie/1", header, strings.NewReader(`{"title": "New Title"}`))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantETag != "" {
+				assert.True(t, strings.HasPrefix(respHeader.Get("ETag"), tt.wantETag), "etag should start with the new version")
+			}
+		})
+	}
+}
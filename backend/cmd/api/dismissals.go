@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// dismissedGenreWeight scales the score of a recommendation for every genre of it the user dismissed.
+const dismissedGenreWeight = 0.5
+
+// suppressDismissed leaves out recommended movies the user dismissed and down-weights movies
+// of dismissed genres, the result is ordered by the adjusted score.
+func (app *application) suppressDismissed(userID int64, recommendations []*MovieRecommendation) ([]*MovieRecommendation, error) {
+	ids := make([]int64, 0, len(recommendations))
+	for _, recommendation := range recommendations {
+		ids = append(ids, recommendation.Movie.ID)
+	}
+
+	suppressions, err := app.models.Dismissals.Suppressions(userID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(suppressions) == 0 {
+		return recommendations, nil
+	}
+
+	kept := make([]*MovieRecommendation, 0, len(recommendations))
+
+	for _, recommendation := range recommendations {
+		suppression, ok := suppressions[recommendation.Movie.ID]
+
+		switch {
+		case !ok:
+			kept = append(kept, recommendation)
+		case !suppression.Dismissed:
+			recommendation.Score *= math.Pow(dismissedGenreWeight, float64(suppression.DismissedGenres))
+			kept = append(kept, recommendation)
+		}
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].Score > kept[j].Score
+	})
+
+	return kept, nil
+}
+
+// DismissMovie godoc
+//
+// @Summary Mark a movie as not interesting
+// @Description The movie is left out of recommendations for the authenticated user
+// @Tags recommendations
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Security BearerAuth
+// @Success 201 {object} map[string]string "Created | Example {"message": "movie successfully dismissed"}"
+// @Success 200 {object} map[string]string "The movie is already dismissed | Example {"message": "movie successfully dismissed"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/dismiss [put]
+func (app *application) dismissMovieHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	dismissed, err := app.models.Dismissals.DismissMovie(app.contextGetUser(r).ID, id)
+	app.writeDismissed(w, r, "movie", dismissed, err)
+}
+
+// UndismissMovie godoc
+//
+// @Summary Undo marking a movie as not interesting
+// @Tags recommendations
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "movie dismissal successfully removed"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/dismiss [delete]
+func (app *application) undismissMovieHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Dismissals.UndismissMovie(app.contextGetUser(r).ID, id)
+	app.writeUndismissed(w, r, "movie", err)
+}
+
+// DismissGenre godoc
+//
+// @Summary Ask for less of a genre
+// @Description Recommended movies of the genre get lower scores for the authenticated user
+// @Tags recommendations
+// @Produce json
+// @Param genreID path int true "Genre ID"
+// @Security BearerAuth
+// @Success 201 {object} map[string]string "Created | Example {"message": "genre successfully dismissed"}"
+// @Success 200 {object} map[string]string "The genre is already dismissed | Example {"message": "genre successfully dismissed"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres/{genreID}/dismiss [put]
+func (app *application) dismissGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "genreID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	dismissed, err := app.models.Dismissals.DismissGenre(app.contextGetUser(r).ID, id)
+	app.writeDismissed(w, r, "genre", dismissed, err)
+}
+
+// UndismissGenre godoc
+//
+// @Summary Undo asking for less of a genre
+// @Tags recommendations
+// @Produce json
+// @Param genreID path int true "Genre ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "genre dismissal successfully removed"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres/{genreID}/dismiss [delete]
+func (app *application) undismissGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "genreID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Dismissals.UndismissGenre(app.contextGetUser(r).ID, id)
+	app.writeUndismissed(w, r, "genre", err)
+}
+
+// writeDismissed responds to a dismissal of the kind, 201 if it is new and 200 if it already existed.
+func (app *application) writeDismissed(w http.ResponseWriter, r *http.Request, kind string, dismissed bool, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	status := http.StatusOK
+	if dismissed {
+		status = http.StatusCreated
+	}
+
+	err = app.writeJSON(w, status, envelope{"message": kind + " successfully dismissed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// writeUndismissed responds to removing a dismissal of the kind.
+func (app *application) writeUndismissed(w http.ResponseWriter, r *http.Request, kind string, err error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": kind + " dismissal successfully removed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ListDismissals godoc
+//
+// @Summary List my dismissals
+// @Description Returns movies and genres the authenticated user is not interested in, the latest first
+// @Tags recommendations
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} data.Dismissals
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/dismissals [get]
+func (app *application) listDismissalsHandler(w http.ResponseWriter, r *http.Request) {
+	dismissals, err := app.models.Dismissals.GetAll(app.contextGetUser(r).ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"dismissals": dismissals}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
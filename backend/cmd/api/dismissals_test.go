@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestSuppressDismissed(t *testing.T) {
+	app := newTestApplication(t)
+
+	mockDismissals := mocks.NewDismissalsInterface(t)
+	mockDismissals.On("Suppressions", int64(1), []int64{1, 2, 3, 4}).Return(map[int64]data.Suppression{
+		1: {Dismissed: true},
+		2: {DismissedGenres: 1},
+		4: {DismissedGenres: 2},
+	}, nil).Once()
+
+	app.models.Dismissals = mockDismissals
+
+	recommendations := []*MovieRecommendation{
+		{Movie: &data.Movie{ID: 1, Title: "Se7en"}, Score: 0.9},
+		{Movie: &data.Movie{ID: 2, Title: "Alien"}, Score: 0.8},
+		{Movie: &data.Movie{ID: 3, Title: "Heat"}, Score: 0.7},
+		{Movie: &data.Movie{ID: 4, Title: "The Thing"}, Score: 0.6},
+	}
+
+	got, err := app.suppressDismissed(1, recommendations)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var gotTitles []string
+	for _, recommendation := range got {
+		gotTitles = append(gotTitles, recommendation.Movie.Title)
+	}
+
+	assert.Equal(t, []string{"Heat", "Alien", "The Thing"}, gotTitles, "dismissed movie should be left out and the rest reordered")
+	assert.InDelta(t, 0.4, got[1].Score, 1e-9, "score should be halved for one dismissed genre")
+	assert.InDelta(t, 0.15, got[2].Score, 1e-9, "score should be quartered for two dismissed genres")
+}
+
+func TestDismissMovieHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockDismissals := mocks.NewDismissalsInterface(t)
+	mockDismissals.On("DismissMovie", int64(1), int64(1)).Return(true, nil).Maybe()
+	mockDismissals.On("DismissMovie", int64(1), int64(2)).Return(false, nil).Maybe()
+	mockDismissals.On("DismissMovie", int64(1), int64(3)).Return(false, data.ErrRecordNotFound).Maybe()
+	mockDismissals.On("UndismissMovie", int64(1), int64(2)).Return(data.ErrRecordNotFound).Maybe()
+
+	app.models.Dismissals = mockDismissals
+
+	tests := []struct {
+		name     string
+		method   string
+		urlPath  string
+		wantCode int
+	}{
+		{
+			name:     "New dismissal",
+			method:   http.MethodPut,
+			urlPath:  "/v1/movie/1/dismiss",
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Already dismissed",
+			method:   http.MethodPut,
+			urlPath:  "/v1/movie/2/dismiss",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Non-existent movie",
+			method:   http.MethodPut,
+			urlPath:  "/v1/movie/3/dismiss",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Undismiss movie not dismissed",
+			method:   http.MethodDelete,
+			urlPath:  "/v1/movie/2/dismiss",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.send(t, tt.method, tt.urlPath, nil, nil)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestDismissGenreHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockDismissals := mocks.NewDismissalsInterface(t)
+	mockDismissals.On("DismissGenre", int64(1), int64(5)).Return(true, nil).Once()
+	mockDismissals.On("UndismissGenre", int64(1), int64(5)).Return(nil).Once()
+
+	app.models.Dismissals = mockDismissals
+
+	code, _, _ := ts.send(t, http.MethodPut, "/v1/genres/5/dismiss", nil, nil)
+	assert.Equal(t, http.StatusCreated, code, "status code should be 201")
+
+	code, _, _ = ts.send(t, http.MethodDelete, "/v1/genres/5/dismiss", nil, nil)
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type DuplicateMovieResponse struct {
+	Error string     `json:"error" example:"a likely duplicate of the movie already exists"`
+	Movie data.Movie `json:"movie"`
+}
+
+type movieMergeInput struct {
+	SourceID int64 `json:"source_id" example:"2"`
+}
+
+func (app *application) duplicateMovieResponse(w http.ResponseWriter, r *http.Request, duplicate *data.Movie) {
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/movie/%d", duplicate.ID))
+
+	data := envelope{
+		"error": "a likely duplicate of the movie already exists",
+		"movie": duplicate,
+	}
+
+	err := app.writeJSON(w, http.StatusConflict, data, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// redirectMergedMovie redirects to the movie the requested one was merged into,
+// or responds with 404 Not Found if the movie was not merged.
+func (app *application) redirectMergedMovie(w http.ResponseWriter, r *http.Request, id int64) {
+	newID, err := app.models.Movies.GetRedirect(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	location := fmt.Sprintf("/v1/movie/%d", newID)
+	if r.URL.RawQuery != "" {
+		location += "?" + r.URL.RawQuery
+	}
+
+	http.Redirect(w, r, location, http.StatusMovedPermanently)
+}
+
+// MergeMovie godoc
+//
+// @Summary Merge a duplicate movie into another
+// @Description Folds the source movie into the movie from the path (admin only): dependent rows are moved,
+// @Description the source movie is deleted and its id redirects to the target movie
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param movieID path int true "Target movie ID"
+// @Param merge body movieMergeInput true "Merge payload"
+// @Security BearerAuth
+// @Success 200 {object} data.Movie
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/merge [post]
+func (app *application) mergeMovieHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input movieMergeInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.SourceID, validation.Required, validation.Min(int64(1)), validation.NotIn(id).Error("must differ from the target movie")),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	movie, err := app.models.Movies.Merge(input.SourceID, id, app.contextGetUser(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
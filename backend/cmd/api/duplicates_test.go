@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestPostDuplicateMovieHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockGenres := mocks.NewGenresInterface(t)
+
+	existing := data.Movie{ID: 1, Title: "The Matrix", Year: 1999, Runtime: 136, Genres: []string{"Action"}, Version: 1}
+
+	mockMovies.On("FindDuplicate", mock.MatchedBy(func(m *data.Movie) bool {
+		return m.ExternalIDs != nil && m.ExternalIDs.TMDB == 603
+	})).Return(&existing, nil).Once()
+	mockMovies.On("FindDuplicate", mock.Anything).Return(&existing, nil).Once()
+	mockMovies.On("Insert", mock.Anything, int64(1)).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*data.Movie)
+		arg.ID = 2
+		arg.Version = 1
+	}).Once()
+	mockGenres.On("Resolve", []string{"Action"}).Return([]string{"Action"}, nil, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Genres = mockGenres
+
+	body := `{"title": "the matrix!", "year": 1999, "runtime": 136, "genres": ["Action"]}`
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+		wantID   int64
+	}{
+		{
+			name:     "Duplicate by external id",
+			urlPath:  "/v1/movie",
+			body:     `{"title": "Matrix", "year": 2000, "runtime": 136, "genres": ["Action"], "external_ids": {"tmdb": 603}}`,
+			wantCode: http.StatusConflict,
+			wantID:   1,
+		},
+		{
+			name:     "Invalid external id",
+			urlPath:  "/v1/movie",
+			body:     `{"title": "Matrix", "year": 2000, "runtime": 136, "genres": ["Action"], "external_ids": {"imdb": "603"}}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Duplicate",
+			urlPath:  "/v1/movie",
+			wantCode: http.StatusConflict,
+			wantID:   1,
+		},
+		{
+			name:     "Forced",
+			urlPath:  "/v1/movie?force=true",
+			wantCode: http.StatusCreated,
+			wantID:   2,
+		},
+		{
+			name:     "Invalid force",
+			urlPath:  "/v1/movie?force=maybe",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody := body
+			if tt.body != "" {
+				reqBody = tt.body
+			}
+
+			code, _, respBody := ts.post(t, tt.urlPath, strings.NewReader(reqBody))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantID != 0 {
+				var resp map[string]json.RawMessage
+
+				err := json.Unmarshal(respBody, &resp)
+				assert.NoError(t, err)
+
+				var movie data.Movie
+
+				err = json.Unmarshal(resp["movie"], &movie)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantID, movie.ID, "movie id should match")
+			}
+		})
+	}
+}
+
+func TestGetMergedMovieHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	mockMovies.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound)
+	mockMovies.On("GetRedirect", int64(2)).Return(int64(1), nil)
+
+	app.models.Movies = mockMovies
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/movie/2?lang=de", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := testAuth(1, true, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	client := ts.Client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusMovedPermanently, resp.StatusCode, "status code should be 301")
+	assert.Equal(t, "/v1/movie/1?lang=de", resp.Header.Get("Location"), "location should point to merged movie")
+}
+
+func TestMergeMovieHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	merged := data.Movie{ID: 1, Title: "The Matrix", Year: 1999, Runtime: 136, Genres: []string{"Action"}, Version: 2}
+
+	mockMovies.On("Merge", int64(2), int64(1), int64(1)).Return(&merged, nil)
+	mockMovies.On("Merge", int64(3), int64(1), int64(1)).Return(nil, data.ErrRecordNotFound)
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Valid merge",
+			urlPath:  "/v1/movie/1/merge",
+			body:     `{"source_id": 2}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Missing source",
+			urlPath:  "/v1/movie/1/merge",
+			body:     `{"source_id": 3}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Merge into itself",
+			urlPath:  "/v1/movie/1/merge",
+			body:     `{"source_id": 1}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Empty body",
+			urlPath:  "/v1/movie/1/merge",
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.post(t, tt.urlPath, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
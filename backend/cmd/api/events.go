@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+const (
+	// maxEventsPerRequest limits events a client sends in one batch.
+	maxEventsPerRequest = 100
+	// maxEventAge is how late clients may send events, e.g. after being offline.
+	maxEventAge = 7 * 24 * time.Hour
+	// maxEventAttempts is how many times a batch is written before its events are dropped.
+	maxEventAttempts = 3
+)
+
+type eventsInput struct {
+	Events []*data.Event `json:"events"`
+}
+
+// eventBatch is a batch of events with the number of times writing it failed.
+type eventBatch struct {
+	events   []*data.Event
+	attempts int
+}
+
+// eventBuffer keeps feedback events in memory until the flush worker writes them in batches.
+type eventBuffer struct {
+	mu        sync.Mutex
+	events    []*data.Event
+	size      int
+	batchSize int
+	// batches that failed to be written, retried on the next flush
+	failed []eventBatch
+	// full wakes the flush worker up as soon as a batch is ready
+	full chan struct{}
+}
+
+func newEventBuffer(size, batchSize int) *eventBuffer {
+	return &eventBuffer{
+		size:      size,
+		batchSize: batchSize,
+		full:      make(chan struct{}, 1),
+	}
+}
+
+// add buffers all the events or, if they don't fit, none of them and reports whether they were buffered.
+func (b *eventBuffer) add(events []*data.Event) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.events)+len(events) > b.size {
+		return false
+	}
+
+	b.events = append(b.events, events...)
+
+	if len(b.events) >= b.batchSize {
+		select {
+		case b.full <- struct{}{}:
+		default:
+		}
+	}
+
+	return true
+}
+
+// take empties the buffer and returns the events it had.
+func (b *eventBuffer) take() []*data.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := b.events
+	b.events = nil
+
+	return events
+}
+
+// retry keeps the batch for the next flush.
+func (b *eventBuffer) retry(batch eventBatch) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failed = append(b.failed, batch)
+}
+
+// takeFailed returns the batches to retry and forgets them.
+func (b *eventBuffer) takeFailed() []eventBatch {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	batches := b.failed
+	b.failed = nil
+
+	return batches
+}
+
+// flushEvents writes buffered events every flush interval or once a batch is ready.
+// When shutdown is closed it writes the events left in the buffer, retrying failed batches
+// until they are saved or dropped, and returns. The server has stopped accepting requests
+// by then, so nothing is added later.
+func (app *application) flushEvents(shutdown <-chan struct{}) {
+	ticker := time.NewTicker(app.config.events.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdown:
+			for app.writeEvents() {
+			}
+
+			return
+		case <-ticker.C:
+			app.writeEvents()
+		case <-app.events.full:
+			app.writeEvents()
+		}
+	}
+}
+
+// writeEvents saves the failed batches and the buffered events in batches, and reports whether
+// failed batches are left for the next flush. A batch is dropped with an error after maxEventAttempts,
+// so a batch the database rejects doesn't block the buffer.
+func (app *application) writeEvents() bool {
+	batches := app.events.takeFailed()
+	events := app.events.take()
+
+	for start := 0; start < len(events); start += app.events.batchSize {
+		batches = append(batches, eventBatch{events: events[start:min(start+app.events.batchSize, len(events))]})
+	}
+
+	retrying := false
+
+	for _, batch := range batches {
+		saved, err := app.models.Events.InsertBatch(batch.events)
+		if err != nil {
+			batch.attempts++
+
+			if batch.attempts < maxEventAttempts {
+				app.logger.Warn("cannot save feedback events, retrying: "+err.Error(),
+					slog.Int("batch", len(batch.events)), slog.Int("attempt", batch.attempts))
+				app.events.retry(batch)
+				retrying = true
+
+				continue
+			}
+
+			app.logger.Error("cannot save feedback events: "+err.Error(), slog.Int("dropped", len(batch.events)))
+
+			continue
+		}
+
+		app.logger.Debug("feedback events saved", slog.Int64("saved", saved), slog.Int("batch", len(batch.events)))
+	}
+
+	return retrying
+}
+
+// PostEvents godoc
+//
+// @Summary Send feedback events
+// @Description Accepts up to 100 implicit feedback events of the authenticated user: click, detail_view,
+// @Description trailer_play and dwell with dwell_ms. occurred_at is the client time of the event, up to 7 days ago.
+// @Description Events are saved in the background, events resent with the same dedupe_key and events
+// @Description about unknown movies are skipped
+// @Tags events
+// @Accept json
+// @Produce json
+// @Param events body eventsInput true "Feedback events"
+// @Security BearerAuth
+// @Success 202 {object} map[string]int "Accepted | Example {"accepted": 2}"
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "events: (0: (type: must be a valid value.).)."}"
+// @Failure 503 {object} map[string]string "Service Unavailable | Example {"error": "too many events are waiting to be saved, please retry later"}"
+// @Router /events [post]
+func (app *application) postEventsHandler(w http.ResponseWriter, r *http.Request) {
+	var input eventsInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	userID := app.contextGetUser(r).ID
+	now := time.Now()
+
+	for _, event := range input.Events {
+		if event != nil {
+			event.UserID = userID
+			event.DedupeKey = strings.TrimSpace(event.DedupeKey)
+		}
+	}
+
+	err = validation.Validate(input.Events, validation.Required, validation.Length(1, maxEventsPerRequest),
+		validation.Each(validation.NotNil, validation.By(func(value interface{}) error {
+			event := value.(*data.Event)
+
+			dwellRules := []validation.Rule{validation.Nil}
+			if event.Type == data.EventDwell {
+				dwellRules = []validation.Rule{validation.NotNil, validation.Min(int32(1))}
+			}
+
+			return validation.ValidateStruct(event,
+				validation.Field(&event.Type, validation.Required, validation.In(data.EventTypes...)),
+				validation.Field(&event.MovieID, validation.Required, validation.Min(int64(1))),
+				validation.Field(&event.DwellMS, dwellRules...),
+				validation.Field(&event.OccurredAt, validation.Required, validation.By(func(value interface{}) error {
+					occurredAt := value.(time.Time)
+
+					switch {
+					// a small allowance for clients with clocks running ahead
+					case occurredAt.After(now.Add(time.Minute)):
+						return errors.New("must not be in the future")
+					case occurredAt.Before(now.Add(-maxEventAge)):
+						return errors.New("must not be older than 7 days")
+					}
+
+					return nil
+				})),
+				validation.Field(&event.DedupeKey, validation.Required, validation.RuneLength(1, 100)),
+			)
+		})),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("events: %w", err))
+		return
+	}
+
+	if !app.events.add(input.Events) {
+		app.eventBufferFullResponse(w, r)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusAccepted, envelope{"accepted": len(input.Events)}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
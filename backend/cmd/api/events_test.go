@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestPostEventsHandler(t *testing.T) {
+	app := newTestApplication(t)
+	app.events = newEventBuffer(3, 100)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	old := time.Now().Add(-8 * 24 * time.Hour).UTC().Format(time.RFC3339)
+	future := time.Now().Add(time.Hour).UTC().Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{
+			name: "Valid events",
+			body: fmt.Sprintf(`{"events": [
+				{"type": "click", "movie_id": 1, "occurred_at": "%[1]s", "dedupe_key": "a"},
+				{"type": "dwell", "movie_id": 1, "dwell_ms": 45000, "occurred_at": "%[1]s", "dedupe_key": "b"}
+			]}`, now),
+			wantCode: http.StatusAccepted,
+		},
+		{
+			name:     "No events",
+			body:     `{"events": []}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Unknown type",
+			body:     fmt.Sprintf(`{"events": [{"type": "hover", "movie_id": 1, "occurred_at": "%s", "dedupe_key": "c"}]}`, now),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Dwell without duration",
+			body:     fmt.Sprintf(`{"events": [{"type": "dwell", "movie_id": 1, "occurred_at": "%s", "dedupe_key": "c"}]}`, now),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Click with duration",
+			body:     fmt.Sprintf(`{"events": [{"type": "click", "movie_id": 1, "dwell_ms": 10, "occurred_at": "%s", "dedupe_key": "c"}]}`, now),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing dedupe key",
+			body:     fmt.Sprintf(`{"events": [{"type": "click", "movie_id": 1, "occurred_at": "%s", "dedupe_key": " "}]}`, now),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Event in the future",
+			body:     fmt.Sprintf(`{"events": [{"type": "click", "movie_id": 1, "occurred_at": "%s", "dedupe_key": "c"}]}`, future),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Event too old",
+			body:     fmt.Sprintf(`{"events": [{"type": "click", "movie_id": 1, "occurred_at": "%s", "dedupe_key": "c"}]}`, old),
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Buffer full",
+			body: fmt.Sprintf(`{"events": [
+				{"type": "click", "movie_id": 1, "occurred_at": "%[1]s", "dedupe_key": "c"},
+				{"type": "click", "movie_id": 2, "occurred_at": "%[1]s", "dedupe_key": "d"}
+			]}`, now),
+			wantCode: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.post(t, "/v1/events", strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+
+	events := app.events.take()
+	if assert.Len(t, events, 2, "only valid events should be buffered") {
+		assert.Equal(t, int64(1), events[0].UserID, "events should belong to the authenticated user")
+	}
+}
+
+func TestFlushEvents(t *testing.T) {
+	app := newTestApplication(t)
+	app.config.events.flushInterval = time.Hour
+	app.events = newEventBuffer(10, 2)
+
+	mockEvents := mocks.NewEventsInterface(t)
+	mockEvents.On("InsertBatch", mock.MatchedBy(func(events []*data.Event) bool { return len(events) == 2 })).
+		Return(int64(2), nil).Once()
+	mockEvents.On("InsertBatch", mock.MatchedBy(func(events []*data.Event) bool { return len(events) == 1 })).
+		Return(int64(1), nil).Once()
+
+	app.models.Events = mockEvents
+
+	app.events.add([]*data.Event{{DedupeKey: "a"}, {DedupeKey: "b"}, {DedupeKey: "c"}})
+
+	// the buffer is written before the worker stops
+	shutdown := make(chan struct{})
+	close(shutdown)
+
+	app.flushEvents(shutdown)
+
+	assert.Empty(t, app.events.take(), "buffer should be empty after shutdown")
+}
+
+func TestFlushEventsRetriesFailedBatches(t *testing.T) {
+	tests := []struct {
+		name      string
+		failures  int
+		wantCalls int
+	}{
+		{name: "Saved on retry", failures: 1, wantCalls: 2},
+		{name: "Dropped after max attempts", failures: maxEventAttempts, wantCalls: maxEventAttempts},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApplication(t)
+			app.config.events.flushInterval = time.Hour
+			app.events = newEventBuffer(10, 2)
+
+			mockEvents := mocks.NewEventsInterface(t)
+			mockEvents.On("InsertBatch", mock.Anything).Return(int64(0), errors.New("connection refused")).Times(tt.failures)
+			if tt.wantCalls > tt.failures {
+				mockEvents.On("InsertBatch", mock.Anything).Return(int64(2), nil).Once()
+			}
+
+			app.models.Events = mockEvents
+
+			app.events.add([]*data.Event{{DedupeKey: "a"}, {DedupeKey: "b"}})
+
+			shutdown := make(chan struct{})
+			close(shutdown)
+
+			app.flushEvents(shutdown)
+
+			mockEvents.AssertNumberOfCalls(t, "InsertBatch", tt.wantCalls)
+			assert.Empty(t, app.events.takeFailed(), "no batch should be left for a retry")
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+var imdbIDRX = regexp.MustCompile(`^tt\d{7,10}$`)
+
+// validateExternalIDs checks the format of external ids, unset ids are valid.
+func validateExternalIDs(ids *data.ExternalIDs) error {
+	return validation.ValidateStruct(ids,
+		validation.Field(&ids.IMDb, validation.Match(imdbIDRX)),
+		validation.Field(&ids.TMDB, validation.Min(int64(0)), validation.Max(int64(math.MaxInt32))),
+		validation.Field(&ids.MovieLens, validation.Min(int64(0)), validation.Max(int64(math.MaxInt32))),
+	)
+}
+
+// LookupMovie godoc
+//
+// @Summary Find a movie by external id
+// @Description Returns the movie with the given IMDb, TMDB or MovieLens id, exactly one of the ids must be set.
+// @Description The movie is shown like by GET /movie/{movieID}
+// @Tags movies
+// @Produce json
+// @Param imdb query string false "IMDb id" example(tt0111161)
+// @Param tmdb query int false "TMDB id"
+// @Param movielens query int false "MovieLens id"
+// @Param lang query string false "Language of the title and overview, overrides Accept-Language"
+// @Param Accept-Language header string false "Preferred languages, translated title is used if there is one"
+// @Param If-None-Match header string false "ETag of the cached movie, 304 is returned if it is still current"
+// @Security BearerAuth
+// @Success 200 {object} data.Movie
+// @Header 200 {string} ETag "Movie entity tag"
+// @Success 304 "Not Modified"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "exactly one of imdb, tmdb and movielens must be provided"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/lookup [get]
+func (app *application) lookupMovieHandler(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	var source, externalID string
+
+	for _, key := range []string{data.ExternalIMDb, data.ExternalTMDB, data.ExternalMovieLens} {
+		if !qs.Has(key) {
+			continue
+		}
+
+		if source != "" {
+			source = ""
+			break
+		}
+
+		source, externalID = key, qs.Get(key)
+	}
+
+	if source == "" {
+		app.failedValidationResponse(w, r, errors.New("exactly one of imdb, tmdb and movielens must be provided"))
+		return
+	}
+
+	var ids data.ExternalIDs
+
+	var err error
+
+	switch source {
+	case data.ExternalIMDb:
+		ids.IMDb = externalID
+		err = validation.Validate(externalID, validation.Required, validation.Match(imdbIDRX))
+	default:
+		var n int64
+
+		n, err = strconv.ParseInt(externalID, 10, 64)
+		if err != nil {
+			err = errors.New("must be an integer value")
+			break
+		}
+
+		if source == data.ExternalTMDB {
+			ids.TMDB = n
+		} else {
+			ids.MovieLens = n
+		}
+
+		err = validation.Validate(n, validation.Min(int64(1)), validation.Max(int64(math.MaxInt32)))
+	}
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("%s: %w", source, err))
+		return
+	}
+
+	languages, err := app.readLanguages(qs, "lang", r.Header.Get("Accept-Language"))
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("lang: %w", err))
+		return
+	}
+
+	movie, err := app.models.Movies.GetByExternalID(ids)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	app.writeMovie(w, r, movie, languages)
+}
+
+// PutExternalIDs godoc
+//
+// @Summary Set movie external ids
+// @Description Replaces IMDb, TMDB and MovieLens ids of the movie, omitted ids are removed.
+// @Description Every external id can belong to one movie only.
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param external_ids body data.ExternalIDs true "External ids payload"
+// @Param If-Match header string false "ETag of the edited movie version (required unless disabled in config)"
+// @Security BearerAuth
+// @Success 200 {object} data.Movie
+// @Header 200 {string} ETag "New movie version as entity tag"
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 412 {object} map[string]string "Precondition Failed | Example {"error": "the resource has been modified since you fetched it, please fetch it again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "imdb: is already assigned to another movie"}"
+// @Failure 428 {object} map[string]string "Precondition Required | Example {"error": "If-Match header with the resource ETag is required"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/external-ids [put]
+func (app *application) putExternalIDsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input data.ExternalIDs
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	err = validateExternalIDs(&input)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	movie, err := app.models.Movies.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	if !app.preconditionMet(w, r, movie.Version) {
+		return
+	}
+
+	err = app.models.Movies.SetExternalIDs(movie, input, app.contextGetUser(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateExternalID):
+			app.failedValidationResponse(w, r, err)
+		case errors.Is(err, data.ErrEditConflict) && r.Header.Get("If-Match") != "":
+			app.preconditionFailedResponse(w, r)
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", movieETag(movie))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
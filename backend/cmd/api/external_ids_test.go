@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestLookupMovieHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	movie := data.Movie{
+		ID:          1,
+		Title:       "The Shawshank Redemption",
+		Year:        1994,
+		Runtime:     142,
+		Genres:      []string{"Drama"},
+		Version:     1,
+		ExternalIDs: &data.ExternalIDs{IMDb: "tt0111161", TMDB: 278},
+	}
+
+	mockMovies.On("GetByExternalID", data.ExternalIDs{IMDb: "tt0111161"}).Return(func(data.ExternalIDs) (*data.Movie, error) {
+		m := movie
+		return &m, nil
+	})
+	mockMovies.On("GetByExternalID", data.ExternalIDs{TMDB: 278}).Return(func(data.ExternalIDs) (*data.Movie, error) {
+		m := movie
+		return &m, nil
+	})
+	mockMovies.On("GetByExternalID", data.ExternalIDs{MovieLens: 318}).Return(nil, data.ErrRecordNotFound)
+
+	app.models.Movies = mockMovies
+	app.models.Watchlist = newWatchlistMock(t)
+	app.models.History = newHistoryMock(t)
+
+	tests := []struct {
+		name        string
+		urlPath     string
+		ifNoneMatch string
+		wantCode    int
+		wantBody    *data.Movie
+	}{
+		{
+			name:        "Current ETag",
+			urlPath:     "/v1/movie/lookup?imdb=tt0111161",
+			ifNoneMatch: movieETag(&movie),
+			wantCode:    http.StatusNotModified,
+		},
+		{
+			name:     "TMDB id out of range",
+			urlPath:  "/v1/movie/lookup?tmdb=99999999999",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "IMDb id",
+			urlPath:  "/v1/movie/lookup?imdb=tt0111161",
+			wantCode: http.StatusOK,
+			wantBody: &movie,
+		},
+		{
+			name:     "TMDB id with leading zero",
+			urlPath:  "/v1/movie/lookup?tmdb=0278",
+			wantCode: http.StatusOK,
+			wantBody: &movie,
+		},
+		{
+			name:     "Unknown MovieLens id",
+			urlPath:  "/v1/movie/lookup?movielens=318",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Invalid IMDb id",
+			urlPath:  "/v1/movie/lookup?imdb=111161",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid TMDB id",
+			urlPath:  "/v1/movie/lookup?tmdb=abc",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Several ids",
+			urlPath:  "/v1/movie/lookup?imdb=tt0111161&tmdb=278",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "No id",
+			urlPath:  "/v1/movie/lookup",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.ifNoneMatch != "" {
+				header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			code, _, body := ts.send(t, http.MethodGet, tt.urlPath, header, nil)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != nil {
+				var resp map[string]data.Movie
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.wantBody, resp["movie"], "movie should be equal")
+			}
+		})
+	}
+}
+
+func TestPutExternalIDsHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+		return &data.Movie{ID: 1, Title: "The Shawshank Redemption", Year: 1994, Genres: []string{"Drama"}, Version: 1}, nil
+	}).Maybe()
+	mockMovies.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound).Maybe()
+	mockMovies.On("SetExternalIDs", mock.Anything, data.ExternalIDs{IMDb: "tt0111161", TMDB: 278}, int64(1)).Return(nil).Run(func(args mock.Arguments) {
+		movie := args.Get(0).(*data.Movie)
+		ids := args.Get(1).(data.ExternalIDs)
+		movie.Version++
+		movie.ExternalIDs = &ids
+	}).Maybe()
+	mockMovies.On("SetExternalIDs", mock.Anything, data.ExternalIDs{TMDB: 13}, int64(1)).Return(fmt.Errorf("tmdb: %w", data.ErrDuplicateExternalID)).Maybe()
+
+	app.models.Movies = mockMovies
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+		wantIDs  *data.ExternalIDs
+	}{
+		{
+			name:     "Valid ids",
+			urlPath:  "/v1/movie/1/external-ids",
+			body:     `{"imdb": "tt0111161", "tmdb": 278}`,
+			wantCode: http.StatusOK,
+			wantIDs:  &data.ExternalIDs{IMDb: "tt0111161", TMDB: 278},
+		},
+		{
+			name:     "Id of another movie",
+			urlPath:  "/v1/movie/1/external-ids",
+			body:     `{"tmdb": 13}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid IMDb id",
+			urlPath:  "/v1/movie/1/external-ids",
+			body:     `{"imdb": "0111161"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Negative TMDB id",
+			urlPath:  "/v1/movie/1/external-ids",
+			body:     `{"tmdb": -1}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Non-existent movie",
+			urlPath:  "/v1/movie/2/external-ids",
+			body:     `{"tmdb": 278}`,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, header, body := ts.send(t, http.MethodPut, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantIDs != nil {
+				var resp map[string]data.Movie
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.wantIDs, resp["movie"].ExternalIDs, "external ids should be equal")
+				assert.True(t, strings.HasPrefix(header.Get("ETag"), `"2-`), "etag should start with the new version")
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type FollowsResponse struct {
+	Users    []data.Follow `json:"users"`
+	Metadata data.Metadata `json:"metadata"`
+}
+
+// FollowUser godoc
+//
+// @Summary Follow a user
+// @Description Adds the user's activity to the feed of the authenticated user, following again changes nothing
+// @Tags social
+// @Produce json
+// @Param userID path int true "User ID"
+// @Security BearerAuth
+// @Success 201 {object} map[string]string "Created | Example {"message": "user successfully followed"}"
+// @Success 200 {object} map[string]string "The user is already followed | Example {"message": "user successfully followed"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "user: users can't follow themselves"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/{userID}/follow [put]
+func (app *application) followUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "userID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	followed, err := app.models.Follows.Follow(app.contextGetUser(r).ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, data.ErrSelfFollow):
+			app.failedValidationResponse(w, r, fmt.Errorf("user: %w", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	status := http.StatusOK
+	if followed {
+		status = http.StatusCreated
+	}
+
+	err = app.writeJSON(w, status, envelope{"message": "user successfully followed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UnfollowUser godoc
+//
+// @Summary Unfollow a user
+// @Tags social
+// @Produce json
+// @Param userID path int true "User ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "user successfully unfollowed"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/{userID}/follow [delete]
+func (app *application) unfollowUserHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "userID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Follows.Unfollow(app.contextGetUser(r).ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "user successfully unfollowed"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ListFollowing godoc
+//
+// @Summary List users I follow
+// @Tags social
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by followed_at, name (prefix with - for descending)" default(-followed_at)
+// @Security BearerAuth
+// @Success 200 {object} FollowsResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/following [get]
+func (app *application) listFollowingHandler(w http.ResponseWriter, r *http.Request) {
+	app.listFollows(w, r, app.models.Follows.GetFollowing)
+}
+
+// ListFollowers godoc
+//
+// @Summary List my followers
+// @Tags social
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by followed_at, name (prefix with - for descending)" default(-followed_at)
+// @Security BearerAuth
+// @Success 200 {object} FollowsResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/followers [get]
+func (app *application) listFollowersHandler(w http.ResponseWriter, r *http.Request) {
+	app.listFollows(w, r, app.models.Follows.GetFollowers)
+}
+
+// listFollows responds with a page of the authenticated user's follows loaded by getAll.
+func (app *application) listFollows(w http.ResponseWriter, r *http.Request,
+	getAll func(int64, data.Filters) ([]*data.Follow, data.Metadata, error)) {
+	filters, err := app.readFilters(r.URL.Query(), "-followed_at", []string{"followed_at", "name", "-followed_at", "-name"})
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	users, metadata, err := getAll(app.contextGetUser(r).ID, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"users": users, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GetPrivacy godoc
+//
+// @Summary Get my privacy settings
+// @Tags social
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} data.Privacy
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/privacy [get]
+func (app *application) getPrivacyHandler(w http.ResponseWriter, r *http.Request) {
+	privacy, err := app.models.Users.GetPrivacy(app.contextGetUser(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"privacy": privacy}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdatePrivacy godoc
+//
+// @Summary Update my privacy settings
+// @Description activity_visibility tells who sees your ratings, reviews and list updates: everyone (public),
+// @Description your followers (followers) or nobody (private). It applies to activities recorded before the change too
+// @Tags social
+// @Accept json
+// @Produce json
+// @Param privacy body data.Privacy true "Privacy settings"
+// @Security BearerAuth
+// @Success 200 {object} data.Privacy
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"activity_visibility": "must be a valid value"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/privacy [put]
+func (app *application) updatePrivacyHandler(w http.ResponseWriter, r *http.Request) {
+	var privacy data.Privacy
+
+	err := app.readJSON(w, r, &privacy)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&privacy,
+		validation.Field(&privacy.ActivityVisibility, validation.Required, validation.In(data.ActivityVisibilities...)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Users.UpdatePrivacy(app.contextGetUser(r).ID, &privacy)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"privacy": privacy}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
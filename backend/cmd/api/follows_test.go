@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestFollowUserHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockFollows := mocks.NewFollowsInterface(t)
+	mockFollows.On("Follow", int64(1), int64(2)).Return(true, nil).Maybe()
+	mockFollows.On("Follow", int64(1), int64(3)).Return(false, nil).Maybe()
+	mockFollows.On("Follow", int64(1), int64(1)).Return(false, data.ErrSelfFollow).Maybe()
+	mockFollows.On("Follow", int64(1), int64(4)).Return(false, data.ErrRecordNotFound).Maybe()
+
+	app.models.Follows = mockFollows
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+	}{
+		{
+			name:     "New follow",
+			urlPath:  "/v1/users/2/follow",
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Already followed",
+			urlPath:  "/v1/users/3/follow",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Self follow",
+			urlPath:  "/v1/users/1/follow",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Non-existent user",
+			urlPath:  "/v1/users/4/follow",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Invalid ID",
+			urlPath:  "/v1/users/abc/follow",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.send(t, http.MethodPut, tt.urlPath, nil, nil)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestListFollowingHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockFollows := mocks.NewFollowsInterface(t)
+	mockFollows.On("GetFollowing", int64(1), mock.MatchedBy(func(f data.Filters) bool { return f.Sort == "name" })).
+		Return([]*data.Follow{{UserID: 2, Name: "Anna"}}, data.Metadata{}, nil).Once()
+
+	app.models.Follows = mockFollows
+
+	code, _, body := ts.get(t, "/v1/users/me/following?sort=name")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+	assert.Contains(t, string(body), `"name": "Anna"`)
+
+	code, _, _ = ts.get(t, "/v1/users/me/following?sort=email")
+	assert.Equal(t, http.StatusUnprocessableEntity, code, "status code should be 422")
+}
+
+func TestUpdatePrivacyHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockUsers := mocks.NewUsersInterface(t)
+	mockUsers.On("UpdatePrivacy", int64(1), &data.Privacy{ActivityVisibility: data.ActivityFollowers}).Return(nil).Once()
+
+	app.models.Users = mockUsers
+
+	token, err := testAuth(1, false, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		inactive bool
+		wantCode int
+	}{
+		{
+			name:     "Followers only",
+			body:     `{"activity_visibility": "followers"}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Invalid visibility",
+			body:     `{"activity_visibility": "friends"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing visibility",
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Inactive user",
+			body:     `{"activity_visibility": "private"}`,
+			inactive: true,
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if tt.inactive {
+				header.Set("Authorization", "Bearer "+token)
+			}
+
+			code, _, _ := ts.send(t, http.MethodPut, "/v1/users/me/privacy", header, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
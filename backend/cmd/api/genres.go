@@ -0,0 +1,338 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/validate"
+)
+
+type genreInput struct {
+	Name    string   `json:"name" example:"Science Fiction"`
+	Aliases []string `json:"aliases" example:"Sci-Fi,SF"`
+}
+
+type genreMergeInput struct {
+	SourceID int64 `json:"source_id" example:"2"`
+}
+
+// validateGenre checks genre name and aliases, aliases must be unique (case insensitive) and differ from the name.
+func validateGenre(genre *data.Genre) error {
+	names := make([]string, 0, len(genre.Aliases)+1)
+	for _, name := range append([]string{genre.Name}, genre.Aliases...) {
+		names = append(names, strings.ToLower(name))
+	}
+
+	return validation.ValidateStruct(genre,
+		validation.Field(&genre.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&genre.Aliases, validation.Length(0, 20), validation.Each(validation.Required, validation.Length(1, 100)),
+			validation.By(validate.Unique(names))),
+	)
+}
+
+// ListGenres godoc
+//
+// @Summary List genres
+// @Description Returns the genre catalog with aliases and number of movies for each genre
+// @Tags genres
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} data.Genre
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres [get]
+func (app *application) listGenresHandler(w http.ResponseWriter, r *http.Request) {
+	genres, err := app.models.Genres.GetAll()
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genres": genres}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GetGenre godoc
+//
+// @Summary Get a genre by ID
+// @Description Returns a single genre with aliases and number of movies
+// @Tags genres
+// @Produce json
+// @Param genreID path int true "Genre ID"
+// @Security BearerAuth
+// @Success 200 {object} data.Genre
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres/{genreID} [get]
+func (app *application) getGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "genreID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	genre, err := app.models.Genres.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genre": genre}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CreateGenre godoc
+//
+// @Summary Create a genre
+// @Description Adds a canonical genre with optional aliases to the catalog (admin only)
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genre body genreInput true "Genre payload"
+// @Security BearerAuth
+// @Success 201 {object} data.Genre
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "genre name or alias is already used"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres [post]
+func (app *application) createGenreHandler(w http.ResponseWriter, r *http.Request) {
+	var input genreInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	genre := &data.Genre{
+		Name:    strings.TrimSpace(input.Name),
+		Aliases: input.Aliases,
+	}
+
+	if genre.Aliases == nil {
+		genre.Aliases = []string{}
+	}
+
+	err = validateGenre(genre)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Genres.Insert(genre)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateGenre):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"genre": genre}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateGenre godoc
+//
+// @Summary Update a genre
+// @Description Renames a genre and/or replaces its aliases (admin only), renaming rewrites genres of existing movies
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genreID path int true "Genre ID"
+// @Param genre body genreInput true "Partial genre payload"
+// @Security BearerAuth
+// @Success 200 {object} data.Genre
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "genre name or alias is already used"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres/{genreID} [patch]
+func (app *application) updateGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "genreID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	genre, err := app.models.Genres.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	// using pointers here to be able to compare which field was empty
+	var input struct {
+		Name    *string  `json:"name"`
+		Aliases []string `json:"aliases"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		genre.Name = strings.TrimSpace(*input.Name)
+	}
+
+	if input.Aliases != nil {
+		genre.Aliases = input.Aliases
+	}
+
+	err = validateGenre(genre)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Genres.Update(genre, app.contextGetUser(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		case errors.Is(err, data.ErrDuplicateGenre):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genre": genre}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteGenre godoc
+//
+// @Summary Delete a genre
+// @Description Deletes a genre that is not used by any movie (admin only), used genres should be merged instead
+// @Tags genres
+// @Produce json
+// @Param genreID path int true "Genre ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "genre successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "genre is used by movies or series, merge it into another genre instead"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres/{genreID} [delete]
+func (app *application) deleteGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "genreID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Genres.Delete(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, data.ErrGenreInUse):
+			app.conflictResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "genre successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// MergeGenre godoc
+//
+// @Summary Merge a genre into another
+// @Description Folds the source genre into the genre from the path (admin only): movies are rewritten, source name and aliases become aliases
+// @Tags genres
+// @Accept json
+// @Produce json
+// @Param genreID path int true "Target genre ID"
+// @Param merge body genreMergeInput true "Merge payload"
+// @Security BearerAuth
+// @Success 200 {object} data.Genre
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /genres/{genreID}/merge [post]
+func (app *application) mergeGenreHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "genreID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input genreMergeInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.SourceID, validation.Required, validation.Min(int64(1)), validation.NotIn(id).Error("must differ from the target genre")),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	genre, err := app.models.Genres.Merge(input.SourceID, id, app.contextGetUser(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"genre": genre}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
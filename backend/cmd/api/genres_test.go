@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestCreateGenreHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockGenres := mocks.NewGenresInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	mockGenres.On("Insert", mock.MatchedBy(func(g *data.Genre) bool {
+		return g.Name == "Science Fiction"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		arg := args.Get(0).(*data.Genre)
+		arg.ID = 1
+		arg.Version = 1
+	})
+	mockGenres.On("Insert", mock.MatchedBy(func(g *data.Genre) bool {
+		return g.Name == "Drama"
+	})).Return(data.ErrDuplicateGenre)
+
+	app.models.Genres = mockGenres
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		reqBody  genreInput
+		wantCode int
+	}{
+		{
+			name:     "Valid genre",
+			reqBody:  genreInput{Name: "Science Fiction", Aliases: []string{"Sci-Fi", "SF"}},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Duplicate genre",
+			reqBody:  genreInput{Name: "Drama"},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing name",
+			reqBody:  genreInput{Aliases: []string{"Sci-Fi"}},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Alias equal to name",
+			reqBody:  genreInput{Name: "Science Fiction", Aliases: []string{"science fiction"}},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestBody, err := json.Marshal(tt.reqBody)
+			assert.NoError(t, err)
+
+			code, _, body := ts.post(t, "/v1/genres", bytes.NewBuffer(requestBody))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusCreated {
+				var resp map[string]data.Genre
+
+				err = json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, int64(1), resp["genre"].ID, "genre ID should be 1")
+				assert.Equal(t, tt.reqBody.Aliases, resp["genre"].Aliases, "aliases should be equal")
+			}
+		})
+	}
+}
+
+func TestGenreHandlersPermissions(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{}, nil)
+
+	app.models.Permissions = mockPermissions
+
+	code, _, _ := ts.post(t, "/v1/genres", bytes.NewBufferString(`{"name": "Drama"}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+
+	code, _, _ = ts.delete(t, "/v1/genres/1")
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
+
+func TestDeleteGenreHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockGenres := mocks.NewGenresInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	mockGenres.On("Delete", int64(1)).Return(nil)
+	mockGenres.On("Delete", int64(2)).Return(data.ErrGenreInUse)
+	mockGenres.On("Delete", int64(3)).Return(data.ErrRecordNotFound)
+
+	app.models.Genres = mockGenres
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+	}{
+		{
+			name:     "Unused genre",
+			urlPath:  "/v1/genres/1",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Genre in use",
+			urlPath:  "/v1/genres/2",
+			wantCode: http.StatusConflict,
+		},
+		{
+			name:     "Non-existent ID",
+			urlPath:  "/v1/genres/3",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.delete(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
@@ -5,14 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/invopop/validation"
 	"github.com/invopop/validation/is"
 
+	"github.com/vladgrskkh/movie_recomendation_system/genproto/common"
 	pb "github.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict"
 
 	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/patch"
 	"github.com/vladgrskkh/movie_recomendation_system/internal/validate"
 )
 
@@ -45,7 +49,13 @@ func (app *application) healthCheckHandler(w http.ResponseWriter, r *http.Reques
 // @Tags movies
 // @Produce json
 // @Param movieID path int true "Movie ID"
+// @Param lang query string false "Language of the title and overview, overrides Accept-Language"
+// @Param Accept-Language header string false "Preferred languages, translated title is used if there is one"
+// @Param If-None-Match header string false "ETag of the cached movie, 304 is returned if it is still current"
 // @Success 200 {object} data.Movie
+// @Header 200 {string} ETag "Movie version as entity tag"
+// @Success 304 "Not Modified"
+// @Success 301 "Moved Permanently, the movie was merged into the one in Location header"
 // @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
 // @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
 // @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
@@ -58,11 +68,17 @@ func (app *application) getMovieHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	languages, err := app.readLanguages(r.URL.Query(), "lang", r.Header.Get("Accept-Language"))
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("lang: %w", err))
+		return
+	}
+
 	movie, err := app.models.Movies.Get(id)
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
-			app.notFoundResponse(w, r)
+			app.redirectMergedMovie(w, r, id)
 		default:
 			app.serverErrorResponse(w, r, err)
 		}
@@ -70,11 +86,53 @@ func (app *application) getMovieHandler(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, nil)
+	app.writeMovie(w, r, movie, languages)
+}
+
+// writeMovie sends the movie as seen by the authenticated user: with the user's flags and localized
+// to the languages. Responds with 304 Not Modified when If-None-Match has the current ETag.
+func (app *application) writeMovie(w http.ResponseWriter, r *http.Request, movie *data.Movie, languages []string) {
+	err := app.models.Watchlist.Mark(app.contextGetUser(r).ID, []*data.Movie{movie})
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.History.Mark(app.contextGetUser(r).ID, []*data.Movie{movie})
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+
+	etag := movieETag(movie)
+
+	if len(languages) > 0 {
+		err = app.models.Translations.Localize([]*data.Movie{movie}, languages)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		// translations have their own versions, so localized movie gets a weak tag of its content
+		if movie.Language != "" {
+			etag, err = envelopeETag(envelope{"movie": movie})
+			if err != nil {
+				app.serverErrorResponse(w, r, err)
+				return
+			}
+		}
 	}
 
+	if app.notModified(w, r, etag) {
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
 }
 
 type movieInput struct {
@@ -84,25 +142,93 @@ type movieInput struct {
 	Genres  []string `json:"genres" example:"Drama,Crime"`
 }
 
+type newMovieInput struct {
+	movieInput
+	ExternalIDs *data.ExternalIDs `json:"external_ids,omitempty"`
+}
+
+// validateMovie checks movie fields and replaces genres with their canonical names from the genre catalog.
+// Returned validation.Errors should be sent as failed validation response, any other error is a server error.
+func (app *application) validateMovie(movie *data.Movie) error {
+	err := validation.ValidateStruct(movie,
+		validation.Field(&movie.Title, validation.Required, validation.Length(1, 500)),
+		validation.Field(&movie.Year, validation.Required, validation.Min(1888), validation.Max(int32(time.Now().Year()))),
+		validation.Field(&movie.Runtime, validation.Required, validation.Min(1)),
+		validation.Field(&movie.Genres, validation.Required, validation.Length(1, 5), validation.By(validate.Unique(movie.Genres))),
+	)
+	if err != nil {
+		return err
+	}
+
+	movie.Genres, err = app.resolveGenres(movie.Genres)
+
+	return err
+}
+
+// resolveGenres replaces genre names and aliases with canonical names from the genre catalog.
+// Unknown genres and aliases of the same genre are reported as validation.Errors for the genres field.
+func (app *application) resolveGenres(names []string) ([]string, error) {
+	genres, unknown, err := app.models.Genres.Resolve(names)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(unknown) > 0 {
+		return nil, validation.Errors{"genres": fmt.Errorf("unknown genres: %s", strings.Join(unknown, ", "))}
+	}
+
+	// different aliases of the same genre become duplicates after resolving
+	err = validation.Validate(genres, validation.By(validate.Unique(genres)))
+	if err != nil {
+		return nil, validation.Errors{"genres": err}
+	}
+
+	return genres, nil
+}
+
+// canonicalGenres replaces genre names and aliases of a filter with canonical names from the genre catalog.
+// Unknown genres are kept as they are, so they simply match no movies.
+func (app *application) canonicalGenres(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return names, nil
+	}
+
+	genres, unknown, err := app.models.Genres.Resolve(names)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(genres, unknown...), nil
+}
+
 // CreateMovie godoc
 //
 // @Summary Create a movie
-// @Description Create a new movie (admin only)
+// @Description Create a new movie (admin only), genres are matched against the genre catalog (names and aliases).
+// @Description A movie with the same normalized title and year or with one of the external ids is a likely duplicate
 // @Tags movies
 // @Accept json
 // @Produce json
-// @Param movie body movieInput true "Movie payload"
+// @Param movie body newMovieInput true "Movie payload"
+// @Param force query bool false "Create the movie even if a likely duplicate exists"
 // @Success 201 {object} data.Movie
 // @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
 // @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 409 {object} DuplicateMovieResponse "Conflict, a likely duplicate of the movie exists"
 // @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
 // @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
 // @Security BearerAuth
 // @Router /movie [post]
 func (app *application) postMovieHandler(w http.ResponseWriter, r *http.Request) {
-	var input movieInput
+	var input newMovieInput
 
-	err := app.readJSON(w, r, &input)
+	force, err := app.readBool(r.URL.Query(), "force", false)
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("force: %w", err))
+		return
+	}
+
+	err = app.readJSON(w, r, &input)
 	if err != nil {
 		app.badRequestResponse(w, r, err)
 		return
@@ -115,21 +241,54 @@ func (app *application) postMovieHandler(w http.ResponseWriter, r *http.Request)
 		Genres:  input.Genres,
 	}
 
-	// Validation of the movie input
-	err = validation.ValidateStruct(movie,
-		validation.Field(&movie.Title, validation.Required, validation.Length(1, 500)),
-		validation.Field(&movie.Year, validation.Required, validation.Min(1888), validation.Max(int32(time.Now().Year()))),
-		validation.Field(&movie.Runtime, validation.Required, validation.Min(1)),
-		validation.Field(&movie.Genres, validation.Required, validation.Length(1, 5), validation.By(validate.Unique(movie.Genres))),
-	)
+	if input.ExternalIDs != nil {
+		err = validateExternalIDs(input.ExternalIDs)
+		if err != nil {
+			app.failedValidationResponse(w, r, validation.Errors{"external_ids": err})
+			return
+		}
+
+		if !input.ExternalIDs.IsEmpty() {
+			movie.ExternalIDs = input.ExternalIDs
+		}
+	}
+
+	err = app.validateMovie(movie)
 	if err != nil {
-		app.failedValidationResponse(w, r, err)
+		var validationErrors validation.Errors
+
+		switch {
+		case errors.As(err, &validationErrors):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
 		return
 	}
 
-	err = app.models.Movies.Insert(movie)
+	if !force {
+		duplicate, err := app.models.Movies.FindDuplicate(movie)
+		if err != nil && !errors.Is(err, data.ErrRecordNotFound) {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		if duplicate != nil {
+			app.duplicateMovieResponse(w, r, duplicate)
+			return
+		}
+	}
+
+	err = app.models.Movies.Insert(movie, app.contextGetUser(r).ID)
 	if err != nil {
-		app.serverErrorResponse(w, r, err)
+		switch {
+		case errors.Is(err, data.ErrDuplicateExternalID):
+			app.failedValidationResponse(w, r, fmt.Errorf("external_ids: %w", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
 		return
 	}
 
@@ -147,12 +306,16 @@ func (app *application) postMovieHandler(w http.ResponseWriter, r *http.Request)
 // DeleteMovie godoc
 //
 // @Summary Delete a movie
-// @Description Delete movie by ID (admin only)
+// @Description Move movie to trash by ID (admin only), it can be restored until purged after the retention period
 // @Tags movies
 // @Produce json
 // @Param movieID path int true "Movie ID"
+// @Param If-Match header string false "ETag of the movie being deleted (required unless disabled in config)"
 // @Success 200 {object} map[string]string "OK | Example {"message": "movie successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
 // @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 412 {object} map[string]string "Precondition Failed | Example {"error": "the resource has been modified since you fetched it, please fetch it again"}"
+// @Failure 428 {object} map[string]string "Precondition Required | Example {"error": "If-Match header with the resource ETag is required"}"
 // @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
 // @Security BearerAuth
 // @Router /movie/{movieID} [delete]
@@ -163,12 +326,37 @@ func (app *application) deleteMovieHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = app.models.Movies.Delete(id)
+	movie, err := app.models.Movies.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	if !app.preconditionMet(w, r, movie.Version) {
+		return
+	}
+
+	// without If-Match the movie is deleted whatever its current version is
+	var version int32
+	if r.Header.Get("If-Match") != "" {
+		version = movie.Version
+	}
+
+	err = app.models.Movies.Delete(id, version, app.contextGetUser(r).ID)
 	if err != nil {
 		switch {
 		case errors.Is(err, data.ErrRecordNotFound):
 			app.notFoundResponse(w, r)
 			return
+		case errors.Is(err, data.ErrEditConflict):
+			app.preconditionFailedResponse(w, r)
+			return
 		default:
 			app.serverErrorResponse(w, r, err)
 			return
@@ -184,17 +372,25 @@ func (app *application) deleteMovieHandler(w http.ResponseWriter, r *http.Reques
 // UpdateMovie godoc
 //
 // @Summary Update a movie
-// @Description Patch movie by ID
+// @Description Patch movie by ID with a JSON Merge Patch (application/json or application/merge-patch+json, null clears a field)
+// @Description or a JSON Patch (application/json-patch+json, test operations are supported), the patched movie is validated as on creation
 // @Tags movies
 // @Accept json
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
 // @Produce json
 // @Param movieID path int true "Movie ID"
-// @Param movie body movieInput true "Partial movie payload"
+// @Param movie body movieInput true "Merge patch or JSON Patch operations"
+// @Param If-Match header string false "ETag of the edited movie version (required unless disabled in config)"
 // @Success 200 {object} data.Movie
+// @Header 200 {string} ETag "New movie version as entity tag"
 // @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
 // @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
-// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 409 {object} map[string]string "Conflict (edit conflict or failed test operation) | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 412 {object} map[string]string "Precondition Failed | Example {"error": "the resource has been modified since you fetched it, please fetch it again"}"
+// @Failure 415 {object} map[string]string "Unsupported Media Type | Example {"error": "content type must be application/json, application/merge-patch+json or application/json-patch+json"}"
 // @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 428 {object} map[string]string "Precondition Required | Example {"error": "If-Match header with the resource ETag is required"}"
 // @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
 // @Security BearerAuth
 // @Router /movie/{movieID} [patch]
@@ -217,50 +413,57 @@ func (app *application) updateMovieHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// using pointers here to be able to compare which field was empty
-	var input struct {
-		Title   *string  `json:"title"`
-		Year    *int32   `json:"year"`
-		Runtime *int32   `json:"runtime"`
-		Genres  []string `json:"genres"`
-	}
-
-	err = app.readJSON(w, r, &input)
-	if err != nil {
-		app.badRequestResponse(w, r, err)
+	if !app.preconditionMet(w, r, movie.Version) {
 		return
 	}
 
-	if input.Title != nil {
-		movie.Title = *input.Title
+	input := movieInput{
+		Title:   movie.Title,
+		Year:    movie.Year,
+		Runtime: movie.Runtime,
+		Genres:  movie.Genres,
 	}
 
-	if input.Year != nil {
-		movie.Year = *input.Year
-	}
+	err = app.readPatch(w, r, &input)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrUnsupportedMediaType):
+			app.unsupportedMediaTypeResponse(w, r)
+		case errors.Is(err, patch.ErrTestFailed):
+			app.patchTestFailedResponse(w, r, err)
+		case errors.Is(err, patch.ErrCannotApply), errors.Is(err, ErrInvalidPatchResult):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.badRequestResponse(w, r, err)
+		}
 
-	if input.Runtime != nil {
-		movie.Runtime = *input.Runtime
+		return
 	}
 
-	if input.Genres != nil {
-		movie.Genres = input.Genres
-	}
+	movie.Title = input.Title
+	movie.Year = input.Year
+	movie.Runtime = input.Runtime
+	movie.Genres = input.Genres
 
-	err = validation.ValidateStruct(movie,
-		validation.Field(&movie.Title, validation.Required, validation.Length(1, 500)),
-		validation.Field(&movie.Year, validation.Required, validation.Min(1888), validation.Max(int32(time.Now().Year()))),
-		validation.Field(&movie.Runtime, validation.Required, validation.Min(1)),
-		validation.Field(&movie.Genres, validation.Required, validation.Length(1, 5), validation.By(validate.Unique(movie.Genres))),
-	)
+	err = app.validateMovie(movie)
 	if err != nil {
-		app.failedValidationResponse(w, r, err)
+		var validationErrors validation.Errors
+
+		switch {
+		case errors.As(err, &validationErrors):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
 		return
 	}
 
-	err = app.models.Movies.Update(movie)
+	err = app.models.Movies.Update(movie, app.contextGetUser(r).ID)
 	if err != nil {
 		switch {
+		case errors.Is(err, data.ErrEditConflict) && r.Header.Get("If-Match") != "":
+			app.preconditionFailedResponse(w, r)
 		case errors.Is(err, data.ErrEditConflict):
 			app.editConflictResponse(w, r)
 		default:
@@ -269,7 +472,10 @@ func (app *application) updateMovieHandler(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, nil)
+	headers := make(http.Header)
+	headers.Set("ETag", movieETag(movie))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, headers)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -279,30 +485,47 @@ func (app *application) updateMovieHandler(w http.ResponseWriter, r *http.Reques
 type MoviesListResponse struct {
 	Movies   []data.Movie  `json:"movies"`
 	Metadata data.Metadata `json:"metadata"`
+	Facets   data.Facets   `json:"facets,omitempty"`
 }
 
 // ListMovies godoc
 //
 // @Summary List movies
 // @Description Retrieve a list of movies filtered by title and genres with pagination and sorting
+// @Description Pass metadata.next_cursor as cursor to get the next page using keyset pagination (page is ignored then)
 // @Tags movies
 // @Produce json
-// @Param title query string false "Full-text search by title"
+// @Param title query string false "Fuzzy search by original and translated titles (tolerates typos and partial words)"
 // @Param genres query []string false "Comma-separated list of genres" collectionFormat(csv)
+// @Param genres_match query string false "How genres are matched: all (movie has every genre) or any (movie has at least one)" default(all)
+// @Param exclude_genres query []string false "Comma-separated list of genres movies must not have" collectionFormat(csv)
+// @Param year_min query int false "Minimum release year"
+// @Param year_max query int false "Maximum release year"
+// @Param runtime_min query int false "Minimum runtime in minutes"
+// @Param runtime_max query int false "Maximum runtime in minutes"
+// @Param rating_min query number false "Minimum average rating from 1 to 10, unrated movies are left out"
+// @Param rating_max query number false "Maximum average rating from 1 to 10, unrated movies are left out"
+// @Param created_after query string false "Only movies added after the RFC 3339 timestamp or YYYY-MM-DD date"
+// @Param collection query int false "Only movies of the collection"
+// @Param facets query []string false "Comma-separated list of facets to count: genres,decade" collectionFormat(csv)
 // @Param page query int false "Page number" default(1)
 // @Param page_size query int false "Number of items per page" default(20)
-// @Param sort query string false "Sort by: one of id,title,year,runtime,-id,-title,-year,-runtime" default(id)
+// @Param sort query string false "Sort by: one of id,title,year,runtime,relevance,rating,rating_count,-id,-title,-year,-runtime,-relevance,-rating,-rating_count (default -relevance when title is set, otherwise id)"
+// @Param cursor query string false "Opaque cursor from metadata.next_cursor of the previous page"
+// @Param include_total query bool false "Calculate total number of records (default true for page based and false for cursor based pagination)"
+// @Param lang query string false "Language of titles and overviews, overrides Accept-Language"
+// @Param Accept-Language header string false "Preferred languages, translated titles are used where available"
+// @Param If-None-Match header string false "ETag of the cached page, 304 is returned if it is still current"
 // @Security BearerAuth
 // @Success 200 {object} MoviesListResponse
+// @Header 200 {string} ETag "Weak entity tag of the page"
+// @Success 304 "Not Modified"
 // @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
 // @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
 // @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
 // @Router /movie [get]
 func (app *application) listMoviesHandler(w http.ResponseWriter, r *http.Request) {
-	var input struct {
-		Title  string
-		Genres []string
-	}
+	var input data.MovieFilter
 
 	var filters data.Filters
 
@@ -312,6 +535,89 @@ func (app *application) listMoviesHandler(w http.ResponseWriter, r *http.Request
 
 	input.Title = app.readString(qs, "title", "")
 	input.Genres = app.readCSV(qs, "genres", []string{})
+	input.GenresMatch = app.readString(qs, "genres_match", data.GenresMatchAll)
+	input.ExcludeGenres = app.readCSV(qs, "exclude_genres", []string{})
+
+	// zero means that the bound is not set
+	bounds := []struct {
+		key string
+		dst *int
+	}{
+		{"year_min", &input.YearMin},
+		{"year_max", &input.YearMax},
+		{"runtime_min", &input.RuntimeMin},
+		{"runtime_max", &input.RuntimeMax},
+	}
+
+	for _, bound := range bounds {
+		*bound.dst, err = app.readInt(qs, bound.key, 0)
+		if err != nil {
+			app.failedValidationResponse(w, r, fmt.Errorf("%s: %w", bound.key, err))
+			return
+		}
+	}
+
+	ratingBounds := []struct {
+		key string
+		dst *float64
+	}{
+		{"rating_min", &input.RatingMin},
+		{"rating_max", &input.RatingMax},
+	}
+
+	for _, bound := range ratingBounds {
+		*bound.dst, err = app.readFloat(qs, bound.key, 0)
+		if err != nil {
+			app.failedValidationResponse(w, r, fmt.Errorf("%s: %w", bound.key, err))
+			return
+		}
+	}
+
+	input.CreatedAfter, err = app.readTime(qs, "created_after", time.Time{})
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("created_after: %w", err))
+		return
+	}
+
+	collectionID, err := app.readInt(qs, "collection", 0)
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("collection: %w", err))
+		return
+	}
+
+	input.CollectionID = int64(collectionID)
+
+	facets := app.readCSV(qs, "facets", []string{})
+
+	err = validation.Validate(facets,
+		validation.Each(validation.In(data.FacetGenres, data.FacetDecade)),
+		validation.By(validate.Unique(facets)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("facets: %w", err))
+		return
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Title, validation.Length(0, 500)),
+		validation.Field(&input.Genres, validation.Length(0, 5)),
+		validation.Field(&input.GenresMatch, validation.In(data.GenresMatchAll, data.GenresMatchAny)),
+		validation.Field(&input.ExcludeGenres, validation.Length(0, 20)),
+		validation.Field(&input.YearMin, validation.Min(1888), validation.Max(time.Now().Year())),
+		validation.Field(&input.YearMax, validation.Min(1888), validation.Max(time.Now().Year()),
+			validation.When(input.YearMin != 0, validation.Min(input.YearMin))),
+		validation.Field(&input.RuntimeMin, validation.Min(1)),
+		validation.Field(&input.RuntimeMax, validation.Min(1),
+			validation.When(input.RuntimeMin != 0, validation.Min(input.RuntimeMin))),
+		validation.Field(&input.RatingMin, validation.Min(1.0), validation.Max(10.0)),
+		validation.Field(&input.RatingMax, validation.Min(1.0), validation.Max(10.0),
+			validation.When(input.RatingMin != 0, validation.Min(input.RatingMin))),
+		validation.Field(&input.CollectionID, validation.Min(int64(1))),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
 
 	filters.Page, err = app.readInt(qs, "page", 1)
 	if err != nil {
@@ -325,13 +631,30 @@ func (app *application) listMoviesHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	filters.Sort = app.readString(qs, "sort", "id")
-	filters.SortSafeList = []string{"id", "title", "year", "runtime", "-id", "-title", "-year", "-runtime"}
+	// best matches go first when searching by title
+	defaultSort := "id"
+	if input.Title != "" {
+		defaultSort = "-relevance"
+	}
+
+	filters.Sort = app.readString(qs, "sort", defaultSort)
+	filters.SortSafeList = []string{"id", "title", "year", "runtime", "relevance", "rating", "rating_count",
+		"-id", "-title", "-year", "-runtime", "-relevance", "-rating", "-rating_count"}
+
+	filters.Cursor = app.readString(qs, "cursor", "")
+
+	// counting all records is expensive on big catalogs, so cursor clients have to ask for it
+	filters.IncludeTotal, err = app.readBool(qs, "include_total", filters.Cursor == "")
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
 
 	err = validation.ValidateStruct(&filters,
 		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
 		validation.Field(&filters.PageSize, validation.Required, validation.Min(1)),
 		validation.Field(&filters.Sort, validation.Required, validation.In(filters.SortSafeList...)),
+		validation.Field(&filters.Cursor, validation.Length(0, 1000)),
 	)
 
 	if err != nil {
@@ -339,13 +662,134 @@ func (app *application) listMoviesHandler(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	movies, metadata, err := app.models.Movies.GetAll(input.Title, input.Genres, filters)
+	languages, err := app.readLanguages(qs, "lang", r.Header.Get("Accept-Language"))
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("lang: %w", err))
+		return
+	}
+
+	input.Genres, err = app.canonicalGenres(input.Genres)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	input.ExcludeGenres, err = app.canonicalGenres(input.ExcludeGenres)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	movies, metadata, err := app.models.Movies.GetAll(input, filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrInvalidCursor):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	if len(languages) > 0 {
+		err = app.models.Translations.Localize(movies, languages)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	// watchlist and seen flags of the whole page are loaded at once
+	err = app.models.Watchlist.Mark(app.contextGetUser(r).ID, movies)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.models.History.Mark(app.contextGetUser(r).ID, movies)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	err = app.writeJSON(w, http.StatusOK, envelope{"movies": movies, "metadata": metadata}, nil)
+	resp := envelope{"movies": movies, "metadata": metadata}
+
+	if len(facets) > 0 {
+		resp["facets"], err = app.models.Movies.Facets(input, facets)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	etag, err := envelopeETag(resp)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept-Language")
+
+	if app.notModified(w, r, etag) {
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, resp, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// SuggestMovies godoc
+//
+// @Summary Suggest movies
+// @Description Returns top matching movies (id, title, year) for typeahead. Titles starting with q go first, then titles
+// @Description with words similar to q, which tolerates typos; there is no plain substring match inside titles
+// @Tags movies
+// @Produce json
+// @Param q query string true "Beginning of the movie title or words similar to its words"
+// @Param limit query int false "Maximum number of suggestions (1-20)" default(10)
+// @Security BearerAuth
+// @Success 200 {array} data.MovieSuggestion
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/suggest [get]
+func (app *application) suggestMoviesHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Query string
+		Limit int
+	}
+
+	qs := r.URL.Query()
+
+	var err error
+
+	input.Query = strings.TrimSpace(app.readString(qs, "q", ""))
+
+	input.Limit, err = app.readInt(qs, "limit", 10)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Query, validation.Required, validation.Length(1, 100)),
+		validation.Field(&input.Limit, validation.Required, validation.Min(1), validation.Max(20)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	suggestions, err := app.models.Movies.Suggest(input.Query, input.Limit)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"suggestions": suggestions}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
@@ -689,19 +1133,59 @@ func (app *application) createAuthenticationTokenHandler(w http.ResponseWriter,
 }
 
 type predictionInput struct {
-	Title string `json:"title" example:"The Shawshank Redemption"`
+	Title string `json:"title,omitempty" example:"The Shawshank Redemption"`
+	// catalog id of the movie, used instead of title when set
+	MovieID int64 `json:"movie_id,omitempty" example:"1"`
+	// catalog id of the series, used instead of title when set
+	SeriesID int64 `json:"series_id,omitempty" example:"1"`
+	// movie or series, movie when omitted
+	Type string `json:"type,omitempty" example:"movie"`
+	// number of recommendations, 5 when omitted
+	TopK int `json:"top_k,omitempty" example:"5"`
+	// recommended movies have at least one of the genres
+	Genres   []string `json:"genres,omitempty" example:"Drama"`
+	YearFrom int      `json:"year_from,omitempty" example:"1990"`
+	YearTo   int      `json:"year_to,omitempty" example:"2000"`
+	// catalog ids of movies or series of the type that must not be recommended
+	Exclude []int64 `json:"exclude,omitempty" example:"2"`
+}
+
+// MovieRecommendation is a recommended movie of the catalog.
+type MovieRecommendation struct {
+	Movie *data.Movie `json:"movie"`
+	Score float64     `json:"score" example:"0.42"`
+}
+
+type SeriesRecommendation struct {
+	Series *data.Series `json:"series"`
+	Score  float64      `json:"score" example:"0.42"`
+}
+
+// PredictResponse holds recommendations of movies or, for type series, of series.
+type PredictResponse struct {
+	Recommendations []*MovieRecommendation `json:"recommendations"`
+}
+
+// contentTypes maps content types of the API to the recommendation service ones.
+var contentTypes = map[string]common.ContentType{
+	data.ContentTypeMovie:  common.ContentType_CONTENT_TYPE_MOVIE,
+	data.ContentTypeSeries: common.ContentType_CONTENT_TYPE_SERIES,
 }
 
 // Predict Handler godoc
 //
 // @Summary Get predict movie
-// @Description Validates movie input and predict movie
+// @Description Predicts movies similar to a movie given by movie_id or title, or with type series, series similar to a series
+// @Description given by series_id or title, then recommendations hold series instead of movies.
+// @Description top_k sets how many are recommended, genres, year_from, year_to and exclude narrow them down.
+// @Description Movies the user dismissed are left out and movies of dismissed genres get lower scores.
+// @Description Recommendations missing from the catalog are left out and replaced by the next recommendations
 // @Tags movies
 // @Accept json
 // @Produce json
 // @Param credentials body predictionInput true "Moive payload"
 // @Security BearerAuth
-// @Success 200 {object} predictionInput
+// @Success 200 {object} PredictResponse
 // @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
 // @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
 // @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
@@ -716,35 +1200,226 @@ func (app *application) predictHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if input.Type == "" {
+		input.Type = data.ContentTypeMovie
+	}
+
+	if input.TopK == 0 {
+		input.TopK = 5
+	}
+
 	err = validation.ValidateStruct(&input,
-		validation.Field(&input.Title, validation.Required, validation.Length(1, 500)),
+		validation.Field(&input.Title, validation.When(input.MovieID == 0 && input.SeriesID == 0, validation.Required), validation.Length(1, 500)),
+		validation.Field(&input.MovieID, validation.Min(int64(1)),
+			validation.When(input.Type != data.ContentTypeMovie, validation.Empty.Error("is available only for movies"))),
+		validation.Field(&input.SeriesID, validation.Min(int64(1)),
+			validation.When(input.Type != data.ContentTypeSeries, validation.Empty.Error("is available only for series"))),
+		validation.Field(&input.Type, validation.In(data.ContentTypeMovie, data.ContentTypeSeries)),
+		validation.Field(&input.TopK, validation.Min(1), validation.Max(50)),
+		validation.Field(&input.Genres, validation.Length(0, 10), validation.Each(validation.Required, validation.Length(1, 100))),
+		validation.Field(&input.YearFrom, validation.Min(1888), validation.Max(time.Now().Year())),
+		validation.Field(&input.YearTo, validation.Min(1888), validation.Max(time.Now().Year()),
+			validation.When(input.YearFrom != 0, validation.Min(input.YearFrom))),
+		validation.Field(&input.Exclude, validation.Length(0, 100), validation.Each(validation.Required, validation.Min(int64(1)))),
 	)
 	if err != nil {
 		app.failedValidationResponse(w, r, err)
 		return
 	}
 
+	request := &pb.RecommendRequest{
+		MovieTitle:  input.Title,
+		ContentType: contentTypes[input.Type],
+		Filter: &pb.RecommendFilter{
+			Genres:   input.Genres,
+			YearFrom: int32(input.YearFrom),
+			YearTo:   int32(input.YearTo),
+		},
+	}
+
+	if input.Type == data.ContentTypeSeries {
+		app.predictSeries(w, r, &input, request)
+		return
+	}
+
+	// the recommendation service knows movies by TMDB ids, catalog ids are mapped to them
+	if input.MovieID != 0 {
+		movie, err := app.models.Movies.Get(input.MovieID)
+		if err != nil {
+			switch {
+			case errors.Is(err, data.ErrRecordNotFound):
+				app.failedValidationResponse(w, r, fmt.Errorf("movie_id: %w", data.ErrUnknownMovie))
+			default:
+				app.serverErrorResponse(w, r, err)
+			}
+
+			return
+		}
+
+		request.MovieTitle = movie.Title
+		if movie.ExternalIDs != nil {
+			request.MovieId = movie.ExternalIDs.TMDB
+		}
+	}
+
+	request.Filter.ExcludeMovieIds, err = app.models.Movies.TMDBIDs(input.Exclude)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	// dismissed movies are excluded by the model, so they don't take places of top_k
+	dismissed, err := app.models.Dismissals.TMDBIDs(app.contextGetUser(r).ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	request.Filter.ExcludeMovieIds = slices.Concat(request.Filter.ExcludeMovieIds, dismissed)
+
+	movieRecommendations, err := fillRecommendations(input.TopK, app.recommendSimilar(request), app.loadMovieRecommendations(app.contextGetUser(r).ID))
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"recommendations": movieRecommendations}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// recommendSimilar asks the recommendation service for recommendations similar to the request,
+// leaving out the TMDB ids the request already excludes.
+func (app *application) recommendSimilar(request *pb.RecommendRequest) fetchRecommendations {
 	client := pb.NewRecommendationClient(app.grpcConn)
+	excluded := request.Filter.ExcludeMovieIds
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
+	return func(count int, exclude []int64) ([]*common.Recommendation, error) {
+		request.TopK = int32(count)
+		request.Filter.ExcludeMovieIds = slices.Concat(excluded, exclude)
 
-	recommendation, err := client.Recommend(ctx, &pb.RecommendRequest{
-		MovieTitle: input.Title,
-	})
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		response, err := client.Recommend(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return response.GetRecommendations(), nil
+	}
+}
+
+// predictSeries recommends series similar to the series given by series_id or title.
+func (app *application) predictSeries(w http.ResponseWriter, r *http.Request, input *predictionInput, request *pb.RecommendRequest) {
+	// the recommendation service knows series by TMDB ids, catalog ids are mapped to them
+	if input.SeriesID != 0 {
+		series, err := app.models.Series.Get(input.SeriesID)
+		if err != nil {
+			switch {
+			case errors.Is(err, data.ErrRecordNotFound):
+				app.failedValidationResponse(w, r, fmt.Errorf("series_id: %w", data.ErrUnknownSeries))
+			default:
+				app.serverErrorResponse(w, r, err)
+			}
+
+			return
+		}
+
+		request.MovieTitle = series.Title
+		request.MovieId = series.TMDBID
+	}
+
+	var err error
+
+	request.Filter.ExcludeMovieIds, err = app.models.Series.TMDBIDs(input.Exclude)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 		return
 	}
 
-	data := envelope{"recommendations": recommendation.GetRecommendations()}
+	seriesRecommendations, err := fillRecommendations(input.TopK, app.recommendSimilar(request), app.hydrateSeriesRecommendations)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
 
-	err = app.writeJSON(w, http.StatusOK, data, nil)
+	err = app.writeJSON(w, http.StatusOK, envelope{"recommendations": seriesRecommendations}, nil)
 	if err != nil {
 		app.serverErrorResponse(w, r, err)
 	}
 }
 
+// hydrateSeriesRecommendations loads the recommended series from the catalog with a single query, keeping their order.
+// Recommendations without a TMDB id or of series missing from the catalog are left out.
+func (app *application) hydrateSeriesRecommendations(recommendations []*common.Recommendation) ([]*SeriesRecommendation, error) {
+	ids := make([]int64, 0, len(recommendations))
+	scores := make(map[int64]float64, len(recommendations))
+
+	for _, recommendation := range recommendations {
+		id := recommendation.GetMovieId()
+		if id == 0 {
+			continue
+		}
+
+		if _, ok := scores[id]; !ok {
+			ids = append(ids, id)
+			scores[id] = recommendation.GetScore()
+		}
+	}
+
+	series, err := app.models.Series.GetAllByTMDBID(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	seriesRecommendations := make([]*SeriesRecommendation, 0, len(series))
+
+	for _, s := range series {
+		seriesRecommendations = append(seriesRecommendations, &SeriesRecommendation{Series: s, Score: scores[s.TMDBID]})
+	}
+
+	return seriesRecommendations, nil
+}
+
+// hydrateRecommendations loads the recommended movies from the catalog with a single query, keeping their order.
+// Recommendations without a movie id or of movies missing from the catalog are left out.
+func (app *application) hydrateRecommendations(recommendations []*common.Recommendation) ([]*MovieRecommendation, error) {
+	ids := make([]int64, 0, len(recommendations))
+	scores := make(map[int64]float64, len(recommendations))
+
+	for _, recommendation := range recommendations {
+		id := recommendation.GetMovieId()
+		if id == 0 {
+			continue
+		}
+
+		if _, ok := scores[id]; !ok {
+			ids = append(ids, id)
+			scores[id] = recommendation.GetScore()
+		}
+	}
+
+	movies, err := app.models.Movies.GetAllByTMDBID(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	movieRecommendations := make([]*MovieRecommendation, 0, len(movies))
+
+	for _, movie := range movies {
+		var score float64
+		if movie.ExternalIDs != nil {
+			score = scores[movie.ExternalIDs.TMDB]
+		}
+
+		movieRecommendations = append(movieRecommendations, &MovieRecommendation{Movie: movie, Score: score})
+	}
+
+	return movieRecommendations, nil
+}
+
 type inputChangePassword struct {
 	Email string `json:"email" example:"something@example.com"`
 }
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/genproto/common"
 	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
 	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
 )
@@ -56,8 +58,11 @@ func TestGetMovieHandler(t *testing.T) {
 
 	mockMovies.On("Get", int64(1)).Return(&movie, nil)
 	mockMovies.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound)
+	mockMovies.On("GetRedirect", int64(2)).Return(int64(0), data.ErrRecordNotFound)
 
 	app.models.Movies = mockMovies
+	app.models.Watchlist = newWatchlistMock(t)
+	app.models.History = newHistoryMock(t)
 
 	tests := []struct {
 		name     string
@@ -132,13 +137,21 @@ func TestPostMovieHandler(t *testing.T) {
 		Genres:  movieReq.Genres,
 	}
 
-	mockMovies.On("Insert", &movie).Return(nil).Run(func(args mock.Arguments) {
+	mockMovies.On("FindDuplicate", &movie).Return(nil, data.ErrRecordNotFound)
+	mockMovies.On("Insert", &movie, int64(1)).Return(nil).Run(func(args mock.Arguments) {
 		arg := args.Get(0).(*data.Movie)
 		arg.ID = 1
 		arg.Version = 1
 	})
 
+	mockGenres := mocks.NewGenresInterface(t)
+
+	mockGenres.On("Resolve", []string{"Drama", "Action"}).Return([]string{"Drama", "Action"}, nil, nil)
+	mockGenres.On("Resolve", []string{"Drama", "Sitcom"}).Return([]string{"Drama"}, []string{"Sitcom"}, nil)
+	mockGenres.On("Resolve", []string{"Sci-Fi", "Science Fiction"}).Return([]string{"Science Fiction", "Science Fiction"}, nil, nil)
+
 	app.models.Movies = mockMovies
+	app.models.Genres = mockGenres
 
 	tests := []struct {
 		name     string
@@ -218,6 +231,26 @@ func TestPostMovieHandler(t *testing.T) {
 			},
 			wantCode: http.StatusUnprocessableEntity,
 		},
+		{
+			name: "Invalid Movie (unknown genre)",
+			reqBody: movieInput{
+				Title:   "Test Movie",
+				Year:    2024,
+				Runtime: 125,
+				Genres:  []string{"Drama", "Sitcom"},
+			},
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name: "Invalid Movie (aliases of the same genre)",
+			reqBody: movieInput{
+				Title:   "Test Movie",
+				Year:    2024,
+				Runtime: 125,
+				Genres:  []string{"Sci-Fi", "Science Fiction"},
+			},
+			wantCode: http.StatusUnprocessableEntity,
+		},
 		{
 			name:     "Invalid JSON",
 			reqBody:  map[string]string{"invalid_json": "invalid_json"},
@@ -259,10 +292,15 @@ func TestDeleteMovieHandler(t *testing.T) {
 
 	mockMovies := mocks.NewMoviesInterface(t)
 
-	mockMovies.On("Delete", int64(1)).Return(nil)
-	mockMovies.On("Delete", int64(2)).Return(data.ErrRecordNotFound)
+	mockMovies.On("Get", int64(1)).Return(&data.Movie{ID: 1, Title: "Test Movie", Version: 1}, nil)
+	mockMovies.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound)
+	mockMovies.On("Delete", int64(1), int32(0), int64(1)).Return(nil)
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
 
 	app.models.Movies = mockMovies
+	app.models.Permissions = mockPermissions
 
 	tests := []struct {
 		name     string
@@ -304,3 +342,435 @@ func TestDeleteMovieHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestDeleteMoviePermissions(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{}, nil)
+
+	app.models.Permissions = mockPermissions
+
+	code, _, _ := ts.delete(t, "/v1/movie/1")
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
+
+func TestListMoviesHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	movies := []*data.Movie{
+		{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 1},
+	}
+
+	metadata := data.Metadata{PageSize: 1, NextCursor: "next"}
+
+	mockMovies.On("GetAll", mock.AnythingOfType("data.MovieFilter"), mock.MatchedBy(func(f data.Filters) bool {
+		return f.Cursor == "valid" && !f.IncludeTotal
+	})).Return(movies, metadata, nil)
+	mockMovies.On("GetAll", mock.AnythingOfType("data.MovieFilter"), mock.MatchedBy(func(f data.Filters) bool {
+		return f.Cursor == "invalid"
+	})).Return(nil, data.Metadata{}, data.ErrInvalidCursor)
+	mockMovies.On("GetAll", mock.MatchedBy(func(f data.MovieFilter) bool {
+		return f.YearMin == 1990 && f.YearMax == 1999 && f.GenresMatch == data.GenresMatchAny
+	}), mock.AnythingOfType("data.Filters")).Return(movies, data.Metadata{}, nil)
+	mockMovies.On("GetAll", mock.MatchedBy(func(f data.MovieFilter) bool {
+		return slices.Equal(f.Genres, []string{"Science Fiction"}) && slices.Equal(f.ExcludeGenres, []string{"Horror", "Splatter"})
+	}), mock.AnythingOfType("data.Filters")).Return(movies, data.Metadata{}, nil)
+	mockMovies.On("GetAll", mock.MatchedBy(func(f data.MovieFilter) bool {
+		return f.RatingMin == 7.5 && f.RatingMax == 9
+	}), mock.AnythingOfType("data.Filters")).Return(movies, data.Metadata{}, nil)
+	mockMovies.On("GetAll", mock.MatchedBy(func(f data.MovieFilter) bool {
+		return f.Title == "facets"
+	}), mock.AnythingOfType("data.Filters")).Return(movies, data.Metadata{}, nil)
+	mockMovies.On("Facets", mock.AnythingOfType("data.MovieFilter"), []string{data.FacetGenres}).Return(data.Facets{
+		data.FacetGenres: {{Value: "Drama", Count: 1}},
+	}, nil)
+
+	mockGenres := mocks.NewGenresInterface(t)
+	mockGenres.On("Resolve", []string{"Drama", "Crime"}).Return([]string{"Drama", "Crime"}, nil, nil)
+	mockGenres.On("Resolve", []string{"Sci-Fi"}).Return([]string{"Science Fiction"}, nil, nil)
+	mockGenres.On("Resolve", []string{"Horror", "Splatter"}).Return([]string{"Horror"}, []string{"Splatter"}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Genres = mockGenres
+	app.models.Watchlist = newWatchlistMock(t)
+	app.models.History = newHistoryMock(t)
+
+	tests := []struct {
+		name       string
+		urlPath    string
+		wantCode   int
+		wantNext   string
+		wantFacets data.Facets
+	}{
+		{
+			name:     "Valid cursor",
+			urlPath:  "/v1/movie?cursor=valid&page_size=1",
+			wantCode: http.StatusOK,
+			wantNext: "next",
+		},
+		{
+			name:     "Invalid cursor",
+			urlPath:  "/v1/movie?cursor=invalid",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid include_total",
+			urlPath:  "/v1/movie?include_total=maybe",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Valid ranges",
+			urlPath:  "/v1/movie?year_min=1990&year_max=1999&genres=Drama,Crime&genres_match=any",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Genre aliases",
+			urlPath:  "/v1/movie?genres=Sci-Fi&exclude_genres=Horror,Splatter",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Reversed year range",
+			urlPath:  "/v1/movie?year_min=2000&year_max=1990",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Valid rating range",
+			urlPath:  "/v1/movie?rating_min=7.5&rating_max=9",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Reversed rating range",
+			urlPath:  "/v1/movie?rating_min=8&rating_max=6",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Rating out of range",
+			urlPath:  "/v1/movie?rating_min=11",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Non numeric rating",
+			urlPath:  "/v1/movie?rating_max=high",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Non integer runtime",
+			urlPath:  "/v1/movie?runtime_min=long",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid genres_match",
+			urlPath:  "/v1/movie?genres_match=some",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid created_after",
+			urlPath:  "/v1/movie?created_after=yesterday",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "Genre facets",
+			urlPath:    "/v1/movie?title=facets&facets=genres",
+			wantCode:   http.StatusOK,
+			wantFacets: data.Facets{data.FacetGenres: {{Value: "Drama", Count: 1}}},
+		},
+		{
+			name:     "Unknown facet",
+			urlPath:  "/v1/movie?facets=rating",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Duplicate facets",
+			urlPath:  "/v1/movie?facets=decade,decade",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantNext != "" {
+				var resp MoviesListResponse
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Len(t, resp.Movies, 1, "one movie should be returned")
+				assert.Equal(t, tt.wantNext, resp.Metadata.NextCursor, "next cursor should be returned")
+			}
+
+			if tt.wantFacets != nil {
+				var resp MoviesListResponse
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.wantFacets, resp.Facets, "facets should be equal")
+			}
+		})
+	}
+}
+
+func TestSuggestMoviesHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	suggestions := []*data.MovieSuggestion{
+		{ID: 1, Title: "The Shawshank Redemption", Year: 1994},
+	}
+
+	mockMovies.On("Suggest", "shawshenk", 10).Return(suggestions, nil)
+
+	app.models.Movies = mockMovies
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+		wantBody []*data.MovieSuggestion
+	}{
+		{
+			name:     "Valid query",
+			urlPath:  "/v1/movie/suggest?q=shawshenk",
+			wantCode: http.StatusOK,
+			wantBody: suggestions,
+		},
+		{
+			name:     "Missing query",
+			urlPath:  "/v1/movie/suggest",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Limit out of range",
+			urlPath:  "/v1/movie/suggest?q=shawshenk&limit=100",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != nil {
+				var resp map[string][]*data.MovieSuggestion
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, tt.wantBody, resp["suggestions"], "suggestions should be equal")
+			}
+		})
+	}
+}
+
+func TestPredictHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	fake := newTestRecommendationService(t, app)
+
+	// the second recommendation is missing from the catalog
+	mockMovies := newCatalogMock(t, 102)
+	mockMovies.On("Get", int64(1)).Return(&data.Movie{ID: 1, Title: "Se7en", ExternalIDs: &data.ExternalIDs{TMDB: 807}}, nil)
+	mockMovies.On("Get", int64(999)).Return(nil, data.ErrRecordNotFound)
+	mockMovies.On("TMDBIDs", []int64{2}).Return([]int64{949}, nil)
+
+	mockDismissals := mocks.NewDismissalsInterface(t)
+	mockDismissals.On("Suppressions", int64(1), mock.Anything).Return(map[int64]data.Suppression{}, nil)
+	mockDismissals.On("TMDBIDs", int64(1)).Return([]int64{550}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Dismissals = mockDismissals
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody []string
+	}{
+		{
+			name:     "Valid movie id with filters",
+			body:     `{"movie_id": 1, "top_k": 3, "genres": ["Crime"], "year_from": 1990, "year_to": 2000, "exclude": [2]}`,
+			wantCode: http.StatusOK,
+			wantBody: []string{`"title": "Movie 1"`, `"title": "Movie 3"`, `"title": "Movie 4"`, `"score": 1`},
+		},
+		{
+			name:     "Missing title and movie id",
+			body:     `{"top_k": 3}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Unknown movie",
+			body:     `{"movie_id": 999}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: []string{"movie doesn't exist"},
+		},
+		{
+			name:     "Movie id for series",
+			body:     `{"movie_id": 1, "type": "series"}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: []string{"is available only for movies"},
+		},
+		{
+			name:     "Series id for movie",
+			body:     `{"series_id": 1}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: []string{"is available only for series"},
+		},
+		{
+			name:     "Unknown content type",
+			body:     `{"title": "Se7en", "type": "episode"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Top k too large",
+			body:     `{"title": "Se7en", "top_k": 100}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Year range reversed",
+			body:     `{"title": "Se7en", "year_from": 2000, "year_to": 1990}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.post(t, "/v1/movie/predict", bytes.NewReader([]byte(tt.body)))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			for _, want := range tt.wantBody {
+				assert.Contains(t, string(body), want)
+			}
+		})
+	}
+
+	if assert.NotNil(t, fake.recommendRequest, "recommendation service should be called") {
+		assert.Equal(t, int64(807), fake.recommendRequest.GetMovieId(), "movie should be sent by TMDB id")
+		assert.Equal(t, int32(6), fake.recommendRequest.GetTopK(), "twice top_k should be requested")
+		assert.Equal(t, []string{"Crime"}, fake.recommendRequest.GetFilter().GetGenres())
+		assert.Equal(t, []int64{949, 550}, fake.recommendRequest.GetFilter().GetExcludeMovieIds(), "excluded and dismissed movies should be sent")
+	}
+}
+
+func TestPredictSeries(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	fake := newTestRecommendationService(t, app)
+
+	mockSeries := mocks.NewSeriesInterface(t)
+	mockSeries.On("Get", int64(1)).Return(&data.Series{ID: 1, Title: "Breaking Bad", TMDBID: 1396}, nil)
+	mockSeries.On("Get", int64(999)).Return(nil, data.ErrRecordNotFound)
+	mockSeries.On("TMDBIDs", []int64{2}).Return([]int64{1399}, nil)
+	// the second recommendation is missing from the catalog
+	mockSeries.On("GetAllByTMDBID", mock.Anything).Return(func(ids []int64) ([]*data.Series, error) {
+		series := []*data.Series{}
+		for _, id := range ids {
+			if id != 102 {
+				series = append(series, &data.Series{ID: id - 100, Title: fmt.Sprintf("Series %d", id-100), TMDBID: id})
+			}
+		}
+
+		return series, nil
+	})
+
+	app.models.Series = mockSeries
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+		wantBody []string
+	}{
+		{
+			name:     "Valid series id",
+			body:     `{"series_id": 1, "type": "series", "top_k": 2, "exclude": [2]}`,
+			wantCode: http.StatusOK,
+			wantBody: []string{`"series": {`, `"title": "Series 1"`, `"title": "Series 3"`},
+		},
+		{
+			name:     "Unknown series",
+			body:     `{"series_id": 999, "type": "series"}`,
+			wantCode: http.StatusUnprocessableEntity,
+			wantBody: []string{"series doesn't exist"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.post(t, "/v1/movie/predict", bytes.NewReader([]byte(tt.body)))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			for _, want := range tt.wantBody {
+				assert.Contains(t, string(body), want)
+			}
+		})
+	}
+
+	if assert.NotNil(t, fake.recommendRequest, "recommendation service should be called") {
+		assert.Equal(t, common.ContentType_CONTENT_TYPE_SERIES, fake.recommendRequest.GetContentType())
+		assert.Equal(t, int64(1396), fake.recommendRequest.GetMovieId(), "series should be sent by TMDB id")
+		assert.Equal(t, "Breaking Bad", fake.recommendRequest.GetMovieTitle())
+		assert.Equal(t, []int64{1399}, fake.recommendRequest.GetFilter().GetExcludeMovieIds(), "excluded series should be sent")
+	}
+}
+
+func TestPredictFillsTopK(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	fake := newTestRecommendationService(t, app)
+
+	// the first four recommendations are missing from the catalog and the fifth is dismissed
+	mockMovies := newCatalogMock(t, 101, 102, 103, 104)
+	mockMovies.On("TMDBIDs", []int64(nil)).Return([]int64{}, nil)
+
+	mockDismissals := mocks.NewDismissalsInterface(t)
+	mockDismissals.On("TMDBIDs", int64(1)).Return([]int64{}, nil)
+	mockDismissals.On("Suppressions", int64(1), mock.Anything).Return(map[int64]data.Suppression{5: {Dismissed: true}}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Dismissals = mockDismissals
+
+	code, _, body := ts.post(t, "/v1/movie/predict", bytes.NewReader([]byte(`{"title": "Se7en", "top_k": 2}`)))
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	var resp PredictResponse
+
+	err := json.Unmarshal(body, &resp)
+	assert.NoError(t, err)
+
+	var ids []int64
+	for _, recommendation := range resp.Recommendations {
+		ids = append(ids, recommendation.Movie.ID)
+	}
+
+	assert.Equal(t, []int64{6, 7}, ids, "top_k should be filled with the next recommendations")
+
+	if assert.Len(t, fake.recommendRequests, 2, "recommendation service should be asked again") {
+		assert.Equal(t, []int64{101, 102, 103, 104}, fake.recommendRequests[1].GetFilter().GetExcludeMovieIds(), "recommended movies should be excluded")
+	}
+}
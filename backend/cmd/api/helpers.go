@@ -1,29 +1,54 @@
 package main
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math"
+	"mime"
 	"net/http"
 	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/patch"
 )
 
 var (
-	ErrKeyNotInteger = errors.New("must be an integer")
+	ErrKeyNotInteger        = errors.New("must be an integer")
+	ErrKeyNotNumber         = errors.New("must be a number")
+	ErrKeyNotBoolean        = errors.New("must be a boolean value")
+	ErrKeyNotTime           = errors.New("must be a RFC 3339 timestamp or a YYYY-MM-DD date")
+	ErrKeyNotLanguage       = errors.New("must be a two or three letter ISO 639 language code")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrInvalidPatchResult   = errors.New("patched document is invalid")
 )
 
+// languageRX matches ISO 639 language codes, which are used as primary language subtags.
+var languageRX = regexp.MustCompile("^[a-z]{2,3}$")
+
 type envelope map[string]interface{}
 
-// readIDParam extracts and validates the ID parameter from the URL
+// readIDParam extracts and validates the movie ID parameter from the URL
 func (app *application) readIDParam(r *http.Request) (int64, error) {
-	movieID := chi.URLParam(r, "movieID")
+	return app.readNamedIDParam(r, "movieID")
+}
+
+// readNamedIDParam extracts and validates the ID parameter with the given name from the URL
+func (app *application) readNamedIDParam(r *http.Request, name string) (int64, error) {
+	param := chi.URLParam(r, name)
 
-	id, err := strconv.ParseInt(movieID, 10, 64)
+	id, err := strconv.ParseInt(param, 10, 64)
 	if err != nil || id < 1 {
 		return 0, errors.New("invalid id parameter")
 	}
@@ -107,6 +132,78 @@ func (app *application) readJSON(w http.ResponseWriter, r *http.Request, dst int
 	return nil
 }
 
+// readPatch reads a JSON Merge Patch (application/merge-patch+json or plain application/json)
+// or a JSON Patch (application/json-patch+json) from the request body and applies it to
+// the JSON representation of dst, which is then replaced with the patched document.
+func (app *application) readPatch(w http.ResponseWriter, r *http.Request, dst interface{}) error {
+	mediaType := "application/json"
+
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		var err error
+
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return ErrUnsupportedMediaType
+		}
+	}
+
+	var apply func(doc, patch []byte) ([]byte, error)
+
+	switch mediaType {
+	case "application/json", "application/merge-patch+json":
+		apply = patch.MergePatch
+	case "application/json-patch+json":
+		apply = patch.JSONPatch
+	default:
+		return ErrUnsupportedMediaType
+	}
+
+	var body json.RawMessage
+
+	err := app.readJSON(w, r, &body)
+	if err != nil {
+		return err
+	}
+
+	return applyPatch(dst, body, apply)
+}
+
+// applyPatch applies the patch document to the JSON representation of dst with the given function
+// and replaces dst with the patched document, unknown keys are not allowed in the result.
+func applyPatch(dst interface{}, body []byte, apply func(doc, patch []byte) ([]byte, error)) error {
+	doc, err := json.Marshal(dst)
+	if err != nil {
+		return err
+	}
+
+	patched, err := apply(doc, body)
+	if err != nil {
+		return err
+	}
+
+	// fields removed by the patch must end up empty, so dst is decoded from scratch
+	reflect.ValueOf(dst).Elem().SetZero()
+
+	dec := json.NewDecoder(bytes.NewReader(patched))
+	dec.DisallowUnknownFields()
+
+	err = dec.Decode(dst)
+	if err != nil {
+		var unmarshalTypeError *json.UnmarshalTypeError
+
+		switch {
+		case errors.As(err, &unmarshalTypeError) && unmarshalTypeError.Field != "":
+			return fmt.Errorf("%w: incorrect JSON type for field %q", ErrInvalidPatchResult, unmarshalTypeError.Field)
+		case strings.HasPrefix(err.Error(), "json: unknown field "):
+			return fmt.Errorf("%w: unknown key %s", ErrInvalidPatchResult, strings.TrimPrefix(err.Error(), "json: unknown field "))
+		default:
+			return fmt.Errorf("%w: %w", ErrInvalidPatchResult, err)
+		}
+	}
+
+	return nil
+}
+
 func (app *application) background(fn func()) {
 	app.wg.Add(1)
 	go func() {
@@ -165,6 +262,157 @@ func (app *application) readInt(qs url.Values, key string, defaultValue int) (in
 	return i, nil
 }
 
+// readFloat is a helper method for retrieving a floating point value from a url.Values object.
+// If the value is not present, it returns the defaultValue.
+func (app *application) readFloat(qs url.Values, key string, defaultValue float64) (float64, error) {
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue, nil
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return defaultValue, ErrKeyNotNumber
+	}
+
+	return f, nil
+}
+
+// readBool is a helper method for retrieving a boolean value from a url.Values object.
+// If the value is not present, it returns the defaultValue.
+// Accepts the same values as strconv.ParseBool (1, t, true, 0, f, false, ...).
+func (app *application) readBool(qs url.Values, key string, defaultValue bool) (bool, error) {
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue, nil
+	}
+
+	b, err := strconv.ParseBool(s)
+	if err != nil {
+		return defaultValue, ErrKeyNotBoolean
+	}
+
+	return b, nil
+}
+
+// readTime is a helper method for retrieving a time value from a url.Values object.
+// If the value is not present, it returns the defaultValue.
+// The value can be either RFC 3339 timestamp or a date in YYYY-MM-DD format (midnight UTC).
+func (app *application) readTime(qs url.Values, key string, defaultValue time.Time) (time.Time, error) {
+	s := qs.Get(key)
+
+	if s == "" {
+		return defaultValue, nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+	}
+
+	return defaultValue, ErrKeyNotTime
+}
+
+// readFilters reads and validates page, page_size and sort query parameters of page based lists.
+func (app *application) readFilters(qs url.Values, defaultSort string, sortSafeList []string) (data.Filters, error) {
+	var filters data.Filters
+
+	var err error
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		return data.Filters{}, fmt.Errorf("page: %w", err)
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		return data.Filters{}, fmt.Errorf("page_size: %w", err)
+	}
+
+	filters.Sort = app.readString(qs, "sort", defaultSort)
+	filters.SortSafeList = sortSafeList
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Sort, validation.Required, validation.In(filters.SortSafeList...)),
+	)
+	if err != nil {
+		return data.Filters{}, err
+	}
+
+	return filters, nil
+}
+
+// readLanguages returns languages the client prefers, most preferred first.
+// The key query parameter takes precedence over the Accept-Language header value.
+// Only primary subtags are used ("ru-RU" becomes "ru"), invalid header entries are ignored.
+func (app *application) readLanguages(qs url.Values, key string, acceptLanguage string) ([]string, error) {
+	if s := qs.Get(key); s != "" {
+		language := strings.ToLower(s)
+		if !languageRX.MatchString(language) {
+			return nil, ErrKeyNotLanguage
+		}
+
+		return []string{language}, nil
+	}
+
+	type weighted struct {
+		language string
+		q        float64
+	}
+
+	var candidates []weighted
+
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+
+			q = parsed
+		}
+
+		primary, _, _ := strings.Cut(tag, "-")
+		primary = strings.ToLower(primary)
+
+		if q <= 0 || !languageRX.MatchString(primary) {
+			continue
+		}
+
+		candidates = append(candidates, weighted{language: primary, q: q})
+	}
+
+	slices.SortStableFunc(candidates, func(a, b weighted) int {
+		switch {
+		case a.q > b.q:
+			return -1
+		case a.q < b.q:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	languages := []string{}
+
+	for _, candidate := range candidates {
+		if !slices.Contains(languages, candidate.language) {
+			languages = append(languages, candidate.language)
+		}
+	}
+
+	return languages, nil
+}
+
 // readValidation is a helper method for reading validation message and converting it to a map for json response
 // This method should only be used for reading validationMessage when user is registering/logging in
 // Can change it in future, but best options is to change validation package or implement our own
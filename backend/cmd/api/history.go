@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type historyEventInput struct {
+	MovieID   int64      `json:"movie_id" example:"1"`
+	WatchedAt *time.Time `json:"watched_at" example:"2025-01-01T20:00:00Z"`
+	Progress  *int32     `json:"progress" example:"45"`
+	Rewatch   bool       `json:"rewatch" example:"false"`
+}
+
+type HistoryResponse struct {
+	History  []data.WatchEvent `json:"history"`
+	Metadata data.Metadata     `json:"metadata"`
+}
+
+// ListHistory godoc
+//
+// @Summary List my watch history
+// @Description Returns the authenticated user's watch events, the latest first.
+// @Description Pass metadata.next_cursor as cursor to get the next page, the total number of records is not calculated
+// @Tags history
+// @Produce json
+// @Param page_size query int false "Page size" default(20)
+// @Param cursor query string false "Opaque cursor from metadata.next_cursor of the previous page"
+// @Security BearerAuth
+// @Success 200 {object} HistoryResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "invalid cursor"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/history [get]
+func (app *application) listHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.Cursor = app.readString(qs, "cursor", "")
+	filters.Sort = "-watched_at"
+	filters.SortSafeList = []string{"-watched_at"}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Cursor, validation.Length(0, 1000)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	events, metadata, err := app.models.History.GetAll(app.contextGetUser(r).ID, filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrInvalidCursor):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"history": events, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// AddHistoryEvent godoc
+//
+// @Summary Record a watch event
+// @Description Records that the authenticated user watched the movie. watched_at defaults to now,
+// @Description progress is the watched part in percent and is omitted for a complete watch.
+// @Description The movie is marked as seen from 90 percent on
+// @Tags history
+// @Accept json
+// @Produce json
+// @Param event body historyEventInput true "Watch event"
+// @Security BearerAuth
+// @Success 201 {object} data.WatchEvent
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "movie_id: movie doesn't exist"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/history [post]
+func (app *application) addHistoryEventHandler(w http.ResponseWriter, r *http.Request) {
+	var input historyEventInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	now := time.Now()
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.MovieID, validation.Required, validation.Min(int64(1))),
+		validation.Field(&input.WatchedAt, validation.By(func(value interface{}) error {
+			watchedAt := value.(*time.Time)
+			// a small allowance for clients with clocks running ahead
+			if watchedAt != nil && watchedAt.After(now.Add(time.Minute)) {
+				return errors.New("must not be in the future")
+			}
+
+			return nil
+		})),
+		validation.Field(&input.Progress, validation.NilOrNotEmpty, validation.Min(int32(1)), validation.Max(int32(100))),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	event := &data.WatchEvent{
+		MovieID:   input.MovieID,
+		WatchedAt: now,
+		Progress:  input.Progress,
+		Rewatch:   input.Rewatch,
+	}
+
+	if input.WatchedAt != nil {
+		event.WatchedAt = *input.WatchedAt
+	}
+
+	err = app.models.History.Insert(app.contextGetUser(r).ID, event)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.failedValidationResponse(w, r, fmt.Errorf("movie_id: %w", data.ErrUnknownMovie))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"event": event}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestAddHistoryEventHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockHistory := mocks.NewHistoryInterface(t)
+
+	mockHistory.On("Insert", int64(1), mock.MatchedBy(func(e *data.WatchEvent) bool { return e.MovieID == 1 })).
+		Return(nil).Run(func(args mock.Arguments) {
+		event := args.Get(1).(*data.WatchEvent)
+		event.ID = 1
+		event.Title = "Test Movie"
+	}).Maybe()
+	mockHistory.On("Insert", int64(1), mock.MatchedBy(func(e *data.WatchEvent) bool { return e.MovieID == 2 })).
+		Return(data.ErrRecordNotFound).Maybe()
+
+	app.models.History = mockHistory
+
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Complete watch",
+			body:     `{"movie_id": 1}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Partial rewatch",
+			body:     `{"movie_id": 1, "watched_at": "2025-01-01T20:00:00Z", "progress": 45, "rewatch": true}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Non-existent movie",
+			body:     `{"movie_id": 2}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing movie",
+			body:     `{"progress": 45}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Zero progress",
+			body:     `{"movie_id": 1, "progress": 0}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Progress over 100",
+			body:     `{"movie_id": 1, "progress": 101}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Watched in the future",
+			body:     `{"movie_id": 1, "watched_at": "` + future + `"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid time",
+			body:     `{"movie_id": 1, "watched_at": "yesterday"}`,
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.post(t, "/v1/users/me/history", strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusCreated {
+				var resp map[string]data.WatchEvent
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+				assert.Equal(t, "Test Movie", resp["event"].Title)
+				assert.False(t, resp["event"].WatchedAt.IsZero())
+			}
+		})
+	}
+}
+
+func TestAddHistoryEventRequiresActivatedUser(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	// the history mock has no expectations, inactive users never reach it
+	app.models.History = mocks.NewHistoryInterface(t)
+
+	token, err := testAuth(1, false, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := http.Header{"Authorization": {"Bearer " + token}}
+
+	code, _, _ := ts.send(t, http.MethodPost, "/v1/users/me/history", header, strings.NewReader(`{"movie_id": 1, "progress": 100}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
+
+func TestListHistoryHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockHistory := mocks.NewHistoryInterface(t)
+
+	mockHistory.On("GetAll", int64(1), mock.MatchedBy(func(f data.Filters) bool { return f.Cursor == "" })).
+		Return([]*data.WatchEvent{{ID: 1, MovieID: 1, Title: "Test Movie", WatchedAt: time.Now()}},
+			data.Metadata{PageSize: 20, NextCursor: "next"}, nil).Maybe()
+	mockHistory.On("GetAll", int64(1), mock.MatchedBy(func(f data.Filters) bool { return f.Cursor == "bad" })).
+		Return(nil, data.Metadata{}, data.ErrInvalidCursor).Maybe()
+
+	app.models.History = mockHistory
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+	}{
+		{
+			name:     "First page",
+			urlPath:  "/v1/users/me/history",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Invalid cursor",
+			urlPath:  "/v1/users/me/history?cursor=bad",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Page size too big",
+			urlPath:  "/v1/users/me/history?page_size=101",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusOK {
+				var resp HistoryResponse
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+				assert.Len(t, resp.History, 1)
+				assert.Equal(t, "next", resp.Metadata.NextCursor)
+			}
+		})
+	}
+}
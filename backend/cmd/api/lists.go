@@ -0,0 +1,499 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/validate"
+)
+
+type listInput struct {
+	Name        string `json:"name" example:"Best 90s thrillers"`
+	Description string `json:"description" example:"Twists guaranteed"`
+	Visibility  string `json:"visibility" example:"public"`
+}
+
+type listItemInput struct {
+	MovieID int64  `json:"movie_id" example:"1"`
+	Note    string `json:"note" example:"the ending"`
+}
+
+type listItemsInput struct {
+	Items []listItemInput `json:"items"`
+}
+
+type ListsResponse struct {
+	Lists    []data.List   `json:"lists"`
+	Metadata data.Metadata `json:"metadata"`
+}
+
+func validateList(list *data.List) error {
+	return validation.ValidateStruct(list,
+		validation.Field(&list.Name, validation.Required, validation.RuneLength(1, 200)),
+		validation.Field(&list.Description, validation.RuneLength(0, 2000), validate.NoLinks),
+		validation.Field(&list.Visibility, validation.Required, validation.In(data.ListVisibilities...)),
+	)
+}
+
+// readOwnList loads the list from the URL to be changed by the authenticated user.
+// It writes the error response itself and returns nil if the list can't be changed:
+// lists other users can't read are not found, readable lists of other users are forbidden.
+func (app *application) readOwnList(w http.ResponseWriter, r *http.Request) *data.List {
+	id, err := app.readNamedIDParam(r, "listID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return nil
+	}
+
+	list, err := app.models.Lists.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return nil
+	}
+
+	user := app.contextGetUser(r)
+
+	switch {
+	case !list.VisibleTo(user.ID):
+		app.notFoundResponse(w, r)
+		return nil
+	case list.UserID != user.ID:
+		app.notPermittedResponse(w, r)
+		return nil
+	}
+
+	return list
+}
+
+// SearchLists godoc
+//
+// @Summary Search public lists
+// @Description Returns a page of public lists, unlisted and private lists are never found
+// @Tags lists
+// @Produce json
+// @Param name query string false "Part of the list name"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by name, updated_at (prefix with - for descending)" default(-updated_at)
+// @Security BearerAuth
+// @Success 200 {object} ListsResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /lists [get]
+func (app *application) searchListsHandler(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	name := strings.TrimSpace(app.readString(qs, "name", ""))
+
+	err := validation.Validate(name, validation.RuneLength(0, 200))
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("name: %w", err))
+		return
+	}
+
+	filters, err := app.readFilters(qs, "-updated_at", []string{"name", "updated_at", "-name", "-updated_at"})
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	lists, metadata, err := app.models.Lists.Search(name, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"lists": lists, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ListUserLists godoc
+//
+// @Summary List my lists
+// @Description Returns a page of lists of the authenticated user of any visibility
+// @Tags lists
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by name, created_at, updated_at (prefix with - for descending)" default(-updated_at)
+// @Security BearerAuth
+// @Success 200 {object} ListsResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/lists [get]
+func (app *application) listUserListsHandler(w http.ResponseWriter, r *http.Request) {
+	filters, err := app.readFilters(r.URL.Query(), "-updated_at",
+		[]string{"name", "created_at", "updated_at", "-name", "-created_at", "-updated_at"})
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	lists, metadata, err := app.models.Lists.GetAllForUser(app.contextGetUser(r).ID, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"lists": lists, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GetList godoc
+//
+// @Summary Get a list by ID
+// @Description Returns a list with its items in list order. Public and unlisted lists don't require authentication,
+// @Description private lists are only readable by their owner
+// @Tags lists
+// @Produce json
+// @Param listID path int true "List ID"
+// @Success 200 {object} data.List
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /lists/{listID} [get]
+func (app *application) getListHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "listID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	list, err := app.models.Lists.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	// anonymous users have zero id, so they only see lists that aren't private
+	if !list.VisibleTo(app.contextGetUser(r).ID) {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"list": list}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CreateList godoc
+//
+// @Summary Create a list
+// @Description Adds an empty list of the authenticated user, items are added with PUT /lists/{listID}/items
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param list body listInput true "List payload, visibility is private, unlisted or public (default private)"
+// @Security BearerAuth
+// @Success 201 {object} data.List
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /lists [post]
+func (app *application) createListHandler(w http.ResponseWriter, r *http.Request) {
+	var input listInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Visibility == "" {
+		input.Visibility = data.ListPrivate
+	}
+
+	list := &data.List{
+		UserID:      app.contextGetUser(r).ID,
+		Name:        strings.TrimSpace(input.Name),
+		Description: strings.TrimSpace(input.Description),
+		Visibility:  input.Visibility,
+	}
+
+	err = validateList(list)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Lists.Insert(list)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	app.recordActivity(r, &data.Activity{UserID: list.UserID, Type: data.ActivityList, ListID: &list.ID})
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"list": list}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateList godoc
+//
+// @Summary Update a list
+// @Description Changes name, description and/or visibility of a list of the authenticated user
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param listID path int true "List ID"
+// @Param list body listInput true "Partial list payload"
+// @Security BearerAuth
+// @Success 200 {object} data.List
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /lists/{listID} [patch]
+func (app *application) updateListHandler(w http.ResponseWriter, r *http.Request) {
+	list := app.readOwnList(w, r)
+	if list == nil {
+		return
+	}
+
+	// using pointers here to be able to compare which field was empty
+	var input struct {
+		Name        *string `json:"name"`
+		Description *string `json:"description"`
+		Visibility  *string `json:"visibility"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Name != nil {
+		list.Name = strings.TrimSpace(*input.Name)
+	}
+
+	if input.Description != nil {
+		list.Description = strings.TrimSpace(*input.Description)
+	}
+
+	if input.Visibility != nil {
+		list.Visibility = *input.Visibility
+	}
+
+	err = validateList(list)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Lists.Update(list)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"list": list}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteList godoc
+//
+// @Summary Delete a list
+// @Description Deletes a list of the authenticated user, copies other users made are kept
+// @Tags lists
+// @Produce json
+// @Param listID path int true "List ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "list successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /lists/{listID} [delete]
+func (app *application) deleteListHandler(w http.ResponseWriter, r *http.Request) {
+	list := app.readOwnList(w, r)
+	if list == nil {
+		return
+	}
+
+	err := app.models.Lists.Delete(list.ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "list successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// SetListItems godoc
+//
+// @Summary Set list items
+// @Description Replaces items of a list of the authenticated user, the order of items is the order of the list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param listID path int true "List ID"
+// @Param items body listItemsInput true "Ordered items with optional notes"
+// @Security BearerAuth
+// @Success 200 {object} data.List
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "items: movie doesn't exist"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /lists/{listID}/items [put]
+func (app *application) setListItemsHandler(w http.ResponseWriter, r *http.Request) {
+	var input listItemsInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	items := make([]*data.ListItem, 0, len(input.Items))
+	movieIDs := make([]int64, 0, len(input.Items))
+
+	for _, item := range input.Items {
+		items = append(items, &data.ListItem{MovieID: item.MovieID, Note: strings.TrimSpace(item.Note)})
+		movieIDs = append(movieIDs, item.MovieID)
+	}
+
+	err = validation.Validate(items, validation.Length(0, 500), validation.By(validate.Unique(movieIDs)),
+		validation.Each(validation.By(func(value interface{}) error {
+			item := value.(*data.ListItem)
+
+			return validation.ValidateStruct(item,
+				validation.Field(&item.MovieID, validation.Required, validation.Min(int64(1))),
+				validation.Field(&item.Note, validation.RuneLength(0, 1000), validate.NoLinks),
+			)
+		})),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, fmt.Errorf("items: %w", err))
+		return
+	}
+
+	list := app.readOwnList(w, r)
+	if list == nil {
+		return
+	}
+
+	err = app.models.Lists.SetItems(list, items)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrUnknownMovie):
+			app.failedValidationResponse(w, r, fmt.Errorf("items: %w", err))
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	app.recordActivity(r, &data.Activity{UserID: list.UserID, Type: data.ActivityList, ListID: &list.ID})
+
+	list, err = app.models.Lists.Get(list.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"list": list}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CopyList godoc
+//
+// @Summary Copy a list
+// @Description Creates a private copy of a list readable by the authenticated user, items and notes included
+// @Tags lists
+// @Produce json
+// @Param listID path int true "List ID"
+// @Security BearerAuth
+// @Success 201 {object} data.List
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /lists/{listID}/copy [post]
+func (app *application) copyListHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "listID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	source, err := app.models.Lists.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	user := app.contextGetUser(r)
+
+	if !source.VisibleTo(user.ID) {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	list, err := app.models.Lists.Copy(source, user.ID)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"list": list}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
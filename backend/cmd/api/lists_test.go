@@ -0,0 +1,231 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+// newListsMock returns a lists mock with a public list 1 of the test user,
+// a public list 2 of another user and a private list 3 of another user.
+func newListsMock(t *testing.T) *mocks.ListsInterface {
+	mockLists := mocks.NewListsInterface(t)
+
+	mockLists.On("Get", int64(1)).Return(func(int64) (*data.List, error) {
+		return &data.List{ID: 1, UserID: 1, Name: "Best 90s thrillers", Visibility: data.ListPublic, Version: 1}, nil
+	}).Maybe()
+	mockLists.On("Get", int64(2)).Return(func(int64) (*data.List, error) {
+		return &data.List{ID: 2, UserID: 2, Name: "Comfort movies", Visibility: data.ListPublic, Version: 1}, nil
+	}).Maybe()
+	mockLists.On("Get", int64(3)).Return(func(int64) (*data.List, error) {
+		return &data.List{ID: 3, UserID: 2, Name: "Guilty pleasures", Visibility: data.ListPrivate, Version: 1}, nil
+	}).Maybe()
+	mockLists.On("Get", int64(4)).Return(nil, data.ErrRecordNotFound).Maybe()
+
+	return mockLists
+}
+
+func TestGetListHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	app.models.Lists = newListsMock(t)
+
+	tests := []struct {
+		name          string
+		urlPath       string
+		wantCode      int
+		wantAnonymous int
+	}{
+		{
+			name:          "Own public list",
+			urlPath:       "/v1/lists/1",
+			wantCode:      http.StatusOK,
+			wantAnonymous: http.StatusOK,
+		},
+		{
+			name:          "Private list of another user",
+			urlPath:       "/v1/lists/3",
+			wantCode:      http.StatusNotFound,
+			wantAnonymous: http.StatusNotFound,
+		},
+		{
+			name:          "Non-existent list",
+			urlPath:       "/v1/lists/4",
+			wantCode:      http.StatusNotFound,
+			wantAnonymous: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.get(t, tt.urlPath)
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+
+			rs, err := ts.Client().Get(ts.URL + tt.urlPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rs.Body.Close()
+
+			assert.Equal(t, tt.wantAnonymous, rs.StatusCode, fmt.Sprintf("anonymous status code should be %d", tt.wantAnonymous))
+		})
+	}
+}
+
+func TestUpdateListHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockLists := newListsMock(t)
+	mockLists.On("Update", mock.MatchedBy(func(l *data.List) bool { return l.ID == 1 })).Return(nil).Maybe()
+
+	app.models.Lists = mockLists
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Own list",
+			urlPath:  "/v1/lists/1",
+			body:     `{"visibility": "unlisted"}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Invalid visibility",
+			urlPath:  "/v1/lists/1",
+			body:     `{"visibility": "friends"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Blank name",
+			urlPath:  "/v1/lists/1",
+			body:     `{"name": "  "}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Public list of another user",
+			urlPath:  "/v1/lists/2",
+			body:     `{"name": "Mine now"}`,
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "Private list of another user",
+			urlPath:  "/v1/lists/3",
+			body:     `{"name": "Mine now"}`,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.send(t, http.MethodPatch, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestSetListItemsHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockLists := newListsMock(t)
+	mockLists.On("SetItems", mock.Anything, mock.MatchedBy(func(items []*data.ListItem) bool {
+		return len(items) == 2 && items[0].MovieID == 10 && items[0].Note == "the ending"
+	})).Return(nil).Maybe()
+	mockLists.On("SetItems", mock.Anything, mock.MatchedBy(func(items []*data.ListItem) bool {
+		return len(items) == 1 && items[0].MovieID == 999
+	})).Return(data.ErrUnknownMovie).Maybe()
+
+	app.models.Lists = mockLists
+	app.models.Activities = newActivitiesMock(t)
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Valid items",
+			body:     `{"items": [{"movie_id": 10, "note": " the ending "}, {"movie_id": 11}]}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Unknown movie",
+			body:     `{"items": [{"movie_id": 999}]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Repeated movie",
+			body:     `{"items": [{"movie_id": 10}, {"movie_id": 10}]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Link in note",
+			body:     `{"items": [{"movie_id": 10, "note": "see www.example.com"}]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.send(t, http.MethodPut, "/v1/lists/1/items", nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestCopyListHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockLists := newListsMock(t)
+	mockLists.On("Copy", mock.MatchedBy(func(l *data.List) bool { return l.ID == 2 }), int64(1)).
+		Return(&data.List{ID: 5, UserID: 1, Name: "Comfort movies", Visibility: data.ListPrivate}, nil).Once()
+
+	app.models.Lists = mockLists
+
+	code, _, _ := ts.post(t, "/v1/lists/2/copy", nil)
+	assert.Equal(t, http.StatusCreated, code, "status code should be 201")
+
+	code, _, _ = ts.post(t, "/v1/lists/3/copy", nil)
+	assert.Equal(t, http.StatusNotFound, code, "status code should be 404")
+}
+
+func TestSearchListsHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockLists := mocks.NewListsInterface(t)
+	mockLists.On("Search", "thrillers", mock.AnythingOfType("data.Filters")).
+		Return([]*data.List{{ID: 1, Name: "Best 90s thrillers", Visibility: data.ListPublic}}, data.Metadata{}, nil).Once()
+
+	app.models.Lists = mockLists
+
+	code, _, _ := ts.get(t, "/v1/lists?name=thrillers")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	code, _, _ = ts.get(t, "/v1/lists?sort=visibility")
+	assert.Equal(t, http.StatusUnprocessableEntity, code, "status code should be 422")
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
 	"fmt"
 	"log/slog"
@@ -83,6 +84,18 @@ type config struct {
 		secretKey      string
 		secretKeyBytes []byte
 	}
+	trash struct {
+		retention     time.Duration
+		purgeInterval time.Duration
+	}
+	conditional struct {
+		requireIfMatch bool
+	}
+	events struct {
+		bufferSize    int
+		batchSize     int
+		flushInterval time.Duration
+	}
 }
 
 func main() {
@@ -107,6 +120,15 @@ func main() {
 
 	flag.StringVar(&cfg.jwt.secretKey, "jwt-secret", "", "Secret key for signing and verifying JWT tokens")
 
+	flag.DurationVar(&cfg.trash.retention, "trash-retention", 30*24*time.Hour, "How long deleted movies are kept in trash before purging")
+	flag.DurationVar(&cfg.trash.purgeInterval, "trash-purge-interval", time.Hour, "How often movies are purged from trash")
+
+	flag.BoolVar(&cfg.conditional.requireIfMatch, "require-if-match", true, "Require If-Match header on movie updates and deletes")
+
+	flag.IntVar(&cfg.events.bufferSize, "events-buffer-size", 10000, "Maximum number of feedback events buffered before writing")
+	flag.IntVar(&cfg.events.batchSize, "events-batch-size", 500, "Number of feedback events written in one query")
+	flag.DurationVar(&cfg.events.flushInterval, "events-flush-interval", 5*time.Second, "How often buffered feedback events are written")
+
 	displayVersion := flag.Bool("version", false, "Display version and quit")
 
 	flag.Parse()
@@ -125,6 +147,12 @@ func main() {
 
 	ctx := context.Background()
 
+	err := validateConfig(cfg)
+	if err != nil {
+		logger.Log(ctx, LevelFatal, "invalid configuration: "+err.Error())
+		os.Exit(1)
+	}
+
 	db, err := openDB(cfg)
 	if err != nil {
 		logger.Log(ctx, LevelFatal, err.Error())
@@ -171,6 +199,27 @@ func main() {
 	}
 }
 
+// validateConfig checks flag values that would otherwise break the server only once it is running.
+func validateConfig(cfg config) error {
+	if cfg.trash.purgeInterval <= 0 {
+		return errors.New("trash-purge-interval must be positive")
+	}
+
+	if cfg.events.flushInterval <= 0 {
+		return errors.New("events-flush-interval must be positive")
+	}
+
+	if cfg.events.batchSize <= 0 {
+		return errors.New("events-batch-size must be positive")
+	}
+
+	if cfg.events.bufferSize <= 0 {
+		return errors.New("events-buffer-size must be positive")
+	}
+
+	return nil
+}
+
 // openDB opens a database connection pool
 func openDB(cfg config) (*sql.DB, error) {
 	db, err := sql.Open("postgres", cfg.db.dsn)
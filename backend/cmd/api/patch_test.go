@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestUpdateMovieHandlerPatch(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockGenres := mocks.NewGenresInterface(t)
+
+	mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+		return &data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3}, nil
+	})
+	mockMovies.On("Update", mock.AnythingOfType("*data.Movie"), int64(1)).Return(nil).Maybe()
+	mockGenres.On("Resolve", mock.Anything).Return(func(genres []string) ([]string, []string, error) {
+		return genres, []string{}, nil
+	}).Maybe()
+
+	app.models.Movies = mockMovies
+	app.models.Genres = mockGenres
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		wantCode    int
+		wantBody    *data.Movie
+	}{
+		{
+			name:        "Plain JSON",
+			contentType: "application/json",
+			body:        `{"title": "New Title"}`,
+			wantCode:    http.StatusOK,
+			wantBody:    &data.Movie{ID: 1, Title: "New Title", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3},
+		},
+		{
+			name:        "Merge patch",
+			contentType: "application/merge-patch+json",
+			body:        `{"year": 2020, "genres": ["Drama", "Crime"]}`,
+			wantCode:    http.StatusOK,
+			wantBody:    &data.Movie{ID: 1, Title: "Test Movie", Year: 2020, Runtime: 125, Genres: []string{"Drama", "Crime"}, Version: 3},
+		},
+		{
+			name:        "Merge patch clearing required field",
+			contentType: "application/merge-patch+json",
+			body:        `{"runtime": null}`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "Merge patch with unknown field",
+			contentType: "application/merge-patch+json",
+			body:        `{"rating": 5}`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "JSON Patch",
+			contentType: "application/json-patch+json",
+			body:        `[{"op": "test", "path": "/title", "value": "Test Movie"}, {"op": "add", "path": "/genres/-", "value": "Crime"}]`,
+			wantCode:    http.StatusOK,
+			wantBody:    &data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama", "Crime"}, Version: 3},
+		},
+		{
+			name:        "JSON Patch failed test",
+			contentType: "application/json-patch+json",
+			body:        `[{"op": "test", "path": "/title", "value": "Other Movie"}, {"op": "replace", "path": "/title", "value": "New Title"}]`,
+			wantCode:    http.StatusConflict,
+		},
+		{
+			name:        "JSON Patch missing path",
+			contentType: "application/json-patch+json",
+			body:        `[{"op": "remove", "path": "/genres/5"}]`,
+			wantCode:    http.StatusUnprocessableEntity,
+		},
+		{
+			name:        "JSON Patch invalid operation",
+			contentType: "application/json-patch+json",
+			body:        `[{"op": "frobnicate", "path": "/title"}]`,
+			wantCode:    http.StatusBadRequest,
+		},
+		{
+			name:        "Unsupported content type",
+			contentType: "text/plain",
+			body:        `title=New Title`,
+			wantCode:    http.StatusUnsupportedMediaType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{"Content-Type": {tt.contentType}}
+
+			code, _, body := ts.send(t, http.MethodPatch, "/v1/movie/1", header, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != nil {
+				var resp map[string]data.Movie
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.wantBody, resp["movie"], "movie should be patched")
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type ratingInput struct {
+	Score int32 `json:"score" example:"9"`
+}
+
+type RatingsListResponse struct {
+	Ratings  []data.Rating `json:"ratings"`
+	Metadata data.Metadata `json:"metadata"`
+}
+
+// PutRating godoc
+//
+// @Summary Rate a movie
+// @Description Sets the score of the authenticated user for the movie, a previous score is replaced
+// @Tags ratings
+// @Accept json
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param rating body ratingInput true "Score from 1 to 10"
+// @Security BearerAuth
+// @Success 200 {object} data.Rating
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/rating [put]
+func (app *application) putRatingHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input ratingInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Score, validation.Required, validation.Min(int32(1)), validation.Max(int32(10))),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	rating := &data.Rating{
+		MovieID: id,
+		Score:   input.Score,
+	}
+
+	err = app.models.Ratings.Upsert(app.contextGetUser(r).ID, rating)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	app.recordActivity(r, &data.Activity{
+		UserID:  app.contextGetUser(r).ID,
+		Type:    data.ActivityRating,
+		MovieID: &rating.MovieID,
+		Score:   &rating.Score,
+	})
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"rating": rating}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteRating godoc
+//
+// @Summary Remove a movie rating
+// @Description Removes the score of the authenticated user for the movie
+// @Tags ratings
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "rating successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/rating [delete]
+func (app *application) deleteRatingHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Ratings.Delete(app.contextGetUser(r).ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "rating successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ListUserRatings godoc
+//
+// @Summary List my ratings
+// @Description Returns a page of movies rated by the authenticated user with their scores
+// @Tags ratings
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by rated_at, score, title, year (prefix with - for descending)" default(-rated_at)
+// @Security BearerAuth
+// @Success 200 {object} RatingsListResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/ratings [get]
+func (app *application) listUserRatingsHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.Sort = app.readString(qs, "sort", "-rated_at")
+	filters.SortSafeList = []string{"rated_at", "score", "title", "year", "-rated_at", "-score", "-title", "-year"}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Sort, validation.Required, validation.In(filters.SortSafeList...)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	ratings, metadata, err := app.models.Ratings.GetAllForUser(app.contextGetUser(r).ID, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"ratings": ratings, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
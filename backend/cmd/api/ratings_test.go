@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestRatingChangesMovieETag(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockRatings := mocks.NewRatingsInterface(t)
+
+	ratingCount := int32(0)
+
+	mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+		movie := &data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Genres: []string{"Drama"}, Version: 3, RatingCount: ratingCount}
+		if ratingCount > 0 {
+			movie.Rating = 9
+		}
+
+		return movie, nil
+	})
+	mockRatings.On("Upsert", int64(1), mock.AnythingOfType("*data.Rating")).Return(func(int64, *data.Rating) error {
+		ratingCount++
+		return nil
+	})
+
+	app.models.Movies = mockMovies
+	app.models.Ratings = mockRatings
+	app.models.Watchlist = newWatchlistMock(t)
+	app.models.History = newHistoryMock(t)
+	app.models.Activities = newActivitiesMock(t)
+
+	_, header, _ := ts.get(t, "/v1/movie/1")
+	etag := header.Get("ETag")
+
+	code, _, _ := ts.send(t, http.MethodPut, "/v1/movie/1/rating", nil, strings.NewReader(`{"score": 9}`))
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	code, header, body := ts.send(t, http.MethodGet, "/v1/movie/1", http.Header{"If-None-Match": {etag}}, nil)
+	assert.Equal(t, http.StatusOK, code, "rated movie should not be reported as not modified")
+	assert.NotEqual(t, etag, header.Get("ETag"), "etag should change")
+	assert.Contains(t, string(body), `"rating_count": 1`)
+}
+
+func TestPutRatingHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockRatings := mocks.NewRatingsInterface(t)
+
+	mockRatings.On("Upsert", int64(1), mock.MatchedBy(func(r *data.Rating) bool { return r.MovieID == 1 })).
+		Return(func(_ int64, rating *data.Rating) error {
+			rating.Title = "Test Movie"
+			rating.RatedAt = time.Now()
+			return nil
+		}).Maybe()
+	mockRatings.On("Upsert", int64(1), mock.MatchedBy(func(r *data.Rating) bool { return r.MovieID == 2 })).
+		Return(data.ErrRecordNotFound).Maybe()
+
+	app.models.Ratings = mockRatings
+	app.models.Activities = newActivitiesMock(t)
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Valid score",
+			urlPath:  "/v1/movie/1/rating",
+			body:     `{"score": 9}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Score too high",
+			urlPath:  "/v1/movie/1/rating",
+			body:     `{"score": 11}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing score",
+			urlPath:  "/v1/movie/1/rating",
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Non-existent movie",
+			urlPath:  "/v1/movie/2/rating",
+			body:     `{"score": 5}`,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.send(t, http.MethodPut, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusOK {
+				var resp map[string]data.Rating
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, int32(9), resp["rating"].Score)
+				assert.Equal(t, "Test Movie", resp["rating"].Title)
+			}
+		})
+	}
+}
+
+func TestDeleteRatingHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockRatings := mocks.NewRatingsInterface(t)
+
+	mockRatings.On("Delete", int64(1), int64(1)).Return(nil)
+	mockRatings.On("Delete", int64(1), int64(2)).Return(data.ErrRecordNotFound)
+
+	app.models.Ratings = mockRatings
+
+	code, _, _ := ts.delete(t, "/v1/movie/1/rating")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	code, _, _ = ts.delete(t, "/v1/movie/2/rating")
+	assert.Equal(t, http.StatusNotFound, code, "status code should be 404")
+}
+
+func TestListUserRatingsHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockRatings := mocks.NewRatingsInterface(t)
+
+	ratings := []*data.Rating{
+		{MovieID: 1, Title: "Test Movie", Year: 2020, Score: 9},
+	}
+
+	mockRatings.On("GetAllForUser", int64(1), mock.MatchedBy(func(f data.Filters) bool { return f.Sort == "-rated_at" })).
+		Return(ratings, data.Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1, TotalRecords: 1}, nil).Maybe()
+	mockRatings.On("GetAllForUser", int64(1), mock.MatchedBy(func(f data.Filters) bool { return f.Sort == "-score" })).
+		Return(ratings, data.Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1, TotalRecords: 1}, nil).Maybe()
+
+	app.models.Ratings = mockRatings
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+	}{
+		{
+			name:     "Default sort",
+			urlPath:  "/v1/users/me/ratings",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Sorted by score",
+			urlPath:  "/v1/users/me/ratings?sort=-score",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Unknown sort",
+			urlPath:  "/v1/users/me/ratings?sort=runtime",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusOK {
+				var resp RatingsListResponse
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Len(t, resp.Ratings, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/genproto/common"
+	pb "github.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+const (
+	// maxRecommendations is how many recommendations are paged through. The list is not stored, every page
+	// request recomputes it, so pages only line up while the profile, exclusions and dismissals stay the same.
+	// A rating, watch or dismissal between two requests can shift the list and repeat or skip movies.
+	maxRecommendations = 100
+	// profileSize is how many of the latest rated and seen movies make up the user's taste profile.
+	profileSize = 200
+	// maxRecommendAttempts is how many times the recommendation service is asked to fill up recommendations.
+	maxRecommendAttempts = 3
+)
+
+// fetchRecommendations asks the recommendation service for count recommendations,
+// leaving out movies with the exclude TMDB ids.
+type fetchRecommendations func(count int, exclude []int64) ([]*common.Recommendation, error)
+
+// scoredRecommendation is a recommended catalog movie or series.
+type scoredRecommendation interface {
+	score() float64
+}
+
+func (r *MovieRecommendation) score() float64 { return r.Score }
+
+func (r *SeriesRecommendation) score() float64 { return r.Score }
+
+// fillRecommendations collects up to topK recommendations load keeps, by the score.
+// Recommendations missing from the catalog or dismissed would leave the list short, so twice as many are
+// fetched and the service is asked again for the rest, excluding the TMDB ids it already recommended,
+// up to maxRecommendAttempts times or until it runs out of recommendations.
+func fillRecommendations[T scoredRecommendation](topK int, fetch fetchRecommendations, load func([]*common.Recommendation) ([]T, error)) ([]T, error) {
+	kept := []T{}
+
+	var recommended []int64
+
+	for attempt := 0; attempt < maxRecommendAttempts && len(kept) < topK; attempt++ {
+		count := 2 * (topK - len(kept))
+
+		recommendations, err := fetch(count, recommended)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, recommendation := range recommendations {
+			if id := recommendation.GetMovieId(); id != 0 {
+				recommended = append(recommended, id)
+			}
+		}
+
+		loaded, err := load(recommendations)
+		if err != nil {
+			return nil, err
+		}
+
+		kept = append(kept, loaded...)
+
+		// the service has no more movies to recommend
+		if len(recommendations) < count {
+			break
+		}
+	}
+
+	sort.SliceStable(kept, func(i, j int) bool {
+		return kept[i].score() > kept[j].score()
+	})
+
+	return kept[:min(topK, len(kept))], nil
+}
+
+// loadMovieRecommendations returns a loader of recommended catalog movies the user hasn't dismissed.
+func (app *application) loadMovieRecommendations(userID int64) func([]*common.Recommendation) ([]*MovieRecommendation, error) {
+	return func(recommendations []*common.Recommendation) ([]*MovieRecommendation, error) {
+		movieRecommendations, err := app.hydrateRecommendations(recommendations)
+		if err != nil {
+			return nil, err
+		}
+
+		return app.suppressDismissed(userID, movieRecommendations)
+	}
+}
+
+type RecommendationsResponse struct {
+	Recommendations []*MovieRecommendation `json:"recommendations"`
+	Metadata        data.Metadata          `json:"metadata"`
+}
+
+// ListRecommendations godoc
+//
+// @Summary Get recommendations for me
+// @Description Recommends movies for the taste profile of the authenticated user built from ratings and watch history.
+// @Description Movies the user rated, has seen or dismissed are left out, movies of dismissed genres get lower scores.
+// @Description Users without ratings and history get no recommendations yet. Up to 100 recommendations are paged.
+// @Description The list is recomputed for every page, so pages can repeat or skip movies when ratings, history
+// @Description or dismissals change in between. Recommended movies are returned from the catalog, matched by TMDB id
+// @Tags recommendations
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Security BearerAuth
+// @Success 200 {object} RecommendationsResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/recommendations [get]
+func (app *application) listRecommendationsHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Page     int
+		PageSize int
+	}
+
+	qs := r.URL.Query()
+
+	var err error
+
+	input.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	input.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	// pages past the last one that can hold recommendations are rejected
+	lastPage := 1
+	if input.PageSize > 0 {
+		lastPage = int(math.Ceil(float64(maxRecommendations) / float64(input.PageSize)))
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Page, validation.Required, validation.Min(1), validation.Max(lastPage)),
+		validation.Field(&input.PageSize, validation.Required, validation.Min(1), validation.Max(50)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	userID := app.contextGetUser(r).ID
+
+	profile, err := app.models.Recommendations.Profile(userID, profileSize)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	recommendations := []*MovieRecommendation{}
+
+	if len(profile) > 0 {
+		recommendations, err = app.recommendForUser(userID, profile)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+	}
+
+	metadata := data.Metadata{}
+	if len(recommendations) > 0 {
+		metadata = data.Metadata{
+			CurrentPage:  input.Page,
+			PageSize:     input.PageSize,
+			FirstPage:    1,
+			LastPage:     int(math.Ceil(float64(len(recommendations)) / float64(input.PageSize))),
+			TotalRecords: len(recommendations),
+		}
+	}
+
+	start := min((input.Page-1)*input.PageSize, len(recommendations))
+	end := min(start+input.PageSize, len(recommendations))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"recommendations": recommendations[start:end], "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// recommendForUser asks the recommendation service for catalog movies matching the profile,
+// leaving out movies the user shouldn't get and applying dismissed genres.
+func (app *application) recommendForUser(userID int64, profile []*data.ProfileMovie) ([]*MovieRecommendation, error) {
+	excluded, err := app.models.Recommendations.Excluded(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &pb.UserRecommendRequest{}
+
+	for _, movie := range profile {
+		request.Profile = append(request.Profile, &pb.ProfileMovie{MovieId: movie.MovieID, Weight: movie.Weight})
+	}
+
+	client := pb.NewRecommendationClient(app.grpcConn)
+
+	return fillRecommendations(maxRecommendations, func(count int, exclude []int64) ([]*common.Recommendation, error) {
+		request.TopK = int32(count)
+		request.ExcludeMovieIds = slices.Concat(excluded, exclude)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		response, err := client.RecommendForUser(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return response.GetRecommendations(), nil
+	}, app.loadMovieRecommendations(userID))
+}
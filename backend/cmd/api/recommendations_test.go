@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/genproto/common"
+	pb "github.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// fakeRecommendationServer recommends movies named "Movie 1", "Movie 2", ... with TMDB ids 101, 102, ...
+// up to the requested number, skipping excluded ids, and keeps the requests.
+type fakeRecommendationServer struct {
+	pb.UnimplementedRecommendationServer
+	request           *pb.UserRecommendRequest
+	recommendRequest  *pb.RecommendRequest
+	recommendRequests []*pb.RecommendRequest
+}
+
+// recommend returns count recommendations leaving out the exclude ids.
+func (s *fakeRecommendationServer) recommend(count int32, exclude []int64) []*common.Recommendation {
+	recommendations := []*common.Recommendation{}
+
+	for id := int64(101); len(recommendations) < int(count); id++ {
+		if slices.Contains(exclude, id) {
+			continue
+		}
+
+		recommendations = append(recommendations, &common.Recommendation{
+			Title:   fmt.Sprintf("Movie %d", id-100),
+			Score:   1 / float64(id-100),
+			MovieId: id,
+		})
+	}
+
+	return recommendations
+}
+
+func (s *fakeRecommendationServer) Recommend(_ context.Context, request *pb.RecommendRequest) (*pb.RecommendResponse, error) {
+	s.recommendRequest = request
+	s.recommendRequests = append(s.recommendRequests, request)
+
+	return &pb.RecommendResponse{Recommendations: s.recommend(request.GetTopK(), request.GetFilter().GetExcludeMovieIds())}, nil
+}
+
+func (s *fakeRecommendationServer) RecommendForUser(_ context.Context, request *pb.UserRecommendRequest) (*pb.RecommendResponse, error) {
+	s.request = request
+
+	return &pb.RecommendResponse{Recommendations: s.recommend(request.GetTopK(), request.GetExcludeMovieIds())}, nil
+}
+
+// newCatalogMock returns a movies mock with a catalog movie "Movie N" with id N for every TMDB id 100+N
+// the fake recommendation service recommends, except for the missing TMDB ids.
+func newCatalogMock(t *testing.T, missing ...int64) *mocks.MoviesInterface {
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockMovies.On("GetAllByTMDBID", mock.Anything).Return(func(tmdbIDs []int64) ([]*data.Movie, error) {
+		movies := make([]*data.Movie, 0, len(tmdbIDs))
+		for _, id := range tmdbIDs {
+			if slices.Contains(missing, id) {
+				continue
+			}
+
+			movies = append(movies, &data.Movie{ID: id - 100, Title: fmt.Sprintf("Movie %d", id-100), ExternalIDs: &data.ExternalIDs{TMDB: id}})
+		}
+
+		return movies, nil
+	}).Maybe()
+
+	return mockMovies
+}
+
+// newTestRecommendationService starts the fake recommendation service and connects the application to it.
+func newTestRecommendationService(t *testing.T, app *application) *fakeRecommendationServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fake := &fakeRecommendationServer{}
+
+	srv := grpc.NewServer()
+	pb.RegisterRecommendationServer(srv, fake)
+
+	go func() {
+		_ = srv.Serve(listener)
+	}()
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	app.grpcConn = conn
+
+	return fake
+}
+
+func TestListRecommendationsHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	fake := newTestRecommendationService(t, app)
+
+	mockRecommendations := mocks.NewRecommendationsInterface(t)
+	mockRecommendations.On("Profile", int64(1), profileSize).
+		Return([]*data.ProfileMovie{{MovieID: 807, Weight: 1}}, nil)
+	mockRecommendations.On("Excluded", int64(1)).Return([]int64{807, 949}, nil)
+
+	mockDismissals := mocks.NewDismissalsInterface(t)
+	mockDismissals.On("Suppressions", int64(1), mock.Anything).Return(map[int64]data.Suppression{}, nil)
+
+	app.models.Recommendations = mockRecommendations
+	app.models.Dismissals = mockDismissals
+	app.models.Movies = newCatalogMock(t)
+
+	tests := []struct {
+		name      string
+		urlPath   string
+		wantCode  int
+		wantFirst string
+	}{
+		{
+			name:      "First page",
+			urlPath:   "/v1/users/me/recommendations?page_size=10",
+			wantCode:  http.StatusOK,
+			wantFirst: `"title": "Movie 1"`,
+		},
+		{
+			name:      "Third page",
+			urlPath:   "/v1/users/me/recommendations?page=3&page_size=10",
+			wantCode:  http.StatusOK,
+			wantFirst: `"id": 21`,
+		},
+		{
+			name:      "Last page",
+			urlPath:   "/v1/users/me/recommendations?page=10&page_size=10",
+			wantCode:  http.StatusOK,
+			wantFirst: `"id": 91`,
+		},
+		{
+			name:     "Page past the last one",
+			urlPath:  "/v1/users/me/recommendations?page=11&page_size=10",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Page size too large",
+			urlPath:  "/v1/users/me/recommendations?page_size=500",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+
+			if tt.wantFirst != "" {
+				assert.Contains(t, string(body), tt.wantFirst)
+				assert.Contains(t, string(body), `"total_records": 100`)
+			}
+		})
+	}
+
+	if assert.NotNil(t, fake.request, "recommendation service should be called") {
+		if assert.Len(t, fake.request.GetProfile(), 1) {
+			assert.Equal(t, int64(807), fake.request.GetProfile()[0].GetMovieId(), "profile should be sent by TMDB ids")
+		}
+		assert.Equal(t, []int64{807, 949}, fake.request.GetExcludeMovieIds(), "seen movies should be excluded")
+		assert.Equal(t, int32(2*maxRecommendations), fake.request.GetTopK(), "twice the recommendations should be requested")
+	}
+}
+
+func TestListRecommendationsWithoutProfile(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockRecommendations := mocks.NewRecommendationsInterface(t)
+	mockRecommendations.On("Profile", int64(1), profileSize).Return([]*data.ProfileMovie{}, nil).Once()
+
+	app.models.Recommendations = mockRecommendations
+
+	// no service is running, users without a profile must not reach it
+	code, _, body := ts.get(t, "/v1/users/me/recommendations")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+	assert.Contains(t, string(body), `"recommendations": []`)
+}
@@ -0,0 +1,458 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/validate"
+)
+
+type reviewInput struct {
+	Body    string `json:"body" example:"A slow but rewarding story about hope."`
+	Spoiler bool   `json:"spoiler" example:"false"`
+}
+
+type reviewVoteInput struct {
+	Helpful *bool `json:"helpful" example:"true"`
+}
+
+type reviewReportInput struct {
+	Reason string `json:"reason" example:"spam"`
+}
+
+type ReviewsListResponse struct {
+	Reviews  []data.Review `json:"reviews"`
+	Metadata data.Metadata `json:"metadata"`
+}
+
+type ReportedReviewsResponse struct {
+	Reviews  []data.ReportedReview `json:"reviews"`
+	Metadata data.Metadata         `json:"metadata"`
+}
+
+// ListMovieReviews godoc
+//
+// @Summary List movie reviews
+// @Description Returns a page of visible reviews of the movie with the authors' scores
+// @Tags reviews
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by helpfulness, created_at (prefix with - for descending)" default(-helpfulness)
+// @Security BearerAuth
+// @Success 200 {object} ReviewsListResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/reviews [get]
+func (app *application) listMovieReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	filters, err := app.readFilters(r.URL.Query(), "-helpfulness",
+		[]string{"helpfulness", "created_at", "-helpfulness", "-created_at"})
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	reviews, metadata, err := app.models.Reviews.GetAllForMovie(id, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"reviews": reviews, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// PutReview godoc
+//
+// @Summary Write a movie review
+// @Description Saves the review of the authenticated user for the movie, a previous review is replaced and keeps its votes
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param review body reviewInput true "Review text from 20 to 5000 characters without links"
+// @Security BearerAuth
+// @Success 201 {object} data.Review
+// @Success 200 {object} data.Review "The review already existed and is updated"
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"body": "must not contain links"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/review [put]
+func (app *application) putReviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input reviewInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	input.Body = strings.TrimSpace(input.Body)
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Body, validation.Required, validation.RuneLength(20, 5000), validate.NoLinks),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	review := &data.Review{
+		MovieID: id,
+		UserID:  app.contextGetUser(r).ID,
+		Body:    input.Body,
+		Spoiler: input.Spoiler,
+	}
+
+	created, err := app.models.Reviews.Upsert(review)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+
+		// edits of a review don't show up in the feed again
+		app.recordActivity(r, &data.Activity{
+			UserID:   review.UserID,
+			Type:     data.ActivityReview,
+			MovieID:  &review.MovieID,
+			ReviewID: &review.ID,
+		})
+	}
+
+	err = app.writeJSON(w, status, envelope{"review": review}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteReview godoc
+//
+// @Summary Delete my movie review
+// @Tags reviews
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "review successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/review [delete]
+func (app *application) deleteReviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Reviews.Delete(app.contextGetUser(r).ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "review successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// VoteReview godoc
+//
+// @Summary Vote for a review
+// @Description Marks the review as helpful or unhelpful, a previous vote is replaced. Authors can't vote for their own reviews
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param reviewID path int true "Review ID"
+// @Param vote body reviewVoteInput true "Vote"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "vote successfully saved"}"
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "review: own reviews can't be voted for or reported"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /reviews/{reviewID}/vote [put]
+func (app *application) voteReviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "reviewID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input reviewVoteInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Helpful, validation.NotNil),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Reviews.Vote(app.contextGetUser(r).ID, id, *input.Helpful)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, data.ErrOwnReview):
+			app.failedValidationResponse(w, r, fmt.Errorf("review: %w", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "vote successfully saved"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteReviewVote godoc
+//
+// @Summary Remove my vote for a review
+// @Tags reviews
+// @Produce json
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "vote successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /reviews/{reviewID}/vote [delete]
+func (app *application) deleteReviewVoteHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "reviewID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Reviews.Unvote(app.contextGetUser(r).ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "vote successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ReportReview godoc
+//
+// @Summary Report a review
+// @Description Sends the review to the moderation queue, reporting it again replaces the reason
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Param reviewID path int true "Review ID"
+// @Param report body reviewReportInput true "Reason of the report"
+// @Security BearerAuth
+// @Success 202 {object} map[string]string "Accepted | Example {"message": "review successfully reported"}"
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"reason": "cannot be blank"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /reviews/{reviewID}/report [post]
+func (app *application) reportReviewHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "reviewID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input reviewReportInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	input.Reason = strings.TrimSpace(input.Reason)
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Reason, validation.Required, validation.RuneLength(1, 500)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Reviews.Report(app.contextGetUser(r).ID, id, input.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, data.ErrOwnReview):
+			app.failedValidationResponse(w, r, fmt.Errorf("review: %w", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusAccepted, envelope{"message": "review successfully reported"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// ListReportedReviews godoc
+//
+// @Summary List the review moderation queue
+// @Description Returns a page of reviews with open reports, including already hidden ones
+// @Tags reviews
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by report_count, last_reported_at (prefix with - for descending)" default(-report_count)
+// @Security BearerAuth
+// @Success 200 {object} ReportedReviewsResponse
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /reviews/moderation [get]
+func (app *application) listReportedReviewsHandler(w http.ResponseWriter, r *http.Request) {
+	filters, err := app.readFilters(r.URL.Query(), "-report_count",
+		[]string{"report_count", "last_reported_at", "-report_count", "-last_reported_at"})
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	reviews, metadata, err := app.models.Reviews.GetReported(filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"reviews": reviews, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// HideReview godoc
+//
+// @Summary Hide a review
+// @Description Hides the review from movie reviews and resolves its open reports
+// @Tags reviews
+// @Produce json
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "review successfully hidden"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /reviews/{reviewID}/hide [post]
+func (app *application) hideReviewHandler(w http.ResponseWriter, r *http.Request) {
+	app.moderateReview(w, r, true, "review successfully hidden")
+}
+
+// RestoreReview godoc
+//
+// @Summary Restore a review
+// @Description Makes the review visible again and resolves its open reports, so reports can be dismissed too
+// @Tags reviews
+// @Produce json
+// @Param reviewID path int true "Review ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "review successfully restored"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /reviews/{reviewID}/restore [post]
+func (app *application) restoreReviewHandler(w http.ResponseWriter, r *http.Request) {
+	app.moderateReview(w, r, false, "review successfully restored")
+}
+
+// moderateReview hides or restores the review from the URL and responds with the message.
+func (app *application) moderateReview(w http.ResponseWriter, r *http.Request, hidden bool, message string) {
+	id, err := app.readNamedIDParam(r, "reviewID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Reviews.Moderate(id, hidden)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	app.logger.Info("review moderated",
+		slog.Int64("review_id", id),
+		slog.Bool("hidden", hidden),
+		slog.Int64("moderator_id", app.contextGetUser(r).ID),
+	)
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": message}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
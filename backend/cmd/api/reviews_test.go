@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestPutReviewHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockReviews := mocks.NewReviewsInterface(t)
+
+	mockReviews.On("Upsert", mock.MatchedBy(func(r *data.Review) bool { return r.MovieID == 1 && r.UserID == 1 })).
+		Return(true, nil).Maybe()
+	mockReviews.On("Upsert", mock.MatchedBy(func(r *data.Review) bool { return r.MovieID == 2 })).
+		Return(false, nil).Maybe()
+	mockReviews.On("Upsert", mock.MatchedBy(func(r *data.Review) bool { return r.MovieID == 3 })).
+		Return(false, data.ErrRecordNotFound).Maybe()
+
+	app.models.Reviews = mockReviews
+	app.models.Activities = newActivitiesMock(t)
+
+	validBody := "A slow but rewarding story about hope."
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "New review",
+			urlPath:  "/v1/movie/1/review",
+			body:     `{"body": "` + validBody + `", "spoiler": true}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Updated review",
+			urlPath:  "/v1/movie/2/review",
+			body:     `{"body": "` + validBody + `"}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Non-existent movie",
+			urlPath:  "/v1/movie/3/review",
+			body:     `{"body": "` + validBody + `"}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Too short",
+			urlPath:  "/v1/movie/1/review",
+			body:     `{"body": "   great movie   "}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Too long",
+			urlPath:  "/v1/movie/1/review",
+			body:     `{"body": "` + strings.Repeat("a", 5001) + `"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Contains link",
+			urlPath:  "/v1/movie/1/review",
+			body:     `{"body": "Watch it for free at https://example.com/movie"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.send(t, http.MethodPut, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestListMovieReviewsHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockReviews := mocks.NewReviewsInterface(t)
+
+	score := int32(9)
+
+	mockReviews.On("GetAllForMovie", int64(1), mock.MatchedBy(func(f data.Filters) bool { return f.Sort == "-helpfulness" })).
+		Return([]*data.Review{{ID: 1, MovieID: 1, UserID: 2, Body: "A slow but rewarding story about hope.", Score: &score}},
+			data.Metadata{}, nil).Maybe()
+	mockReviews.On("GetAllForMovie", int64(1), mock.MatchedBy(func(f data.Filters) bool { return f.Sort == "-created_at" })).
+		Return([]*data.Review{}, data.Metadata{}, nil).Maybe()
+
+	app.models.Reviews = mockReviews
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+	}{
+		{
+			name:     "Default sort by helpfulness",
+			urlPath:  "/v1/movie/1/reviews",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Sort by recency",
+			urlPath:  "/v1/movie/1/reviews?sort=-created_at",
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Invalid sort",
+			urlPath:  "/v1/movie/1/reviews?sort=body",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.name == "Default sort by helpfulness" {
+				var resp ReviewsListResponse
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+				assert.Len(t, resp.Reviews, 1)
+				assert.Equal(t, int32(9), *resp.Reviews[0].Score)
+			}
+		})
+	}
+}
+
+func TestVoteReviewHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockReviews := mocks.NewReviewsInterface(t)
+
+	mockReviews.On("Vote", int64(1), int64(1), true).Return(nil).Maybe()
+	mockReviews.On("Vote", int64(1), int64(2), false).Return(data.ErrOwnReview).Maybe()
+	mockReviews.On("Vote", int64(1), int64(3), true).Return(data.ErrRecordNotFound).Maybe()
+
+	app.models.Reviews = mockReviews
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Helpful",
+			urlPath:  "/v1/reviews/1/vote",
+			body:     `{"helpful": true}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Own review",
+			urlPath:  "/v1/reviews/2/vote",
+			body:     `{"helpful": false}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Hidden or non-existent review",
+			urlPath:  "/v1/reviews/3/vote",
+			body:     `{"helpful": true}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Missing vote",
+			urlPath:  "/v1/reviews/1/vote",
+			body:     `{}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.send(t, http.MethodPut, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestReportReviewHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockReviews := mocks.NewReviewsInterface(t)
+
+	mockReviews.On("Report", int64(1), int64(1), "spam").Return(nil)
+
+	app.models.Reviews = mockReviews
+
+	code, _, _ := ts.post(t, "/v1/reviews/1/report", strings.NewReader(`{"reason": " spam "}`))
+	assert.Equal(t, http.StatusAccepted, code, "status code should be 202")
+
+	code, _, _ = ts.post(t, "/v1/reviews/1/report", strings.NewReader(`{"reason": ""}`))
+	assert.Equal(t, http.StatusUnprocessableEntity, code, "status code should be 422")
+}
+
+func TestModerateReviewHandlers(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockReviews := mocks.NewReviewsInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionReviewsModerate}, nil)
+
+	mockReviews.On("GetReported", mock.AnythingOfType("data.Filters")).
+		Return([]*data.ReportedReview{{Review: data.Review{ID: 1}, ReportCount: 2, Reasons: []string{"spam", "insult"}}},
+			data.Metadata{}, nil).Once()
+	mockReviews.On("Moderate", int64(1), true).Return(nil).Once()
+	mockReviews.On("Moderate", int64(1), false).Return(nil).Once()
+	mockReviews.On("Moderate", int64(2), true).Return(data.ErrRecordNotFound).Once()
+
+	app.models.Reviews = mockReviews
+	app.models.Permissions = mockPermissions
+
+	code, _, body := ts.get(t, "/v1/reviews/moderation")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	var resp ReportedReviewsResponse
+
+	err := json.Unmarshal(body, &resp)
+	assert.NoError(t, err)
+	assert.Len(t, resp.Reviews, 1)
+	assert.Equal(t, int32(2), resp.Reviews[0].ReportCount)
+
+	code, _, _ = ts.post(t, "/v1/reviews/1/hide", nil)
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	code, _, _ = ts.post(t, "/v1/reviews/1/restore", nil)
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	code, _, _ = ts.post(t, "/v1/reviews/2/hide", nil)
+	assert.Equal(t, http.StatusNotFound, code, "status code should be 404")
+}
+
+func TestModerateReviewPermissions(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	// movie admins are not moderators
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	app.models.Permissions = mockPermissions
+
+	code, _, _ := ts.get(t, "/v1/reviews/moderation")
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+
+	code, _, _ = ts.post(t, "/v1/reviews/1/hide", nil)
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"net/http"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type MovieHistoryResponse struct {
+	Revisions []data.MovieRevision `json:"revisions"`
+	Metadata  data.Metadata        `json:"metadata"`
+}
+
+// MovieHistory godoc
+//
+// @Summary Movie revision history
+// @Description Retrieve movie revisions, newest first, each with field-level changes compared to the previous revision
+// @Tags movies
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(20)
+// @Security BearerAuth
+// @Success 200 {object} MovieHistoryResponse
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/history [get]
+func (app *application) movieHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	revisions, metadata, err := app.models.Movies.History(id, filters)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"revisions": revisions, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// RevertMovie godoc
+//
+// @Summary Revert a movie to a revision
+// @Description Sets movie fields to their values at the given version, the revert is saved as a new revision
+// @Tags movies
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param version path int true "Revision version"
+// @Param If-Match header string false "ETag of the reverted movie version (required unless disabled in config)"
+// @Security BearerAuth
+// @Success 200 {object} data.Movie
+// @Header 200 {string} ETag "New movie version as entity tag"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 412 {object} map[string]string "Precondition Failed | Example {"error": "the resource has been modified since you fetched it, please fetch it again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 428 {object} map[string]string "Precondition Required | Example {"error": "If-Match header with the resource ETag is required"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/revert/{version} [post]
+func (app *application) revertMovieHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	version, err := app.readNamedIDParam(r, "version")
+	if err != nil || version > math.MaxInt32 {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	movie, err := app.models.Movies.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	if !app.preconditionMet(w, r, movie.Version) {
+		return
+	}
+
+	revision, err := app.models.Movies.GetRevision(id, int32(version))
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	revision.Snapshot.Apply(movie)
+
+	// genres could have been renamed or merged since the revision, so they are resolved again
+	err = app.validateMovie(movie)
+	if err != nil {
+		var validationErrors validation.Errors
+
+		switch {
+		case errors.As(err, &validationErrors):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.models.Movies.Update(movie, app.contextGetUser(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict) && r.Header.Get("If-Match") != "":
+			app.preconditionFailedResponse(w, r)
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", movieETag(movie))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
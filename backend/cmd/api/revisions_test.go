@@ -0,0 +1,176 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestMovieHistoryHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+
+	userID := int64(1)
+	revisions := []*data.MovieRevision{
+		{ID: 2, MovieID: 1, Version: 2, Action: data.RevisionUpdate, UserID: &userID, Changes: []data.FieldChange{
+			{Field: "title", From: "Test Movi", To: "Test Movie"},
+		}},
+		{ID: 1, MovieID: 1, Version: 1, Action: data.RevisionCreate, UserID: &userID, Changes: []data.FieldChange{
+			{Field: "title", To: "Test Movi"},
+		}},
+	}
+
+	mockMovies.On("History", int64(1), mock.Anything).Return(revisions, data.Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1, TotalRecords: 2}, nil)
+	mockMovies.On("History", int64(2), mock.Anything).Return(nil, data.Metadata{}, data.ErrRecordNotFound)
+
+	app.models.Movies = mockMovies
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+		wantLen  int
+	}{
+		{
+			name:     "Movie with revisions",
+			urlPath:  "/v1/movie/1/history",
+			wantCode: http.StatusOK,
+			wantLen:  2,
+		},
+		{
+			name:     "Non-existent movie",
+			urlPath:  "/v1/movie/2/history",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Invalid page size",
+			urlPath:  "/v1/movie/1/history?page_size=1000",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantLen > 0 {
+				var resp MovieHistoryResponse
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Len(t, resp.Revisions, tt.wantLen, "all revisions should be returned")
+				assert.Equal(t, "title", resp.Revisions[0].Changes[0].Field, "changes should be returned")
+			}
+		})
+	}
+}
+
+func TestRevertMovieHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockGenres := mocks.NewGenresInterface(t)
+
+	current := data.Movie{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 3}
+	revision := data.MovieRevision{
+		MovieID:  1,
+		Version:  1,
+		Action:   data.RevisionCreate,
+		Snapshot: data.MovieSnapshot{Title: "Old Title", Year: 2023, Runtime: 120, Genres: []string{"drama"}},
+	}
+
+	mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+		movie := current
+		return &movie, nil
+	})
+	mockMovies.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound)
+	mockMovies.On("GetRevision", int64(1), int32(1)).Return(&revision, nil)
+	mockMovies.On("GetRevision", int64(1), int32(5)).Return(nil, data.ErrRecordNotFound)
+	mockMovies.On("Update", mock.MatchedBy(func(m *data.Movie) bool {
+		return m.Title == "Old Title" && m.Version == 3
+	}), int64(1)).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*data.Movie).Version = 4
+	})
+	mockGenres.On("Resolve", []string{"drama"}).Return([]string{"Drama"}, []string{}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Genres = mockGenres
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		ifMatch  string
+		wantCode int
+		wantBody *data.Movie
+	}{
+		{
+			name:     "Valid revision",
+			urlPath:  "/v1/movie/1/revert/1",
+			wantCode: http.StatusOK,
+			wantBody: &data.Movie{ID: 1, Title: "Old Title", Year: 2023, Runtime: 120, Genres: []string{"Drama"}, Version: 4},
+		},
+		{
+			name:     "Current If-Match",
+			urlPath:  "/v1/movie/1/revert/1",
+			ifMatch:  movieETag(&current),
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Stale If-Match",
+			urlPath:  "/v1/movie/1/revert/1",
+			ifMatch:  `"2"`,
+			wantCode: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "Non-existent revision",
+			urlPath:  "/v1/movie/1/revert/5",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Non-existent movie",
+			urlPath:  "/v1/movie/2/revert/1",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Invalid version",
+			urlPath:  "/v1/movie/1/revert/smth",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.ifMatch != "" {
+				header.Set("If-Match", tt.ifMatch)
+			}
+
+			code, _, body := ts.send(t, http.MethodPost, tt.urlPath, header, nil)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != nil {
+				var resp map[string]data.Movie
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.wantBody, resp["movie"], "movie should be reverted")
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/go-chi/httprate"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
 
 	_ "github.com/vladgrskkh/movie_recomendation_system/cmd/api/docs"
 )
@@ -33,12 +34,126 @@ func (app *application) routes() http.Handler {
 			r.Use(app.requireAuthenticatedUser)
 			r.Get("/", app.listMoviesHandler)
 			r.With(app.requireActivatedUser).Post("/", app.postMovieHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/batch", app.batchMoviesHandler)
 			r.Post("/predict", app.predictHandler)
+			r.Get("/suggest", app.suggestMoviesHandler)
+			r.Get("/lookup", app.lookupMovieHandler)
 
 			r.Route("/{movieID}", func(r chi.Router) {
 				r.Get("/", app.getMovieHandler)
 				r.With(app.requireActivatedUser).Patch("/", app.updateMovieHandler)
-				r.Delete("/", app.deleteMovieHandler)
+				r.With(app.requirePermission(data.PermissionMoviesAdmin)).Delete("/", app.deleteMovieHandler)
+				r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/restore", app.restoreMovieHandler)
+				r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/merge", app.mergeMovieHandler)
+				r.Get("/history", app.movieHistoryHandler)
+				r.With(app.requireActivatedUser).Post("/revert/{version}", app.revertMovieHandler)
+				r.Get("/translations", app.listTranslationsHandler)
+				r.With(app.requireActivatedUser).Put("/translations/{lang}", app.putTranslationHandler)
+				r.With(app.requireActivatedUser).Delete("/translations/{lang}", app.deleteTranslationHandler)
+				r.With(app.requireActivatedUser).Put("/external-ids", app.putExternalIDsHandler)
+				r.With(app.requireActivatedUser).Put("/rating", app.putRatingHandler)
+				r.With(app.requireActivatedUser).Delete("/rating", app.deleteRatingHandler)
+				r.Get("/reviews", app.listMovieReviewsHandler)
+				r.With(app.requireActivatedUser).Put("/review", app.putReviewHandler)
+				r.With(app.requireActivatedUser).Delete("/review", app.deleteReviewHandler)
+				r.With(app.requireActivatedUser).Put("/dismiss", app.dismissMovieHandler)
+				r.With(app.requireActivatedUser).Delete("/dismiss", app.undismissMovieHandler)
+			})
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+			r.Get("/movies/trash", app.listTrashHandler)
+		})
+
+		r.Route("/genres", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Get("/", app.listGenresHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/", app.createGenreHandler)
+
+			r.Route("/{genreID}", func(r chi.Router) {
+				r.Get("/", app.getGenreHandler)
+				r.With(app.requireActivatedUser).Put("/dismiss", app.dismissGenreHandler)
+				r.With(app.requireActivatedUser).Delete("/dismiss", app.undismissGenreHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+					r.Patch("/", app.updateGenreHandler)
+					r.Delete("/", app.deleteGenreHandler)
+					r.Post("/merge", app.mergeGenreHandler)
+				})
+			})
+		})
+
+		r.Route("/collections", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Get("/", app.listCollectionsHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/", app.createCollectionHandler)
+
+			r.Route("/{collectionID}", func(r chi.Router) {
+				r.Get("/", app.getCollectionHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+					r.Patch("/", app.updateCollectionHandler)
+					r.Delete("/", app.deleteCollectionHandler)
+					r.Put("/movies", app.setCollectionMoviesHandler)
+				})
+			})
+		})
+
+		r.Route("/series", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Get("/", app.listSeriesHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/", app.createSeriesHandler)
+
+			r.Route("/{seriesID}", func(r chi.Router) {
+				r.Get("/", app.getSeriesHandler)
+				r.Get("/seasons/{number}", app.getSeasonHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+					r.Patch("/", app.updateSeriesHandler)
+					r.Delete("/", app.deleteSeriesHandler)
+					r.Put("/seasons/{number}", app.putSeasonHandler)
+					r.Delete("/seasons/{number}", app.deleteSeasonHandler)
+				})
+			})
+		})
+
+		r.With(app.requireAuthenticatedUser).Get("/search", app.searchHandler)
+		r.With(app.requireAuthenticatedUser).Post("/events", app.postEventsHandler)
+
+		r.Route("/lists", func(r chi.Router) {
+			// public and unlisted lists can be shared with anyone, the handler checks visibility
+			r.Get("/{listID}", app.getListHandler)
+
+			r.Group(func(r chi.Router) {
+				r.Use(app.requireAuthenticatedUser)
+				r.Get("/", app.searchListsHandler)
+				r.With(app.requireActivatedUser).Post("/", app.createListHandler)
+				r.With(app.requireActivatedUser).Patch("/{listID}", app.updateListHandler)
+				r.Delete("/{listID}", app.deleteListHandler)
+				r.With(app.requireActivatedUser).Put("/{listID}/items", app.setListItemsHandler)
+				r.With(app.requireActivatedUser).Post("/{listID}/copy", app.copyListHandler)
+			})
+		})
+
+		r.Route("/reviews", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.With(app.requirePermission(data.PermissionReviewsModerate)).Get("/moderation", app.listReportedReviewsHandler)
+
+			r.Route("/{reviewID}", func(r chi.Router) {
+				r.With(app.requireActivatedUser).Put("/vote", app.voteReviewHandler)
+				r.With(app.requireActivatedUser).Delete("/vote", app.deleteReviewVoteHandler)
+				r.With(app.requireActivatedUser).Post("/report", app.reportReviewHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionReviewsModerate))
+					r.Post("/hide", app.hideReviewHandler)
+					r.Post("/restore", app.restoreReviewHandler)
+				})
 			})
 		})
 
@@ -46,6 +161,31 @@ func (app *application) routes() http.Handler {
 			r.Post("/", app.registerUserHandler)
 			r.Put("/activate", app.activateUserHandler)
 			r.Put("/password", app.updateUserPasswordHandler)
+
+			r.Route("/me", func(r chi.Router) {
+				r.Use(app.requireAuthenticatedUser)
+				r.Get("/ratings", app.listUserRatingsHandler)
+				r.Get("/watchlist", app.listWatchlistHandler)
+				r.With(app.requireActivatedUser).Post("/watchlist", app.addToWatchlistHandler)
+				r.With(app.requireActivatedUser).Delete("/watchlist/{movieID}", app.removeFromWatchlistHandler)
+				r.Get("/history", app.listHistoryHandler)
+				r.With(app.requireActivatedUser).Post("/history", app.addHistoryEventHandler)
+				r.Get("/lists", app.listUserListsHandler)
+				r.Get("/dismissals", app.listDismissalsHandler)
+				r.Get("/recommendations", app.listRecommendationsHandler)
+				r.Get("/following", app.listFollowingHandler)
+				r.Get("/followers", app.listFollowersHandler)
+				r.Get("/feed", app.feedHandler)
+				r.Get("/privacy", app.getPrivacyHandler)
+				r.With(app.requireActivatedUser).Put("/privacy", app.updatePrivacyHandler)
+			})
+
+			r.Route("/{userID}", func(r chi.Router) {
+				r.Use(app.requireAuthenticatedUser)
+				r.With(app.requireActivatedUser).Put("/follow", app.followUserHandler)
+				r.With(app.requireActivatedUser).Delete("/follow", app.unfollowUserHandler)
+				r.Get("/activity", app.userActivityHandler)
+			})
 		})
 
 		r.Route("/tokens", func(r chi.Router) {
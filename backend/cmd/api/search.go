@@ -0,0 +1,90 @@
+package main
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/validate"
+)
+
+type SearchResponse struct {
+	Results  []data.SearchResult `json:"results"`
+	Metadata data.Metadata       `json:"metadata"`
+}
+
+// Search godoc
+//
+// @Summary Search movies and series
+// @Description Searches movies and TV series by title at once, results of both types are ranked together with the best matches first
+// @Tags search
+// @Produce json
+// @Param q query string true "Title or its part, tolerates typos"
+// @Param type query []string false "Comma-separated list of content types: movie,series" collectionFormat(csv) default(movie,series)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Security BearerAuth
+// @Success 200 {object} SearchResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /search [get]
+func (app *application) searchHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Query string
+		Types []string
+	}
+
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	input.Query = strings.TrimSpace(app.readString(qs, "q", ""))
+	input.Types = app.readCSV(qs, "type", []string{data.ContentTypeMovie, data.ContentTypeSeries})
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.Query, validation.Required, validation.Length(1, 500)),
+		validation.Field(&input.Types, validation.Required, validation.Each(validation.In(data.ContentTypeMovie, data.ContentTypeSeries)),
+			validation.By(validate.Unique(input.Types))),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	results, metadata, err := app.models.Search.Search(input.Query, input.Types, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"results": results, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
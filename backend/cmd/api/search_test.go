@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestSearchHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockSearch := mocks.NewSearchInterface(t)
+
+	results := []*data.SearchResult{
+		{Type: data.ContentTypeSeries, ID: 1, Title: "Breaking Bad", Year: 2008, Score: 2},
+		{Type: data.ContentTypeMovie, ID: 7, Title: "El Camino: A Breaking Bad Movie", Year: 2019, Score: 1.2},
+	}
+
+	mockSearch.On("Search", "breaking bad", []string{data.ContentTypeMovie, data.ContentTypeSeries}, mock.Anything).
+		Return(results, data.Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1, TotalRecords: 2}, nil).Maybe()
+	mockSearch.On("Search", "breaking bad", []string{data.ContentTypeSeries}, mock.Anything).
+		Return(results[:1], data.Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1, TotalRecords: 1}, nil).Maybe()
+
+	app.models.Search = mockSearch
+
+	tests := []struct {
+		name      string
+		urlPath   string
+		wantCode  int
+		wantCount int
+	}{
+		{
+			name:      "All types",
+			urlPath:   "/v1/search?q=breaking+bad",
+			wantCode:  http.StatusOK,
+			wantCount: 2,
+		},
+		{
+			name:      "Series only",
+			urlPath:   "/v1/search?q=breaking+bad&type=series",
+			wantCode:  http.StatusOK,
+			wantCount: 1,
+		},
+		{
+			name:     "Unknown type",
+			urlPath:  "/v1/search?q=breaking+bad&type=podcast",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing query",
+			urlPath:  "/v1/search",
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusOK {
+				var resp struct {
+					Results []data.SearchResult `json:"results"`
+				}
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Len(t, resp.Results, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,581 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/validate"
+)
+
+type seriesInput struct {
+	Title     string   `json:"title" example:"Breaking Bad"`
+	StartYear int32    `json:"start_year" example:"2008"`
+	EndYear   int32    `json:"end_year" example:"2013"`
+	Genres    []string `json:"genres" example:"Drama,Crime"`
+	Overview  string   `json:"overview" example:"A chemistry teacher diagnosed with cancer turns to making meth..."`
+	// TMDB id the recommendation service knows the series by
+	TMDBID int64 `json:"tmdb_id" example:"1396"`
+}
+
+type seasonInput struct {
+	Title    string          `json:"title" example:"Season 1"`
+	AirDate  string          `json:"air_date" example:"2008-01-20"`
+	Episodes []*data.Episode `json:"episodes"`
+}
+
+type SeriesListResponse struct {
+	Series   []data.Series `json:"series"`
+	Metadata data.Metadata `json:"metadata"`
+}
+
+// validateSeries checks series fields and replaces genres with their canonical names from the genre catalog.
+// Returned validation.Errors should be sent as failed validation response, any other error is a server error.
+func (app *application) validateSeries(series *data.Series) error {
+	err := validation.ValidateStruct(series,
+		validation.Field(&series.Title, validation.Required, validation.Length(1, 500)),
+		validation.Field(&series.StartYear, validation.Required, validation.Min(1928), validation.Max(int32(time.Now().Year()))),
+		validation.Field(&series.EndYear, validation.Min(series.StartYear), validation.Max(int32(time.Now().Year()))),
+		validation.Field(&series.Genres, validation.Required, validation.Length(1, 5), validation.By(validate.Unique(series.Genres))),
+		validation.Field(&series.Overview, validation.Length(0, 5000)),
+		validation.Field(&series.TMDBID, validation.Min(int64(0)), validation.Max(int64(math.MaxInt32))),
+	)
+	if err != nil {
+		return err
+	}
+
+	series.Genres, err = app.resolveGenres(series.Genres)
+
+	return err
+}
+
+func validateSeason(season *data.Season) error {
+	numbers := make([]int32, len(season.Episodes))
+	for i, episode := range season.Episodes {
+		numbers[i] = episode.Number
+	}
+
+	return validation.ValidateStruct(season,
+		validation.Field(&season.Title, validation.Length(0, 200)),
+		validation.Field(&season.AirDate, validation.Date(time.DateOnly)),
+		validation.Field(&season.Episodes, validation.Length(0, 500), validation.By(validate.Unique(numbers)),
+			validation.Each(validation.Required, validation.By(func(value interface{}) error {
+				episode := value.(*data.Episode)
+
+				return validation.ValidateStruct(episode,
+					validation.Field(&episode.Number, validation.Required, validation.Min(int32(1))),
+					validation.Field(&episode.Title, validation.Required, validation.Length(1, 500)),
+					validation.Field(&episode.Runtime, validation.Required, validation.Min(int32(1))),
+					validation.Field(&episode.AirDate, validation.Date(time.DateOnly)),
+				)
+			}))),
+	)
+}
+
+// readSeasonParam extracts the season number from the URL, 0 is the number of specials.
+func (app *application) readSeasonParam(r *http.Request) (int32, error) {
+	number, err := strconv.ParseInt(chi.URLParam(r, "number"), 10, 32)
+	if err != nil || number < 0 {
+		return 0, errors.New("invalid season number parameter")
+	}
+
+	return int32(number), nil
+}
+
+// ListSeries godoc
+//
+// @Summary List series
+// @Description Returns a page of TV series filtered by title and genres
+// @Tags series
+// @Produce json
+// @Param title query string false "Fuzzy search by title (tolerates typos and partial words)"
+// @Param genres query []string false "Comma-separated list of genres series must have" collectionFormat(csv)
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by id, title, start_year (prefix with - for descending)" default(id)
+// @Security BearerAuth
+// @Success 200 {object} SeriesListResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series [get]
+func (app *application) listSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	title := app.readString(qs, "title", "")
+	genres := app.readCSV(qs, "genres", []string{})
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.Sort = app.readString(qs, "sort", "id")
+	filters.SortSafeList = []string{"id", "title", "start_year", "-id", "-title", "-start_year"}
+
+	err = validation.Errors{
+		"title":  validation.Validate(title, validation.Length(0, 500)),
+		"genres": validation.Validate(genres, validation.Length(0, 5)),
+	}.Filter()
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Sort, validation.Required, validation.In(filters.SortSafeList...)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	series, metadata, err := app.models.Series.GetAll(title, genres, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"series": series, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GetSeries godoc
+//
+// @Summary Get a series by ID
+// @Description Returns a TV series with its seasons, episodes of a season are returned by GET /series/{seriesID}/seasons/{number}
+// @Tags series
+// @Produce json
+// @Param seriesID path int true "Series ID"
+// @Security BearerAuth
+// @Success 200 {object} data.Series
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series/{seriesID} [get]
+func (app *application) getSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "seriesID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	series, err := app.models.Series.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"series": series}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// CreateSeries godoc
+//
+// @Summary Create a series
+// @Description Adds a TV series without seasons (admin only), genres are matched against the genre catalog (names and aliases)
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param series body seriesInput true "Series payload, end_year is omitted while the series is running"
+// @Security BearerAuth
+// @Success 201 {object} data.Series
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series [post]
+func (app *application) createSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	var input seriesInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	series := &data.Series{
+		Title:     strings.TrimSpace(input.Title),
+		StartYear: input.StartYear,
+		EndYear:   input.EndYear,
+		Genres:    input.Genres,
+		Overview:  strings.TrimSpace(input.Overview),
+		TMDBID:    input.TMDBID,
+	}
+
+	err = app.validateSeries(series)
+	if err != nil {
+		var validationErrors validation.Errors
+
+		switch {
+		case errors.As(err, &validationErrors):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.models.Series.Insert(series)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrDuplicateSeriesTMDBID):
+			app.failedValidationResponse(w, r, fmt.Errorf("tmdb_id: %w", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/series/%d", series.ID))
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"series": series}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// UpdateSeries godoc
+//
+// @Summary Update a series
+// @Description Changes the given series fields (admin only), end_year 0 marks the series as running
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param seriesID path int true "Series ID"
+// @Param series body seriesInput true "Partial series payload"
+// @Security BearerAuth
+// @Success 200 {object} data.Series
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 409 {object} map[string]string "Conflict | Example {"error": "unable to update the record due to an edit conflict, please try again"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series/{seriesID} [patch]
+func (app *application) updateSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "seriesID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	series, err := app.models.Series.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	// using pointers here to be able to compare which field was empty
+	var input struct {
+		Title     *string  `json:"title"`
+		StartYear *int32   `json:"start_year"`
+		EndYear   *int32   `json:"end_year"`
+		Genres    []string `json:"genres"`
+		Overview  *string  `json:"overview"`
+		TMDBID    *int64   `json:"tmdb_id"`
+	}
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Title != nil {
+		series.Title = strings.TrimSpace(*input.Title)
+	}
+
+	if input.StartYear != nil {
+		series.StartYear = *input.StartYear
+	}
+
+	if input.EndYear != nil {
+		series.EndYear = *input.EndYear
+	}
+
+	if input.Genres != nil {
+		series.Genres = input.Genres
+	}
+
+	if input.Overview != nil {
+		series.Overview = strings.TrimSpace(*input.Overview)
+	}
+
+	if input.TMDBID != nil {
+		series.TMDBID = *input.TMDBID
+	}
+
+	err = app.validateSeries(series)
+	if err != nil {
+		var validationErrors validation.Errors
+
+		switch {
+		case errors.As(err, &validationErrors):
+			app.failedValidationResponse(w, r, err)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.models.Series.Update(series)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		case errors.Is(err, data.ErrDuplicateSeriesTMDBID):
+			app.failedValidationResponse(w, r, fmt.Errorf("tmdb_id: %w", err))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"series": series}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteSeries godoc
+//
+// @Summary Delete a series
+// @Description Deletes a TV series with all of its seasons and episodes (admin only)
+// @Tags series
+// @Produce json
+// @Param seriesID path int true "Series ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "series successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series/{seriesID} [delete]
+func (app *application) deleteSeriesHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "seriesID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Series.Delete(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "series successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// GetSeason godoc
+//
+// @Summary Get a season
+// @Description Returns a season of the series with its episodes, season 0 holds specials
+// @Tags series
+// @Produce json
+// @Param seriesID path int true "Series ID"
+// @Param number path int true "Season number"
+// @Security BearerAuth
+// @Success 200 {object} data.Season
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series/{seriesID}/seasons/{number} [get]
+func (app *application) getSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "seriesID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	number, err := app.readSeasonParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	season, err := app.models.Series.GetSeason(id, number)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"season": season}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// PutSeason godoc
+//
+// @Summary Create or replace a season
+// @Description Creates the season or replaces it together with all of its episodes (admin only), dates are YYYY-MM-DD
+// @Tags series
+// @Accept json
+// @Produce json
+// @Param seriesID path int true "Series ID"
+// @Param number path int true "Season number"
+// @Param season body seasonInput true "Season with its episodes"
+// @Security BearerAuth
+// @Success 200 {object} data.Season
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series/{seriesID}/seasons/{number} [put]
+func (app *application) putSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "seriesID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	number, err := app.readSeasonParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input seasonInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	if input.Episodes == nil {
+		input.Episodes = []*data.Episode{}
+	}
+
+	season := &data.Season{
+		Number:   number,
+		Title:    strings.TrimSpace(input.Title),
+		AirDate:  input.AirDate,
+		Episodes: input.Episodes,
+	}
+
+	err = validateSeason(season)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	err = app.models.Series.PutSeason(id, season)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"season": season}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteSeason godoc
+//
+// @Summary Delete a season
+// @Description Deletes a season with its episodes (admin only)
+// @Tags series
+// @Produce json
+// @Param seriesID path int true "Series ID"
+// @Param number path int true "Season number"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "season successfully deleted"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /series/{seriesID}/seasons/{number} [delete]
+func (app *application) deleteSeasonHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readNamedIDParam(r, "seriesID")
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	number, err := app.readSeasonParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Series.DeleteSeason(id, number)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "season successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestGetSeriesHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockSeries := mocks.NewSeriesInterface(t)
+
+	series := data.Series{
+		ID:        1,
+		Title:     "Breaking Bad",
+		StartYear: 2008,
+		EndYear:   2013,
+		Genres:    []string{"Drama", "Crime"},
+		Seasons: []*data.Season{
+			{Number: 1, AirDate: "2008-01-20", EpisodeCount: 7, Version: 1},
+			{Number: 2, AirDate: "2009-03-08", EpisodeCount: 13, Version: 1},
+		},
+		Version: 3,
+	}
+
+	mockSeries.On("Get", int64(1)).Return(&series, nil)
+	mockSeries.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound)
+
+	app.models.Series = mockSeries
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+		wantBody *data.Series
+	}{
+		{
+			name:     "Valid ID",
+			urlPath:  "/v1/series/1",
+			wantCode: http.StatusOK,
+			wantBody: &series,
+		},
+		{
+			name:     "Non-existent ID",
+			urlPath:  "/v1/series/2",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "String ID",
+			urlPath:  "/v1/series/bb",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.get(t, tt.urlPath)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != nil {
+				var resp map[string]data.Series
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.wantBody, resp["series"], "series should be equal")
+			}
+		})
+	}
+}
+
+func TestCreateSeriesHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockSeries := mocks.NewSeriesInterface(t)
+	mockGenres := mocks.NewGenresInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+	mockGenres.On("Resolve", []string{"drama"}).Return([]string{"Drama"}, []string{}, nil).Maybe()
+	mockGenres.On("Resolve", []string{"Telenovela"}).Return([]string{}, []string{"Telenovela"}, nil).Maybe()
+	mockSeries.On("Insert", mock.MatchedBy(func(s *data.Series) bool {
+		return s.Title == "Breaking Bad" && s.Genres[0] == "Drama" && s.TMDBID != 1399
+	})).Return(nil).Maybe()
+	mockSeries.On("Insert", mock.MatchedBy(func(s *data.Series) bool {
+		return s.TMDBID == 1399
+	})).Return(data.ErrDuplicateSeriesTMDBID).Maybe()
+
+	app.models.Series = mockSeries
+	app.models.Genres = mockGenres
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Valid running series",
+			body:     `{"title": "Breaking Bad", "start_year": 2008, "genres": ["drama"]}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Valid ended series",
+			body:     `{"title": "Breaking Bad", "start_year": 2008, "end_year": 2013, "genres": ["drama"]}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Valid series with TMDB id",
+			body:     `{"title": "Breaking Bad", "start_year": 2008, "genres": ["drama"], "tmdb_id": 1396}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Duplicate TMDB id",
+			body:     `{"title": "Breaking Bad", "start_year": 2008, "genres": ["drama"], "tmdb_id": 1399}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Negative TMDB id",
+			body:     `{"title": "Breaking Bad", "start_year": 2008, "genres": ["drama"], "tmdb_id": -1}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "End before start",
+			body:     `{"title": "Breaking Bad", "start_year": 2008, "end_year": 2005, "genres": ["drama"]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Unknown genre",
+			body:     `{"title": "Breaking Bad", "start_year": 2008, "genres": ["Telenovela"]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing title",
+			body:     `{"start_year": 2008, "genres": ["drama"]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, header, _ := ts.post(t, "/v1/series", strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusCreated {
+				assert.Equal(t, "/v1/series/0", header.Get("Location"))
+			}
+		})
+	}
+}
+
+func TestPutSeasonHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockSeries := mocks.NewSeriesInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+	mockSeries.On("PutSeason", int64(1), mock.AnythingOfType("*data.Season")).Return(func(_ int64, season *data.Season) error {
+		season.EpisodeCount = len(season.Episodes)
+		season.Version = 1
+		return nil
+	}).Maybe()
+	mockSeries.On("PutSeason", int64(2), mock.AnythingOfType("*data.Season")).Return(data.ErrRecordNotFound).Maybe()
+
+	app.models.Series = mockSeries
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Valid season",
+			urlPath:  "/v1/series/1/seasons/1",
+			body:     `{"air_date": "2008-01-20", "episodes": [{"number": 1, "title": "Pilot", "runtime": 58, "air_date": "2008-01-20"}, {"number": 2, "title": "Cat's in the Bag...", "runtime": 48}]}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Specials",
+			urlPath:  "/v1/series/1/seasons/0",
+			body:     `{"title": "Minisodes", "episodes": []}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Repeated episode number",
+			urlPath:  "/v1/series/1/seasons/1",
+			body:     `{"episodes": [{"number": 1, "title": "Pilot", "runtime": 58}, {"number": 1, "title": "Pilot", "runtime": 58}]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid air date",
+			urlPath:  "/v1/series/1/seasons/1",
+			body:     `{"episodes": [{"number": 1, "title": "Pilot", "runtime": 58, "air_date": "20.01.2008"}]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing runtime",
+			urlPath:  "/v1/series/1/seasons/1",
+			body:     `{"episodes": [{"number": 1, "title": "Pilot"}]}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Negative season",
+			urlPath:  "/v1/series/1/seasons/-1",
+			body:     `{"episodes": []}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Non-existent series",
+			urlPath:  "/v1/series/2/seasons/1",
+			body:     `{"episodes": []}`,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.send(t, http.MethodPut, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusOK {
+				var resp map[string]data.Season
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, len(resp["season"].Episodes), resp["season"].EpisodeCount)
+			}
+		})
+	}
+}
+
+func TestSeriesHandlersPermissions(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{}, nil)
+
+	app.models.Permissions = mockPermissions
+
+	code, _, _ := ts.post(t, "/v1/series", strings.NewReader(`{"title": "Breaking Bad", "start_year": 2008, "genres": ["drama"]}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+
+	code, _, _ = ts.send(t, http.MethodPut, "/v1/series/1/seasons/1", nil, strings.NewReader(`{"episodes": []}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
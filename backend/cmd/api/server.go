@@ -20,6 +20,17 @@ func (app *application) server() error {
 		WriteTimeout: 30 * time.Second,
 	}
 
+	// closed on shutdown to stop background workers
+	shutdown := make(chan struct{})
+
+	app.background(func() {
+		app.purgeTrash(shutdown)
+	})
+
+	app.background(func() {
+		app.flushEvents(shutdown)
+	})
+
 	// gracefull shutdown
 	shutdownError := make(chan error)
 
@@ -37,15 +48,16 @@ func (app *application) server() error {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
+		// background workers are stopped even if requests didn't finish in time,
+		// otherwise buffered events would be lost
 		err := srv.Shutdown(ctx)
-		if err != nil {
-			shutdownError <- err
-		}
+
+		close(shutdown)
 
 		app.logger.Info("waiting for background tasks to finish", slog.String("addr", srv.Addr))
 
 		app.wg.Wait()
-		shutdownError <- nil
+		shutdownError <- err
 	}()
 
 	err := srv.ListenAndServe()
@@ -11,6 +11,9 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/httprate"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
 )
 
 func newTestApplication(t *testing.T) *application {
@@ -27,6 +30,30 @@ func newTestApplication(t *testing.T) *application {
 	}
 }
 
+// newWatchlistMock returns a watchlist mock for handlers marking movies of the test user.
+func newWatchlistMock(t *testing.T) *mocks.WatchlistInterface {
+	mockWatchlist := mocks.NewWatchlistInterface(t)
+	mockWatchlist.On("Mark", int64(1), mock.Anything).Return(nil).Maybe()
+
+	return mockWatchlist
+}
+
+// newHistoryMock returns a history mock for handlers marking movies of the test user.
+func newHistoryMock(t *testing.T) *mocks.HistoryInterface {
+	mockHistory := mocks.NewHistoryInterface(t)
+	mockHistory.On("Mark", int64(1), mock.Anything).Return(nil).Maybe()
+
+	return mockHistory
+}
+
+// newActivitiesMock returns an activities mock for handlers recording activities of the test user.
+func newActivitiesMock(t *testing.T) *mocks.ActivitiesInterface {
+	mockActivities := mocks.NewActivitiesInterface(t)
+	mockActivities.On("Insert", mock.MatchedBy(func(a *data.Activity) bool { return a.UserID == 1 })).Return(nil).Maybe()
+
+	return mockActivities
+}
+
 type testServer struct {
 	*httptest.Server
 }
@@ -143,6 +170,48 @@ func (ts *testServer) delete(t *testing.T, urlPath string) (int, http.Header, []
 	return rs.StatusCode, rs.Header, body
 }
 
+// send makes an authenticated request with the given method and additional headers,
+// an Authorization header among them replaces the token of the activated test user.
+func (ts *testServer) send(t *testing.T, method, urlPath string, header http.Header, requestBody io.Reader) (int, http.Header, []byte) {
+	req, err := http.NewRequest(method, ts.URL+urlPath, requestBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := testAuth(1, true, newTestApplication(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	for key, value := range header {
+		req.Header[key] = value
+	}
+
+	rs, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		e := rs.Body.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else if e != nil {
+			t.Fatal(e)
+		}
+	}()
+
+	body, err := io.ReadAll(rs.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return rs.StatusCode, rs.Header, body
+}
+
 func testAuth(userID int64, activation bool, app *application) (string, error) {
 	token, err := createToken(userID, activation, app)
 	if err != nil {
@@ -173,12 +242,126 @@ func testRoutes(app *application) http.Handler {
 			r.Use(app.requireAuthenticatedUser)
 			r.Get("/", app.listMoviesHandler)
 			r.With(app.requireActivatedUser).Post("/", app.postMovieHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/batch", app.batchMoviesHandler)
 			r.Post("/predict", app.predictHandler)
+			r.Get("/suggest", app.suggestMoviesHandler)
+			r.Get("/lookup", app.lookupMovieHandler)
 
 			r.Route("/{movieID}", func(r chi.Router) {
 				r.Get("/", app.getMovieHandler)
 				r.With(app.requireActivatedUser).Patch("/", app.updateMovieHandler)
-				r.Delete("/", app.deleteMovieHandler)
+				r.With(app.requirePermission(data.PermissionMoviesAdmin)).Delete("/", app.deleteMovieHandler)
+				r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/restore", app.restoreMovieHandler)
+				r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/merge", app.mergeMovieHandler)
+				r.Get("/history", app.movieHistoryHandler)
+				r.With(app.requireActivatedUser).Post("/revert/{version}", app.revertMovieHandler)
+				r.Get("/translations", app.listTranslationsHandler)
+				r.With(app.requireActivatedUser).Put("/translations/{lang}", app.putTranslationHandler)
+				r.With(app.requireActivatedUser).Delete("/translations/{lang}", app.deleteTranslationHandler)
+				r.With(app.requireActivatedUser).Put("/external-ids", app.putExternalIDsHandler)
+				r.With(app.requireActivatedUser).Put("/rating", app.putRatingHandler)
+				r.With(app.requireActivatedUser).Delete("/rating", app.deleteRatingHandler)
+				r.Get("/reviews", app.listMovieReviewsHandler)
+				r.With(app.requireActivatedUser).Put("/review", app.putReviewHandler)
+				r.With(app.requireActivatedUser).Delete("/review", app.deleteReviewHandler)
+				r.With(app.requireActivatedUser).Put("/dismiss", app.dismissMovieHandler)
+				r.With(app.requireActivatedUser).Delete("/dismiss", app.undismissMovieHandler)
+			})
+		})
+
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+			r.Get("/movies/trash", app.listTrashHandler)
+		})
+
+		r.Route("/genres", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Get("/", app.listGenresHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/", app.createGenreHandler)
+
+			r.Route("/{genreID}", func(r chi.Router) {
+				r.Get("/", app.getGenreHandler)
+				r.With(app.requireActivatedUser).Put("/dismiss", app.dismissGenreHandler)
+				r.With(app.requireActivatedUser).Delete("/dismiss", app.undismissGenreHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+					r.Patch("/", app.updateGenreHandler)
+					r.Delete("/", app.deleteGenreHandler)
+					r.Post("/merge", app.mergeGenreHandler)
+				})
+			})
+		})
+
+		r.Route("/collections", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Get("/", app.listCollectionsHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/", app.createCollectionHandler)
+
+			r.Route("/{collectionID}", func(r chi.Router) {
+				r.Get("/", app.getCollectionHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+					r.Patch("/", app.updateCollectionHandler)
+					r.Delete("/", app.deleteCollectionHandler)
+					r.Put("/movies", app.setCollectionMoviesHandler)
+				})
+			})
+		})
+
+		r.Route("/series", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.Get("/", app.listSeriesHandler)
+			r.With(app.requirePermission(data.PermissionMoviesAdmin)).Post("/", app.createSeriesHandler)
+
+			r.Route("/{seriesID}", func(r chi.Router) {
+				r.Get("/", app.getSeriesHandler)
+				r.Get("/seasons/{number}", app.getSeasonHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionMoviesAdmin))
+					r.Patch("/", app.updateSeriesHandler)
+					r.Delete("/", app.deleteSeriesHandler)
+					r.Put("/seasons/{number}", app.putSeasonHandler)
+					r.Delete("/seasons/{number}", app.deleteSeasonHandler)
+				})
+			})
+		})
+
+		r.With(app.requireAuthenticatedUser).Get("/search", app.searchHandler)
+		r.With(app.requireAuthenticatedUser).Post("/events", app.postEventsHandler)
+
+		r.Route("/lists", func(r chi.Router) {
+			// public and unlisted lists can be shared with anyone, the handler checks visibility
+			r.Get("/{listID}", app.getListHandler)
+
+			r.Group(func(r chi.Router) {
+				r.Use(app.requireAuthenticatedUser)
+				r.Get("/", app.searchListsHandler)
+				r.With(app.requireActivatedUser).Post("/", app.createListHandler)
+				r.With(app.requireActivatedUser).Patch("/{listID}", app.updateListHandler)
+				r.Delete("/{listID}", app.deleteListHandler)
+				r.With(app.requireActivatedUser).Put("/{listID}/items", app.setListItemsHandler)
+				r.With(app.requireActivatedUser).Post("/{listID}/copy", app.copyListHandler)
+			})
+		})
+
+		r.Route("/reviews", func(r chi.Router) {
+			r.Use(app.requireAuthenticatedUser)
+			r.With(app.requirePermission(data.PermissionReviewsModerate)).Get("/moderation", app.listReportedReviewsHandler)
+
+			r.Route("/{reviewID}", func(r chi.Router) {
+				r.With(app.requireActivatedUser).Put("/vote", app.voteReviewHandler)
+				r.With(app.requireActivatedUser).Delete("/vote", app.deleteReviewVoteHandler)
+				r.With(app.requireActivatedUser).Post("/report", app.reportReviewHandler)
+
+				r.Group(func(r chi.Router) {
+					r.Use(app.requirePermission(data.PermissionReviewsModerate))
+					r.Post("/hide", app.hideReviewHandler)
+					r.Post("/restore", app.restoreReviewHandler)
+				})
 			})
 		})
 
@@ -186,6 +369,31 @@ func testRoutes(app *application) http.Handler {
 			r.Post("/", app.registerUserHandler)
 			r.Put("/activate", app.activateUserHandler)
 			r.Put("/password", app.updateUserPasswordHandler)
+
+			r.Route("/me", func(r chi.Router) {
+				r.Use(app.requireAuthenticatedUser)
+				r.Get("/ratings", app.listUserRatingsHandler)
+				r.Get("/watchlist", app.listWatchlistHandler)
+				r.With(app.requireActivatedUser).Post("/watchlist", app.addToWatchlistHandler)
+				r.With(app.requireActivatedUser).Delete("/watchlist/{movieID}", app.removeFromWatchlistHandler)
+				r.Get("/history", app.listHistoryHandler)
+				r.With(app.requireActivatedUser).Post("/history", app.addHistoryEventHandler)
+				r.Get("/lists", app.listUserListsHandler)
+				r.Get("/dismissals", app.listDismissalsHandler)
+				r.Get("/recommendations", app.listRecommendationsHandler)
+				r.Get("/following", app.listFollowingHandler)
+				r.Get("/followers", app.listFollowersHandler)
+				r.Get("/feed", app.feedHandler)
+				r.Get("/privacy", app.getPrivacyHandler)
+				r.With(app.requireActivatedUser).Put("/privacy", app.updatePrivacyHandler)
+			})
+
+			r.Route("/{userID}", func(r chi.Router) {
+				r.Use(app.requireAuthenticatedUser)
+				r.With(app.requireActivatedUser).Put("/follow", app.followUserHandler)
+				r.With(app.requireActivatedUser).Delete("/follow", app.unfollowUserHandler)
+				r.Get("/activity", app.userActivityHandler)
+			})
 		})
 
 		r.Route("/tokens", func(r chi.Router) {
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type translationInput struct {
+	Title    string `json:"title" example:"Побег из Шоушенка"`
+	Overview string `json:"overview" example:"Бухгалтер Энди Дюфрейн обвинён в убийстве..."`
+}
+
+// readLanguageParam extracts and validates the language code parameter from the URL.
+func (app *application) readLanguageParam(r *http.Request) (string, error) {
+	language := strings.ToLower(chi.URLParam(r, "lang"))
+	if !languageRX.MatchString(language) {
+		return "", ErrKeyNotLanguage
+	}
+
+	return language, nil
+}
+
+// ListTranslations godoc
+//
+// @Summary List movie translations
+// @Description Returns titles and overviews of the movie in all languages it is translated to
+// @Tags movies
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Security BearerAuth
+// @Success 200 {array} data.Translation
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/translations [get]
+func (app *application) listTranslationsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	_, err = app.models.Movies.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	translations, err := app.models.Translations.GetAll(id)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"translations": translations}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// PutTranslation godoc
+//
+// @Summary Add or replace a movie translation
+// @Description Sets movie title and overview in the language, translated titles are searched with the language text search configuration
+// @Tags movies
+// @Accept json
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param lang path string true "ISO 639 language code"
+// @Param translation body translationInput true "Translation payload"
+// @Security BearerAuth
+// @Success 200 {object} data.Translation
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/translations/{lang} [put]
+func (app *application) putTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	language, err := app.readLanguageParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	var input translationInput
+
+	err = app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	translation := &data.Translation{
+		MovieID:  id,
+		Language: language,
+		Title:    strings.TrimSpace(input.Title),
+		Overview: strings.TrimSpace(input.Overview),
+	}
+
+	err = validation.ValidateStruct(translation,
+		validation.Field(&translation.Title, validation.Required, validation.Length(1, 500)),
+		validation.Field(&translation.Overview, validation.Length(0, 5000)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	// movies in trash can't be translated
+	_, err = app.models.Movies.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.models.Translations.Upsert(translation)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"translation": translation}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// DeleteTranslation godoc
+//
+// @Summary Delete a movie translation
+// @Description Removes movie title and overview in the language
+// @Tags movies
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Param lang path string true "ISO 639 language code"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "translation successfully deleted"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/translations/{lang} [delete]
+func (app *application) deleteTranslationHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	language, err := app.readLanguageParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Translations.Delete(id, language)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "translation successfully deleted"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestReadLanguages(t *testing.T) {
+	app := newTestApplication(t)
+
+	tests := []struct {
+		name           string
+		query          string
+		acceptLanguage string
+		want           []string
+		wantErr        error
+	}{
+		{name: "Nothing requested", want: []string{}},
+		{name: "Query parameter", query: "lang=RU", acceptLanguage: "de", want: []string{"ru"}},
+		{name: "Invalid query parameter", query: "lang=russian", wantErr: ErrKeyNotLanguage},
+		{name: "Header with weights", acceptLanguage: "de;q=0.5, ru-RU, en;q=0.8, ru;q=0.9", want: []string{"ru", "en", "de"}},
+		{name: "Header with wildcard and zero weight", acceptLanguage: "*, fr;q=0, es", want: []string{"es"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qs, _ := url.ParseQuery(tt.query)
+
+			got, err := app.readLanguages(qs, "lang", tt.acceptLanguage)
+
+			assert.ErrorIs(t, err, tt.wantErr)
+			if tt.wantErr == nil {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetMovieLocalized(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockTranslations := mocks.NewTranslationsInterface(t)
+
+	mockMovies.On("Get", int64(1)).Return(func(int64) (*data.Movie, error) {
+		return &data.Movie{ID: 1, Title: "The Shawshank Redemption", Year: 1994, Runtime: 142, Genres: []string{"Drama"}, Version: 1}, nil
+	})
+	mockTranslations.On("Localize", mock.Anything, []string{"ru", "en"}).Return(func(movies []*data.Movie, languages []string) error {
+		movies[0].OriginalTitle = movies[0].Title
+		movies[0].Title = "Побег из Шоушенка"
+		movies[0].Language = "ru"
+
+		return nil
+	})
+
+	app.models.Movies = mockMovies
+	app.models.Watchlist = newWatchlistMock(t)
+	app.models.History = newHistoryMock(t)
+	app.models.Translations = mockTranslations
+
+	code, header, body := ts.send(t, http.MethodGet, "/v1/movie/1", http.Header{"Accept-Language": {"ru-RU, en;q=0.5"}}, nil)
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+	assert.Contains(t, header.Values("Vary"), "Accept-Language", "response should vary by language")
+
+	var resp map[string]data.Movie
+
+	err := json.Unmarshal(body, &resp)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "Побег из Шоушенка", resp["movie"].Title, "title should be translated")
+	assert.Equal(t, "The Shawshank Redemption", resp["movie"].OriginalTitle, "original title should be kept")
+
+	code, _, _ = ts.get(t, "/v1/movie/1?lang=x1")
+	assert.Equal(t, http.StatusUnprocessableEntity, code, "status code should be 422")
+}
+
+func TestPutTranslationHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockTranslations := mocks.NewTranslationsInterface(t)
+
+	mockMovies.On("Get", int64(1)).Return(&data.Movie{ID: 1, Title: "Test Movie", Version: 1}, nil).Maybe()
+	mockMovies.On("Get", int64(2)).Return(nil, data.ErrRecordNotFound).Maybe()
+	mockTranslations.On("Upsert", mock.MatchedBy(func(tr *data.Translation) bool {
+		return tr.MovieID == 1 && tr.Language == "ru" && tr.Title == "Тестовый фильм"
+	})).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(0).(*data.Translation).Version = 1
+	}).Maybe()
+
+	app.models.Movies = mockMovies
+	app.models.Translations = mockTranslations
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "Valid translation",
+			urlPath:  "/v1/movie/1/translations/ru",
+			body:     `{"title": "Тестовый фильм", "overview": "Описание"}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Empty title",
+			urlPath:  "/v1/movie/1/translations/ru",
+			body:     `{"title": " "}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Invalid language",
+			urlPath:  "/v1/movie/1/translations/russian",
+			body:     `{"title": "Тестовый фильм"}`,
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "Non-existent movie",
+			urlPath:  "/v1/movie/2/translations/ru",
+			body:     `{"title": "Тестовый фильм"}`,
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := ts.send(t, http.MethodPut, tt.urlPath, nil, strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+		})
+	}
+}
+
+func TestDeleteTranslationHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockTranslations := mocks.NewTranslationsInterface(t)
+
+	mockTranslations.On("Delete", int64(1), "ru").Return(nil)
+	mockTranslations.On("Delete", int64(1), "de").Return(data.ErrRecordNotFound)
+
+	app.models.Translations = mockTranslations
+
+	code, _, _ := ts.delete(t, "/v1/movie/1/translations/ru")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	code, _, _ = ts.delete(t, "/v1/movie/1/translations/de")
+	assert.Equal(t, http.StatusNotFound, code, "status code should be 404")
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// ListTrash godoc
+//
+// @Summary List deleted movies
+// @Description Retrieve movies in trash, they are purged permanently after the retention period (admin only)
+// @Tags admin
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Number of items per page" default(20)
+// @Param sort query string false "Sort by: one of deleted_at,id,title,-deleted_at,-id,-title" default(-deleted_at)
+// @Security BearerAuth
+// @Success 200 {object} MoviesListResponse
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /admin/movies/trash [get]
+func (app *application) listTrashHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.Sort = app.readString(qs, "sort", "-deleted_at")
+	filters.SortSafeList = []string{"deleted_at", "id", "title", "-deleted_at", "-id", "-title"}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Sort, validation.Required, validation.In(filters.SortSafeList...)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	movies, metadata, err := app.models.Movies.GetDeleted(filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"movies": movies, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// RestoreMovie godoc
+//
+// @Summary Restore a deleted movie
+// @Description Brings the movie back from trash (admin only)
+// @Tags admin
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Security BearerAuth
+// @Success 200 {object} data.Movie
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your user account doesn't have the necessary permissions to access this resource"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /movie/{movieID}/restore [post]
+func (app *application) restoreMovieHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	movie, err := app.models.Movies.Restore(id, app.contextGetUser(r).ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"movie": movie}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// purgeTrash permanently deletes movies that stayed in trash longer than the retention period.
+// It runs every purge interval until shutdown is closed.
+func (app *application) purgeTrash(shutdown <-chan struct{}) {
+	ticker := time.NewTicker(app.config.trash.purgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-ticker.C:
+			purged, err := app.models.Movies.PurgeDeleted(time.Now().Add(-app.config.trash.retention))
+			if err != nil {
+				app.logger.Error("cannot purge trash: " + err.Error())
+				continue
+			}
+
+			if purged > 0 {
+				app.logger.Info("movies purged from trash", slog.Int64("count", purged))
+			}
+		}
+	}
+}
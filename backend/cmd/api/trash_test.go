@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestRestoreMovieHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	movie := data.Movie{
+		ID:      1,
+		Title:   "Test Movie",
+		Year:    2024,
+		Runtime: 125,
+		Genres:  []string{"Drama"},
+		Version: 3,
+	}
+
+	mockMovies.On("Restore", int64(1), int64(1)).Return(&movie, nil)
+	mockMovies.On("Restore", int64(2), int64(1)).Return(nil, data.ErrRecordNotFound)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Permissions = mockPermissions
+
+	tests := []struct {
+		name     string
+		urlPath  string
+		wantCode int
+		wantBody *data.Movie
+	}{
+		{
+			name:     "Movie in trash",
+			urlPath:  "/v1/movie/1/restore",
+			wantCode: http.StatusOK,
+			wantBody: &movie,
+		},
+		{
+			name:     "Movie not in trash",
+			urlPath:  "/v1/movie/2/restore",
+			wantCode: http.StatusNotFound,
+		},
+		{
+			name:     "String ID",
+			urlPath:  "/v1/movie/smth/restore",
+			wantCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.post(t, tt.urlPath, nil)
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantBody != nil {
+				var resp map[string]data.Movie
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, *tt.wantBody, resp["movie"], "movie should be equal")
+			}
+		})
+	}
+}
+
+func TestListTrashHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockPermissions := mocks.NewPermissionsInterface(t)
+
+	deletedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	movies := []*data.Movie{
+		{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 2, DeletedAt: &deletedAt},
+	}
+
+	mockMovies.On("GetDeleted", mock.MatchedBy(func(f data.Filters) bool {
+		return f.Sort == "-deleted_at"
+	})).Return(movies, data.Metadata{CurrentPage: 1, PageSize: 20, FirstPage: 1, LastPage: 1, TotalRecords: 1}, nil)
+	mockPermissions.On("GetAllForUser", int64(1)).Return(data.Permissions{data.PermissionMoviesAdmin}, nil)
+
+	app.models.Movies = mockMovies
+	app.models.Permissions = mockPermissions
+
+	code, _, body := ts.get(t, "/v1/admin/movies/trash")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	var resp MoviesListResponse
+
+	err := json.Unmarshal(body, &resp)
+	assert.NoError(t, err)
+
+	assert.Len(t, resp.Movies, 1, "one movie should be returned")
+	assert.Equal(t, deletedAt, *resp.Movies[0].DeletedAt, "deleted_at should be returned")
+
+	code, _, _ = ts.get(t, "/v1/admin/movies/trash?sort=runtime")
+	assert.Equal(t, http.StatusUnprocessableEntity, code, "status code should be 422")
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/invopop/validation"
+
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+type watchlistInput struct {
+	MovieID int64  `json:"movie_id" example:"1"`
+	Note    string `json:"note" example:"recommended by Anna"`
+}
+
+type WatchlistResponse struct {
+	Watchlist []data.WatchlistItem `json:"watchlist"`
+	Metadata  data.Metadata        `json:"metadata"`
+}
+
+// ListWatchlist godoc
+//
+// @Summary List my watchlist
+// @Description Returns a page of movies the authenticated user saved for later
+// @Tags watchlist
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param page_size query int false "Page size" default(20)
+// @Param sort query string false "Sort by added_at, title, year (prefix with - for descending)" default(-added_at)
+// @Security BearerAuth
+// @Success 200 {object} WatchlistResponse
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "validation error"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/watchlist [get]
+func (app *application) listWatchlistHandler(w http.ResponseWriter, r *http.Request) {
+	var filters data.Filters
+
+	qs := r.URL.Query()
+
+	var err error
+
+	filters.Page, err = app.readInt(qs, "page", 1)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.PageSize, err = app.readInt(qs, "page_size", 20)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	filters.Sort = app.readString(qs, "sort", "-added_at")
+	filters.SortSafeList = []string{"added_at", "title", "year", "-added_at", "-title", "-year"}
+
+	err = validation.ValidateStruct(&filters,
+		validation.Field(&filters.Page, validation.Required, validation.Min(1), validation.Max(10_000_000)),
+		validation.Field(&filters.PageSize, validation.Required, validation.Min(1), validation.Max(100)),
+		validation.Field(&filters.Sort, validation.Required, validation.In(filters.SortSafeList...)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	items, metadata, err := app.models.Watchlist.GetAll(app.contextGetUser(r).ID, filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"watchlist": items, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// AddToWatchlist godoc
+//
+// @Summary Add a movie to my watchlist
+// @Description Saves the movie for later, adding a movie already on the watchlist replaces its note
+// @Tags watchlist
+// @Accept json
+// @Produce json
+// @Param item body watchlistInput true "Movie and an optional note"
+// @Security BearerAuth
+// @Success 201 {object} data.WatchlistItem
+// @Success 200 {object} data.WatchlistItem "The movie was already on the watchlist, its note is updated"
+// @Failure 400 {object} map[string]string "Bad Request | Example {"error": "body contains badly-formated JSON"}"
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 422 {object} map[string]string "Unprocessable Entity | Example {"error": "movie_id: movie doesn't exist"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/watchlist [post]
+func (app *application) addToWatchlistHandler(w http.ResponseWriter, r *http.Request) {
+	var input watchlistInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	input.Note = strings.TrimSpace(input.Note)
+
+	err = validation.ValidateStruct(&input,
+		validation.Field(&input.MovieID, validation.Required, validation.Min(int64(1))),
+		validation.Field(&input.Note, validation.Length(0, 500)),
+	)
+	if err != nil {
+		app.failedValidationResponse(w, r, err)
+		return
+	}
+
+	item := &data.WatchlistItem{
+		MovieID: input.MovieID,
+		Note:    input.Note,
+	}
+
+	added, err := app.models.Watchlist.Add(app.contextGetUser(r).ID, item)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.failedValidationResponse(w, r, fmt.Errorf("movie_id: %w", data.ErrUnknownMovie))
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	status := http.StatusOK
+	if added {
+		status = http.StatusCreated
+	}
+
+	err = app.writeJSON(w, status, envelope{"item": item}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// RemoveFromWatchlist godoc
+//
+// @Summary Remove a movie from my watchlist
+// @Tags watchlist
+// @Produce json
+// @Param movieID path int true "Movie ID"
+// @Security BearerAuth
+// @Success 200 {object} map[string]string "OK | Example {"message": "movie successfully removed from watchlist"}"
+// @Failure 401 {object} map[string]string "Unauthorized | Example {"error": "this resourse avaliable only for authenticated users"}"
+// @Failure 403 {object} map[string]string "Forbidden | Example {"error": "your account must be activated to access this resourse"}"
+// @Failure 404 {object} map[string]string "Not Found | Example {"error": "requested resource could not be found"}"
+// @Failure 500 {object} map[string]string "Internal Server Error | Example {"error": "server encountered a problem and could not process your request"}"
+// @Router /users/me/watchlist/{movieID} [delete]
+func (app *application) removeFromWatchlistHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	err = app.models.Watchlist.Remove(app.contextGetUser(r).ID, id)
+	if err != nil {
+		switch {
+		case errors.Is(err, data.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "movie successfully removed from watchlist"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
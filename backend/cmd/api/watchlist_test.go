@@ -0,0 +1,173 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data"
+	"github.com/vladgrskkh/movie_recomendation_system/internal/data/mocks"
+)
+
+func TestAddToWatchlistHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockWatchlist := mocks.NewWatchlistInterface(t)
+
+	mockWatchlist.On("Add", int64(1), mock.MatchedBy(func(i *data.WatchlistItem) bool { return i.MovieID == 1 })).
+		Return(true, nil).Run(func(args mock.Arguments) {
+		item := args.Get(1).(*data.WatchlistItem)
+		item.Title = "Test Movie"
+		item.AddedAt = time.Now()
+	}).Maybe()
+	mockWatchlist.On("Add", int64(1), mock.MatchedBy(func(i *data.WatchlistItem) bool { return i.MovieID == 2 })).
+		Return(false, nil).Maybe()
+	mockWatchlist.On("Add", int64(1), mock.MatchedBy(func(i *data.WatchlistItem) bool { return i.MovieID == 3 })).
+		Return(false, data.ErrRecordNotFound).Maybe()
+
+	app.models.Watchlist = mockWatchlist
+
+	tests := []struct {
+		name     string
+		body     string
+		wantCode int
+	}{
+		{
+			name:     "New movie",
+			body:     `{"movie_id": 1, "note": " recommended by Anna "}`,
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Movie already on watchlist",
+			body:     `{"movie_id": 2}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Non-existent movie",
+			body:     `{"movie_id": 3}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Missing movie",
+			body:     `{"note": "later"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+		{
+			name:     "Note too long",
+			body:     `{"movie_id": 1, "note": "` + strings.Repeat("a", 501) + `"}`,
+			wantCode: http.StatusUnprocessableEntity,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, body := ts.post(t, "/v1/users/me/watchlist", strings.NewReader(tt.body))
+
+			assert.Equal(t, tt.wantCode, code, fmt.Sprintf("status code should be %d", tt.wantCode))
+			if tt.wantCode == http.StatusCreated {
+				var resp map[string]data.WatchlistItem
+
+				err := json.Unmarshal(body, &resp)
+				assert.NoError(t, err)
+
+				assert.Equal(t, "recommended by Anna", resp["item"].Note)
+				assert.Equal(t, "Test Movie", resp["item"].Title)
+			}
+		})
+	}
+}
+
+func TestRemoveFromWatchlistHandler(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockWatchlist := mocks.NewWatchlistInterface(t)
+
+	mockWatchlist.On("Remove", int64(1), int64(1)).Return(nil)
+	mockWatchlist.On("Remove", int64(1), int64(2)).Return(data.ErrRecordNotFound)
+
+	app.models.Watchlist = mockWatchlist
+
+	code, _, _ := ts.delete(t, "/v1/users/me/watchlist/1")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	code, _, _ = ts.delete(t, "/v1/users/me/watchlist/2")
+	assert.Equal(t, http.StatusNotFound, code, "status code should be 404")
+}
+
+func TestWatchlistRequiresActivatedUser(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	// the watchlist mock has no expectations, inactive users never reach it
+	app.models.Watchlist = mocks.NewWatchlistInterface(t)
+
+	token, err := testAuth(1, false, app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header := http.Header{"Authorization": {"Bearer " + token}}
+
+	code, _, _ := ts.send(t, http.MethodPost, "/v1/users/me/watchlist", header, strings.NewReader(`{"movie_id": 1}`))
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+
+	code, _, _ = ts.send(t, http.MethodDelete, "/v1/users/me/watchlist/1", header, nil)
+	assert.Equal(t, http.StatusForbidden, code, "status code should be 403")
+}
+
+func TestListMoviesInWatchlist(t *testing.T) {
+	app := newTestApplication(t)
+
+	ts := newTestServer(t, testRoutes(app))
+	defer ts.Close()
+
+	mockMovies := mocks.NewMoviesInterface(t)
+	mockWatchlist := mocks.NewWatchlistInterface(t)
+
+	movies := []*data.Movie{
+		{ID: 1, Title: "Test Movie", Year: 2024, Runtime: 125, Genres: []string{"Drama"}, Version: 1},
+		{ID: 2, Title: "Other Movie", Year: 2023, Runtime: 98, Genres: []string{"Drama"}, Version: 1},
+	}
+
+	mockMovies.On("GetAll", mock.AnythingOfType("data.MovieFilter"), mock.AnythingOfType("data.Filters")).
+		Return(movies, data.Metadata{}, nil)
+
+	// the whole page is marked with a single call
+	mockWatchlist.On("Mark", int64(1), movies).Return(nil).Run(func(args mock.Arguments) {
+		for _, movie := range args.Get(1).([]*data.Movie) {
+			inWatchlist := movie.ID == 1
+			movie.InWatchlist = &inWatchlist
+		}
+	}).Once()
+
+	app.models.Movies = mockMovies
+	app.models.Watchlist = mockWatchlist
+	app.models.History = newHistoryMock(t)
+
+	code, _, body := ts.get(t, "/v1/movie")
+	assert.Equal(t, http.StatusOK, code, "status code should be 200")
+
+	var resp struct {
+		Movies []map[string]any `json:"movies"`
+	}
+
+	err := json.Unmarshal(body, &resp)
+	assert.NoError(t, err)
+
+	assert.Len(t, resp.Movies, 2)
+	assert.Equal(t, true, resp.Movies[0]["in_watchlist"])
+	assert.Equal(t, false, resp.Movies[1]["in_watchlist"])
+}
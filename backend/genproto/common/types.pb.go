@@ -21,10 +21,60 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type ContentType int32
+
+const (
+	ContentType_CONTENT_TYPE_UNSPECIFIED ContentType = 0
+	ContentType_CONTENT_TYPE_MOVIE       ContentType = 1
+	ContentType_CONTENT_TYPE_SERIES      ContentType = 2
+)
+
+// Enum value maps for ContentType.
+var (
+	ContentType_name = map[int32]string{
+		0: "CONTENT_TYPE_UNSPECIFIED",
+		1: "CONTENT_TYPE_MOVIE",
+		2: "CONTENT_TYPE_SERIES",
+	}
+	ContentType_value = map[string]int32{
+		"CONTENT_TYPE_UNSPECIFIED": 0,
+		"CONTENT_TYPE_MOVIE":       1,
+		"CONTENT_TYPE_SERIES":      2,
+	}
+)
+
+func (x ContentType) Enum() *ContentType {
+	p := new(ContentType)
+	*p = x
+	return p
+}
+
+func (x ContentType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ContentType) Descriptor() protoreflect.EnumDescriptor {
+	return file_common_types_proto_enumTypes[0].Descriptor()
+}
+
+func (ContentType) Type() protoreflect.EnumType {
+	return &file_common_types_proto_enumTypes[0]
+}
+
+func (x ContentType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ContentType.Descriptor instead.
+func (ContentType) EnumDescriptor() ([]byte, []int) {
+	return file_common_types_proto_rawDescGZIP(), []int{0}
+}
+
 type Recommendation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
 	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	MovieId       int64                  `protobuf:"varint,3,opt,name=movieId,proto3" json:"movieId,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,14 +123,26 @@ func (x *Recommendation) GetScore() float64 {
 	return 0
 }
 
+func (x *Recommendation) GetMovieId() int64 {
+	if x != nil {
+		return x.MovieId
+	}
+	return 0
+}
+
 var File_common_types_proto protoreflect.FileDescriptor
 
 const file_common_types_proto_rawDesc = "" +
 	"\n" +
-	"\x12common/types.proto\x12\x06common\"<\n" +
+	"\x12common/types.proto\x12\x06common\"V\n" +
 	"\x0eRecommendation\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x14\n" +
-	"\x05score\x18\x02 \x01(\x01R\x05scoreBIZGgithub.com/vladgrskkh/movie_recomendation_system/genproto/common;commonb\x06proto3"
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12\x18\n" +
+	"\amovieId\x18\x03 \x01(\x03R\amovieId*\\\n" +
+	"\vContentType\x12\x1c\n" +
+	"\x18CONTENT_TYPE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12CONTENT_TYPE_MOVIE\x10\x01\x12\x17\n" +
+	"\x13CONTENT_TYPE_SERIES\x10\x02BIZGgithub.com/vladgrskkh/movie_recomendation_system/genproto/common;commonb\x06proto3"
 
 var (
 	file_common_types_proto_rawDescOnce sync.Once
@@ -94,9 +156,11 @@ func file_common_types_proto_rawDescGZIP() []byte {
 	return file_common_types_proto_rawDescData
 }
 
+var file_common_types_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_common_types_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
 var file_common_types_proto_goTypes = []any{
-	(*Recommendation)(nil), // 0: common.Recommendation
+	(ContentType)(0),       // 0: common.ContentType
+	(*Recommendation)(nil), // 1: common.Recommendation
 }
 var file_common_types_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -116,13 +180,14 @@ func file_common_types_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_common_types_proto_rawDesc), len(file_common_types_proto_rawDesc)),
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   1,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_common_types_proto_goTypes,
 		DependencyIndexes: file_common_types_proto_depIdxs,
+		EnumInfos:         file_common_types_proto_enumTypes,
 		MessageInfos:      file_common_types_proto_msgTypes,
 	}.Build()
 	File_common_types_proto = out.File
@@ -25,6 +25,10 @@ const (
 type RecommendRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MovieTitle    string                 `protobuf:"bytes,1,opt,name=movieTitle,proto3" json:"movieTitle,omitempty"`
+	ContentType   common.ContentType     `protobuf:"varint,2,opt,name=contentType,proto3,enum=common.ContentType" json:"contentType,omitempty"`
+	MovieId       int64                  `protobuf:"varint,3,opt,name=movieId,proto3" json:"movieId,omitempty"`
+	TopK          int32                  `protobuf:"varint,4,opt,name=topK,proto3" json:"topK,omitempty"`
+	Filter        *RecommendFilter       `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -66,6 +70,102 @@ func (x *RecommendRequest) GetMovieTitle() string {
 	return ""
 }
 
+func (x *RecommendRequest) GetContentType() common.ContentType {
+	if x != nil {
+		return x.ContentType
+	}
+	return common.ContentType(0)
+}
+
+func (x *RecommendRequest) GetMovieId() int64 {
+	if x != nil {
+		return x.MovieId
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *RecommendRequest) GetFilter() *RecommendFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+type RecommendFilter struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Genres          []string               `protobuf:"bytes,1,rep,name=genres,proto3" json:"genres,omitempty"`
+	YearFrom        int32                  `protobuf:"varint,2,opt,name=yearFrom,proto3" json:"yearFrom,omitempty"`
+	YearTo          int32                  `protobuf:"varint,3,opt,name=yearTo,proto3" json:"yearTo,omitempty"`
+	ExcludeMovieIds []int64                `protobuf:"varint,4,rep,packed,name=excludeMovieIds,proto3" json:"excludeMovieIds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *RecommendFilter) Reset() {
+	*x = RecommendFilter{}
+	mi := &file_v1_predict_predict_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecommendFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecommendFilter) ProtoMessage() {}
+
+func (x *RecommendFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_predict_predict_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecommendFilter.ProtoReflect.Descriptor instead.
+func (*RecommendFilter) Descriptor() ([]byte, []int) {
+	return file_v1_predict_predict_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RecommendFilter) GetGenres() []string {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+func (x *RecommendFilter) GetYearFrom() int32 {
+	if x != nil {
+		return x.YearFrom
+	}
+	return 0
+}
+
+func (x *RecommendFilter) GetYearTo() int32 {
+	if x != nil {
+		return x.YearTo
+	}
+	return 0
+}
+
+func (x *RecommendFilter) GetExcludeMovieIds() []int64 {
+	if x != nil {
+		return x.ExcludeMovieIds
+	}
+	return nil
+}
+
 type RecommendResponse struct {
 	state           protoimpl.MessageState   `protogen:"open.v1"`
 	Recommendations []*common.Recommendation `protobuf:"bytes,1,rep,name=recommendations,proto3" json:"recommendations,omitempty"`
@@ -75,7 +175,7 @@ type RecommendResponse struct {
 
 func (x *RecommendResponse) Reset() {
 	*x = RecommendResponse{}
-	mi := &file_v1_predict_predict_proto_msgTypes[1]
+	mi := &file_v1_predict_predict_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -87,7 +187,7 @@ func (x *RecommendResponse) String() string {
 func (*RecommendResponse) ProtoMessage() {}
 
 func (x *RecommendResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_v1_predict_predict_proto_msgTypes[1]
+	mi := &file_v1_predict_predict_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -100,7 +200,7 @@ func (x *RecommendResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RecommendResponse.ProtoReflect.Descriptor instead.
 func (*RecommendResponse) Descriptor() ([]byte, []int) {
-	return file_v1_predict_predict_proto_rawDescGZIP(), []int{1}
+	return file_v1_predict_predict_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *RecommendResponse) GetRecommendations() []*common.Recommendation {
@@ -110,20 +210,149 @@ func (x *RecommendResponse) GetRecommendations() []*common.Recommendation {
 	return nil
 }
 
+type ProfileMovie struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Weight        float64                `protobuf:"fixed64,2,opt,name=weight,proto3" json:"weight,omitempty"`
+	MovieId       int64                  `protobuf:"varint,3,opt,name=movieId,proto3" json:"movieId,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProfileMovie) Reset() {
+	*x = ProfileMovie{}
+	mi := &file_v1_predict_predict_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProfileMovie) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProfileMovie) ProtoMessage() {}
+
+func (x *ProfileMovie) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_predict_predict_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProfileMovie.ProtoReflect.Descriptor instead.
+func (*ProfileMovie) Descriptor() ([]byte, []int) {
+	return file_v1_predict_predict_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProfileMovie) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *ProfileMovie) GetMovieId() int64 {
+	if x != nil {
+		return x.MovieId
+	}
+	return 0
+}
+
+type UserRecommendRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Profile         []*ProfileMovie        `protobuf:"bytes,1,rep,name=profile,proto3" json:"profile,omitempty"`
+	TopK            int32                  `protobuf:"varint,3,opt,name=topK,proto3" json:"topK,omitempty"`
+	ExcludeMovieIds []int64                `protobuf:"varint,4,rep,packed,name=excludeMovieIds,proto3" json:"excludeMovieIds,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UserRecommendRequest) Reset() {
+	*x = UserRecommendRequest{}
+	mi := &file_v1_predict_predict_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserRecommendRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserRecommendRequest) ProtoMessage() {}
+
+func (x *UserRecommendRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v1_predict_predict_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserRecommendRequest.ProtoReflect.Descriptor instead.
+func (*UserRecommendRequest) Descriptor() ([]byte, []int) {
+	return file_v1_predict_predict_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UserRecommendRequest) GetProfile() []*ProfileMovie {
+	if x != nil {
+		return x.Profile
+	}
+	return nil
+}
+
+func (x *UserRecommendRequest) GetTopK() int32 {
+	if x != nil {
+		return x.TopK
+	}
+	return 0
+}
+
+func (x *UserRecommendRequest) GetExcludeMovieIds() []int64 {
+	if x != nil {
+		return x.ExcludeMovieIds
+	}
+	return nil
+}
+
 var File_v1_predict_predict_proto protoreflect.FileDescriptor
 
 const file_v1_predict_predict_proto_rawDesc = "" +
 	"\n" +
 	"\x18v1/predict/predict.proto\x12\n" +
-	"v1.predict\x1a\x12common/types.proto\"2\n" +
+	"v1.predict\x1a\x12common/types.proto\"\xcc\x01\n" +
 	"\x10RecommendRequest\x12\x1e\n" +
 	"\n" +
 	"movieTitle\x18\x01 \x01(\tR\n" +
-	"movieTitle\"U\n" +
+	"movieTitle\x125\n" +
+	"\vcontentType\x18\x02 \x01(\x0e2\x13.common.ContentTypeR\vcontentType\x12\x18\n" +
+	"\amovieId\x18\x03 \x01(\x03R\amovieId\x12\x12\n" +
+	"\x04topK\x18\x04 \x01(\x05R\x04topK\x123\n" +
+	"\x06filter\x18\x05 \x01(\v2\x1b.v1.predict.RecommendFilterR\x06filter\"\x87\x01\n" +
+	"\x0fRecommendFilter\x12\x16\n" +
+	"\x06genres\x18\x01 \x03(\tR\x06genres\x12\x1a\n" +
+	"\byearFrom\x18\x02 \x01(\x05R\byearFrom\x12\x16\n" +
+	"\x06yearTo\x18\x03 \x01(\x05R\x06yearTo\x12(\n" +
+	"\x0fexcludeMovieIds\x18\x04 \x03(\x03R\x0fexcludeMovieIds\"U\n" +
 	"\x11RecommendResponse\x12@\n" +
-	"\x0frecommendations\x18\x01 \x03(\v2\x16.common.RecommendationR\x0frecommendations2Z\n" +
+	"\x0frecommendations\x18\x01 \x03(\v2\x16.common.RecommendationR\x0frecommendations\"M\n" +
+	"\fProfileMovie\x12\x16\n" +
+	"\x06weight\x18\x02 \x01(\x01R\x06weight\x12\x18\n" +
+	"\amovieId\x18\x03 \x01(\x03R\amovieIdJ\x04\b\x01\x10\x02R\x05title\"\x9d\x01\n" +
+	"\x14UserRecommendRequest\x122\n" +
+	"\aprofile\x18\x01 \x03(\v2\x18.v1.predict.ProfileMovieR\aprofile\x12\x12\n" +
+	"\x04topK\x18\x03 \x01(\x05R\x04topK\x12(\n" +
+	"\x0fexcludeMovieIds\x18\x04 \x03(\x03R\x0fexcludeMovieIdsJ\x04\b\x02\x10\x03R\rexcludeTitles2\xaf\x01\n" +
 	"\x0eRecommendation\x12H\n" +
-	"\tRecommend\x12\x1c.v1.predict.RecommendRequest\x1a\x1d.v1.predict.RecommendResponseBNZLgithub.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict;predictb\x06proto3"
+	"\tRecommend\x12\x1c.v1.predict.RecommendRequest\x1a\x1d.v1.predict.RecommendResponse\x12S\n" +
+	"\x10RecommendForUser\x12 .v1.predict.UserRecommendRequest\x1a\x1d.v1.predict.RecommendResponseBNZLgithub.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict;predictb\x06proto3"
 
 var (
 	file_v1_predict_predict_proto_rawDescOnce sync.Once
@@ -137,21 +366,30 @@ func file_v1_predict_predict_proto_rawDescGZIP() []byte {
 	return file_v1_predict_predict_proto_rawDescData
 }
 
-var file_v1_predict_predict_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_v1_predict_predict_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
 var file_v1_predict_predict_proto_goTypes = []any{
 	(*RecommendRequest)(nil),      // 0: v1.predict.RecommendRequest
-	(*RecommendResponse)(nil),     // 1: v1.predict.RecommendResponse
-	(*common.Recommendation)(nil), // 2: common.Recommendation
+	(*RecommendFilter)(nil),       // 1: v1.predict.RecommendFilter
+	(*RecommendResponse)(nil),     // 2: v1.predict.RecommendResponse
+	(*ProfileMovie)(nil),          // 3: v1.predict.ProfileMovie
+	(*UserRecommendRequest)(nil),  // 4: v1.predict.UserRecommendRequest
+	(common.ContentType)(0),       // 5: common.ContentType
+	(*common.Recommendation)(nil), // 6: common.Recommendation
 }
 var file_v1_predict_predict_proto_depIdxs = []int32{
-	2, // 0: v1.predict.RecommendResponse.recommendations:type_name -> common.Recommendation
-	0, // 1: v1.predict.Recommendation.Recommend:input_type -> v1.predict.RecommendRequest
-	1, // 2: v1.predict.Recommendation.Recommend:output_type -> v1.predict.RecommendResponse
-	2, // [2:3] is the sub-list for method output_type
-	1, // [1:2] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	5, // 0: v1.predict.RecommendRequest.contentType:type_name -> common.ContentType
+	1, // 1: v1.predict.RecommendRequest.filter:type_name -> v1.predict.RecommendFilter
+	6, // 2: v1.predict.RecommendResponse.recommendations:type_name -> common.Recommendation
+	3, // 3: v1.predict.UserRecommendRequest.profile:type_name -> v1.predict.ProfileMovie
+	0, // 4: v1.predict.Recommendation.Recommend:input_type -> v1.predict.RecommendRequest
+	4, // 5: v1.predict.Recommendation.RecommendForUser:input_type -> v1.predict.UserRecommendRequest
+	2, // 6: v1.predict.Recommendation.Recommend:output_type -> v1.predict.RecommendResponse
+	2, // 7: v1.predict.Recommendation.RecommendForUser:output_type -> v1.predict.RecommendResponse
+	6, // [6:8] is the sub-list for method output_type
+	4, // [4:6] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_v1_predict_predict_proto_init() }
@@ -165,7 +403,7 @@ func file_v1_predict_predict_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_v1_predict_predict_proto_rawDesc), len(file_v1_predict_predict_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   2,
+			NumMessages:   5,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,8 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Recommendation_Recommend_FullMethodName = "/v1.predict.Recommendation/Recommend"
+	Recommendation_Recommend_FullMethodName        = "/v1.predict.Recommendation/Recommend"
+	Recommendation_RecommendForUser_FullMethodName = "/v1.predict.Recommendation/RecommendForUser"
 )
 
 // RecommendationClient is the client API for Recommendation service.
@@ -27,6 +28,7 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type RecommendationClient interface {
 	Recommend(ctx context.Context, in *RecommendRequest, opts ...grpc.CallOption) (*RecommendResponse, error)
+	RecommendForUser(ctx context.Context, in *UserRecommendRequest, opts ...grpc.CallOption) (*RecommendResponse, error)
 }
 
 type recommendationClient struct {
@@ -47,11 +49,22 @@ func (c *recommendationClient) Recommend(ctx context.Context, in *RecommendReque
 	return out, nil
 }
 
+func (c *recommendationClient) RecommendForUser(ctx context.Context, in *UserRecommendRequest, opts ...grpc.CallOption) (*RecommendResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecommendResponse)
+	err := c.cc.Invoke(ctx, Recommendation_RecommendForUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RecommendationServer is the server API for Recommendation service.
 // All implementations must embed UnimplementedRecommendationServer
 // for forward compatibility.
 type RecommendationServer interface {
 	Recommend(context.Context, *RecommendRequest) (*RecommendResponse, error)
+	RecommendForUser(context.Context, *UserRecommendRequest) (*RecommendResponse, error)
 	mustEmbedUnimplementedRecommendationServer()
 }
 
@@ -65,6 +78,9 @@ type UnimplementedRecommendationServer struct{}
 func (UnimplementedRecommendationServer) Recommend(context.Context, *RecommendRequest) (*RecommendResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Recommend not implemented")
 }
+func (UnimplementedRecommendationServer) RecommendForUser(context.Context, *UserRecommendRequest) (*RecommendResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecommendForUser not implemented")
+}
 func (UnimplementedRecommendationServer) mustEmbedUnimplementedRecommendationServer() {}
 func (UnimplementedRecommendationServer) testEmbeddedByValue()                        {}
 
@@ -104,6 +120,24 @@ func _Recommendation_Recommend_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Recommendation_RecommendForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRecommendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RecommendationServer).RecommendForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Recommendation_RecommendForUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RecommendationServer).RecommendForUser(ctx, req.(*UserRecommendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Recommendation_ServiceDesc is the grpc.ServiceDesc for Recommendation service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -115,6 +149,10 @@ var Recommendation_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Recommend",
 			Handler:    _Recommendation_Recommend_Handler,
 		},
+		{
+			MethodName: "RecommendForUser",
+			Handler:    _Recommendation_RecommendForUser_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "v1/predict/predict.proto",
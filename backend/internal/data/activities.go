@@ -0,0 +1,178 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Activity types.
+const (
+	ActivityRating = "rating"
+	ActivityReview = "review"
+	ActivityList   = "list"
+)
+
+// Activity is something a user did that their followers see in the feed.
+// Only the references matching Type are set: the movie and score for ratings,
+// the movie and review for reviews and the list for list updates.
+type Activity struct {
+	ID         int64     `json:"id" example:"1"`
+	UserID     int64     `json:"user_id" example:"2"`
+	UserName   string    `json:"user_name" example:"Anna"`
+	Type       string    `json:"type" example:"rating"`
+	MovieID    *int64    `json:"movie_id,omitempty" example:"1"`
+	MovieTitle string    `json:"movie_title,omitempty" example:"Se7en"`
+	Score      *int32    `json:"score,omitempty" example:"9"`
+	ReviewID   *int64    `json:"review_id,omitempty" example:"1"`
+	ListID     *int64    `json:"list_id,omitempty" example:"1"`
+	ListName   string    `json:"list_name,omitempty" example:"Best 90s thrillers"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type activityModel struct {
+	DB *sql.DB
+}
+
+// activityVisibleSQL leaves out activities about movies in trash, hidden reviews and lists that aren't public,
+// so changes of those are respected without touching the activities.
+const activityVisibleSQL = `
+	(movie_id IS NULL OR EXISTS (
+		SELECT 1 FROM movies WHERE movies.id = activities.movie_id AND movies.deleted_at IS NULL))
+	AND (review_id IS NULL OR EXISTS (
+		SELECT 1 FROM reviews WHERE reviews.id = activities.review_id AND reviews.hidden_at IS NULL))
+	AND (list_id IS NULL OR EXISTS (
+		SELECT 1 FROM lists WHERE lists.id = activities.list_id AND lists.visibility = 'public'))`
+
+// Insert records the activity.
+func (m activityModel) Insert(activity *Activity) error {
+	query := `
+	INSERT INTO activities (user_id, type, movie_id, score, review_id, list_id)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, created_at`
+
+	args := []any{activity.UserID, activity.Type, activity.MovieID, activity.Score, activity.ReviewID, activity.ListID}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, args...).Scan(&activity.ID, &activity.CreatedAt)
+}
+
+// GetFeed returns the feed of the user, activities of followed users that don't keep their activity private,
+// the latest first. The feed is built on read from the followed users' activities and paged with cursors only.
+func (m activityModel) GetFeed(userID int64, filters Filters) ([]*Activity, Metadata, error) {
+	where := `user_id IN (
+		SELECT f.followee_id
+		FROM follows f
+		JOIN users u ON u.id = f.followee_id
+		WHERE f.follower_id = $1 AND u.activity_visibility <> 'private')`
+
+	return m.getAll(where, userID, filters)
+}
+
+// GetForUser returns activities of the user, the latest first, paged with cursors only.
+// Callers check the privacy settings of the user.
+func (m activityModel) GetForUser(userID int64, filters Filters) ([]*Activity, Metadata, error) {
+	return m.getAll("user_id = $1", userID, filters)
+}
+
+// getAll returns a page of activities matching the where condition, userID is its $1 parameter.
+func (m activityModel) getAll(where string, userID int64, filters Filters) ([]*Activity, Metadata, error) {
+	args := []any{userID}
+
+	if filters.Cursor != "" {
+		c, err := filters.decodeCursor()
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		args = append(args, c.Value, c.ID)
+		where += " AND " + filters.keysetCondition("created_at", len(args)-1, len(args))
+	}
+
+	args = append(args, filters.limit()+1)
+
+	// activities are paged first, so users, movies and lists are only joined to a single page
+	query := fmt.Sprintf(`
+	SELECT a.id, a.user_id, u.name, a.type, a.movie_id, COALESCE(m.title, ''), a.score, a.review_id,
+		a.list_id, COALESCE(l.name, ''), a.created_at, a.created_at::text
+	FROM (
+		SELECT id, user_id, type, movie_id, score, review_id, list_id, created_at
+		FROM activities
+		WHERE %s AND %s
+		ORDER BY %s %s, id ASC
+		LIMIT $%d
+	) AS a
+	JOIN users u ON u.id = a.user_id
+	LEFT JOIN movies m ON m.id = a.movie_id
+	LEFT JOIN lists l ON l.id = a.list_id
+	ORDER BY a.%s %s, a.id ASC`, where, activityVisibleSQL, filters.sortColumn(), filters.sortDirection(), len(args),
+		filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	activities := []*Activity{}
+	var sortValues []string
+
+	for rows.Next() {
+		var activity Activity
+		var sortValue string
+
+		err := rows.Scan(
+			&activity.ID,
+			&activity.UserID,
+			&activity.UserName,
+			&activity.Type,
+			&activity.MovieID,
+			&activity.MovieTitle,
+			&activity.Score,
+			&activity.ReviewID,
+			&activity.ListID,
+			&activity.ListName,
+			&activity.CreatedAt,
+			&sortValue,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		activities = append(activities, &activity)
+		sortValues = append(sortValues, sortValue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := Metadata{PageSize: filters.PageSize}
+
+	if len(activities) > filters.limit() {
+		activities = activities[:filters.limit()]
+		last := activities[len(activities)-1]
+
+		metadata.NextCursor = encodeCursor(cursor{
+			Sort:  filters.Sort,
+			Value: sortValues[len(activities)-1],
+			ID:    last.ID,
+		})
+	}
+
+	return activities, metadata, nil
+}
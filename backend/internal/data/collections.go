@@ -0,0 +1,327 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrMovieInCollection = errors.New("movie already belongs to another collection")
+	ErrUnknownMovie      = errors.New("movie doesn't exist")
+)
+
+// Collection is an ordered group of movies, like a franchise or a trilogy.
+// Movies are only filled in by Get, MovieCount by GetAll and Get.
+type Collection struct {
+	ID         int64     `json:"id" example:"1"`
+	CreatedAt  time.Time `json:"-"`
+	Name       string    `json:"name" example:"The Lord of the Rings"`
+	Overview   string    `json:"overview,omitempty" example:"Frodo and the Fellowship set out to destroy the One Ring..."`
+	MovieCount int       `json:"movie_count" example:"3"`
+	Movies     []*Movie  `json:"movies,omitempty"`
+	Version    int32     `json:"version" example:"1"`
+}
+
+// MovieCollection is the reference to the collection a movie belongs to,
+// Position and Total let clients show "part 2 of 3".
+type MovieCollection struct {
+	ID       int64  `json:"id" example:"1"`
+	Name     string `json:"name" example:"The Lord of the Rings"`
+	Position int    `json:"position" example:"2"`
+	Total    int    `json:"total" example:"3"`
+}
+
+// Scan implements sql.Scanner for the JSON object built by movieCollectionSQL.
+func (c *MovieCollection) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("unsupported movie collection type %T", src)
+	}
+
+	return json.Unmarshal(b, c)
+}
+
+// movieCollectionSQL selects the collection reference of a movies row as a JSON object, keys match MovieCollection JSON tags.
+const movieCollectionSQL = "(SELECT jsonb_build_object('id', c.id, 'name', c.name, 'position', cm.position, " +
+	"'total', (SELECT count(*) FROM collection_movies WHERE collection_id = c.id)) " +
+	"FROM collection_movies cm JOIN collections c ON c.id = cm.collection_id WHERE cm.movie_id = movies.id)"
+
+type collectionModel struct {
+	DB *sql.DB
+}
+
+// collectionSelect selects collections with the number of their movies.
+const collectionSelect = `
+	SELECT c.id, c.created_at, c.name, c.overview, c.version,
+		(SELECT count(*) FROM collection_movies cm WHERE cm.collection_id = c.id)
+	FROM collections c`
+
+// scanCollection scans a row selected with collectionSelect.
+func scanCollection(row interface{ Scan(...any) error }, collection *Collection) error {
+	return row.Scan(
+		&collection.ID,
+		&collection.CreatedAt,
+		&collection.Name,
+		&collection.Overview,
+		&collection.Version,
+		&collection.MovieCount,
+	)
+}
+
+// GetAll returns a page of collections, name is matched case insensitive as a substring.
+func (m collectionModel) GetAll(name string, filters Filters) ([]*Collection, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), c.id, c.created_at, c.name, c.overview, c.version,
+		(SELECT count(*) FROM collection_movies cm WHERE cm.collection_id = c.id)
+	FROM collections c
+	WHERE c.name ILIKE '%%' || $1 || '%%'
+	ORDER BY c.%s %s, c.id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, name, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	collections := []*Collection{}
+
+	for rows.Next() {
+		var collection Collection
+
+		err := rows.Scan(
+			&totalRecords,
+			&collection.ID,
+			&collection.CreatedAt,
+			&collection.Name,
+			&collection.Overview,
+			&collection.Version,
+			&collection.MovieCount,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		collections = append(collections, &collection)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return collections, metadata, nil
+}
+
+// Get returns the collection with its movies in collection order, movies in trash are left out.
+func (m collectionModel) Get(id int64) (*Collection, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	var collection Collection
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := scanCollection(m.DB.QueryRowContext(ctx, collectionSelect+`
+	WHERE c.id = $1`, id), &collection)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	query := `
+	SELECT m.id, m.created_at, m.title, m.year, m.runtime, m.genres, m.version, m.rating, m.rating_count
+	FROM collection_movies cm
+	JOIN movies m ON m.id = cm.movie_id
+	WHERE cm.collection_id = $1 AND m.deleted_at IS NULL
+	ORDER BY cm.position`
+
+	rows, err := m.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	collection.Movies = []*Movie{}
+
+	for rows.Next() {
+		var movie Movie
+
+		err := rows.Scan(
+			&movie.ID,
+			&movie.CreatedAt,
+			&movie.Title,
+			&movie.Year,
+			&movie.Runtime,
+			pq.Array(&movie.Genres),
+			&movie.Version,
+			&movie.Rating,
+			&movie.RatingCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		collection.Movies = append(collection.Movies, &movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &collection, nil
+}
+
+// Insert creates an empty collection.
+func (m collectionModel) Insert(collection *Collection) error {
+	query := `
+	INSERT INTO collections (name, overview)
+	VALUES ($1, $2)
+	RETURNING id, created_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, collection.Name, collection.Overview).Scan(
+		&collection.ID,
+		&collection.CreatedAt,
+		&collection.Version,
+	)
+}
+
+// Update changes collection name and overview.
+func (m collectionModel) Update(collection *Collection) error {
+	query := `
+	UPDATE collections
+	SET name = $1, overview = $2, version = version + 1
+	WHERE id = $3 AND version = $4
+	RETURNING version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, collection.Name, collection.Overview, collection.ID, collection.Version).Scan(&collection.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the collection, its movies are kept.
+func (m collectionModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, "DELETE FROM collections WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SetMovies replaces movies of the collection, positions follow the order of the ids.
+// Ids must be unique, a repeated id would be reported as ErrMovieInCollection.
+// Returns ErrMovieInCollection if one of the movies is in another collection
+// and ErrUnknownMovie if one of them doesn't exist.
+func (m collectionModel) SetMovies(collection *Collection, movieIDs []int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		query := `
+		UPDATE collections SET version = version + 1
+		WHERE id = $1 AND version = $2
+		RETURNING version`
+
+		err := tx.QueryRowContext(ctx, query, collection.ID, collection.Version).Scan(&collection.Version)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEditConflict
+			default:
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM collection_movies WHERE collection_id = $1", collection.ID)
+		if err != nil {
+			return err
+		}
+
+		query = `
+		INSERT INTO collection_movies (collection_id, movie_id, position)
+		SELECT $1, movie_id, position
+		FROM unnest($2::bigint[]) WITH ORDINALITY AS ids (movie_id, position)`
+
+		_, err = tx.ExecContext(ctx, query, collection.ID, pq.Array(movieIDs))
+		if err != nil {
+			var pqErr *pq.Error
+
+			switch {
+			case errors.As(err, &pqErr) && pqErr.Code == "23505": // unique_violation
+				return ErrMovieInCollection
+			case errors.As(err, &pqErr) && pqErr.Code == "23503": // foreign_key_violation
+				return ErrUnknownMovie
+			default:
+				return err
+			}
+		}
+
+		collection.MovieCount = len(movieIDs)
+
+		return nil
+	})
+}
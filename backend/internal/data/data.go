@@ -1,6 +1,7 @@
 package data
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"time"
@@ -13,10 +14,25 @@ var (
 
 type moviesInterface interface {
 	Get(int64) (*Movie, error)
-	Insert(*Movie) error
-	Delete(int64) error
-	Update(*Movie) error
-	GetAll(string, []string, Filters) ([]*Movie, Metadata, error)
+	Insert(*Movie, int64) error
+	Delete(int64, int32, int64) error
+	Update(*Movie, int64) error
+	GetAll(MovieFilter, Filters) ([]*Movie, Metadata, error)
+	Suggest(string, int) ([]*MovieSuggestion, error)
+	Facets(MovieFilter, []string) (Facets, error)
+	GetDeleted(Filters) ([]*Movie, Metadata, error)
+	Restore(int64, int64) (*Movie, error)
+	PurgeDeleted(time.Time) (int64, error)
+	Batch([]*MovieOperation, bool, int64) error
+	FindDuplicate(*Movie) (*Movie, error)
+	GetRedirect(int64) (int64, error)
+	Merge(int64, int64, int64) (*Movie, error)
+	History(int64, Filters) ([]*MovieRevision, Metadata, error)
+	GetRevision(int64, int32) (*MovieRevision, error)
+	GetByExternalID(ExternalIDs) (*Movie, error)
+	SetExternalIDs(*Movie, ExternalIDs, int64) error
+	GetAllByTMDBID([]int64) ([]*Movie, error)
+	TMDBIDs([]int64) ([]int64, error)
 }
 
 type usersInterface interface {
@@ -25,6 +41,8 @@ type usersInterface interface {
 	GetByID(int64) (*User, error)
 	Update(*User) error
 	GetForToken(string, string) (*User, error)
+	GetPrivacy(int64) (*Privacy, error)
+	UpdatePrivacy(int64, *Privacy) error
 }
 
 type tokensInterface interface {
@@ -33,18 +51,191 @@ type tokensInterface interface {
 	DeleteAllForUser(scope string, userID int64) error
 }
 
+type permissionsInterface interface {
+	GetAllForUser(int64) (Permissions, error)
+	AddForUser(int64, ...string) error
+}
+
+type genresInterface interface {
+	GetAll() ([]*Genre, error)
+	Get(int64) (*Genre, error)
+	Resolve([]string) ([]string, []string, error)
+	Insert(*Genre) error
+	Update(*Genre, int64) error
+	Delete(int64) error
+	Merge(int64, int64, int64) (*Genre, error)
+}
+
+type collectionsInterface interface {
+	GetAll(string, Filters) ([]*Collection, Metadata, error)
+	Get(int64) (*Collection, error)
+	Insert(*Collection) error
+	Update(*Collection) error
+	Delete(int64) error
+	SetMovies(*Collection, []int64) error
+}
+
+type seriesInterface interface {
+	GetAll(string, []string, Filters) ([]*Series, Metadata, error)
+	Get(int64) (*Series, error)
+	Insert(*Series) error
+	Update(*Series) error
+	Delete(int64) error
+	GetAllByTMDBID([]int64) ([]*Series, error)
+	TMDBIDs([]int64) ([]int64, error)
+	GetSeason(int64, int32) (*Season, error)
+	PutSeason(int64, *Season) error
+	DeleteSeason(int64, int32) error
+}
+
+type searchInterface interface {
+	Search(string, []string, Filters) ([]*SearchResult, Metadata, error)
+}
+
+type ratingsInterface interface {
+	GetAllForUser(int64, Filters) ([]*Rating, Metadata, error)
+	Upsert(int64, *Rating) error
+	Delete(int64, int64) error
+}
+
+type watchlistInterface interface {
+	GetAll(int64, Filters) ([]*WatchlistItem, Metadata, error)
+	Add(int64, *WatchlistItem) (bool, error)
+	Remove(int64, int64) error
+	Mark(int64, []*Movie) error
+}
+
+type historyInterface interface {
+	GetAll(int64, Filters) ([]*WatchEvent, Metadata, error)
+	Insert(int64, *WatchEvent) error
+	Mark(int64, []*Movie) error
+}
+
+type reviewsInterface interface {
+	GetAllForMovie(int64, Filters) ([]*Review, Metadata, error)
+	Upsert(*Review) (bool, error)
+	Delete(int64, int64) error
+	Vote(int64, int64, bool) error
+	Unvote(int64, int64) error
+	Report(int64, int64, string) error
+	GetReported(Filters) ([]*ReportedReview, Metadata, error)
+	Moderate(int64, bool) error
+}
+
+type listsInterface interface {
+	Search(string, Filters) ([]*List, Metadata, error)
+	GetAllForUser(int64, Filters) ([]*List, Metadata, error)
+	Get(int64) (*List, error)
+	Insert(*List) error
+	Update(*List) error
+	Delete(int64) error
+	SetItems(*List, []*ListItem) error
+	Copy(*List, int64) (*List, error)
+}
+
+type followsInterface interface {
+	Follow(int64, int64) (bool, error)
+	Unfollow(int64, int64) error
+	IsFollowing(int64, int64) (bool, error)
+	GetFollowing(int64, Filters) ([]*Follow, Metadata, error)
+	GetFollowers(int64, Filters) ([]*Follow, Metadata, error)
+}
+
+type activitiesInterface interface {
+	Insert(*Activity) error
+	GetFeed(int64, Filters) ([]*Activity, Metadata, error)
+	GetForUser(int64, Filters) ([]*Activity, Metadata, error)
+}
+
+type eventsInterface interface {
+	InsertBatch([]*Event) (int64, error)
+}
+
+type dismissalsInterface interface {
+	DismissMovie(int64, int64) (bool, error)
+	UndismissMovie(int64, int64) error
+	DismissGenre(int64, int64) (bool, error)
+	UndismissGenre(int64, int64) error
+	GetAll(int64) (*Dismissals, error)
+	TMDBIDs(int64) ([]int64, error)
+	Suppressions(int64, []int64) (map[int64]Suppression, error)
+}
+
+type recommendationsInterface interface {
+	Profile(int64, int) ([]*ProfileMovie, error)
+	Excluded(int64) ([]int64, error)
+}
+
+type translationsInterface interface {
+	GetAll(int64) ([]*Translation, error)
+	Upsert(*Translation) error
+	Delete(int64, string) error
+	Localize([]*Movie, []string) error
+}
+
 type Models struct {
-	Movies moviesInterface
-	Users  usersInterface
-	Tokens tokensInterface
+	Movies          moviesInterface
+	Users           usersInterface
+	Tokens          tokensInterface
+	Permissions     permissionsInterface
+	Genres          genresInterface
+	Translations    translationsInterface
+	Collections     collectionsInterface
+	Series          seriesInterface
+	Search          searchInterface
+	Ratings         ratingsInterface
+	Watchlist       watchlistInterface
+	History         historyInterface
+	Reviews         reviewsInterface
+	Lists           listsInterface
+	Follows         followsInterface
+	Activities      activitiesInterface
+	Events          eventsInterface
+	Dismissals      dismissalsInterface
+	Recommendations recommendationsInterface
 }
 
 func NewModels(db *sql.DB) Models {
 	return Models{
-		Movies: movieModel{DB: db},
-		Users:  userModel{DB: db},
-		Tokens: tokenModel{DB: db},
+		Movies:          movieModel{DB: db},
+		Users:           userModel{DB: db},
+		Tokens:          tokenModel{DB: db},
+		Permissions:     permissionModel{DB: db},
+		Genres:          genreModel{DB: db},
+		Translations:    translationModel{DB: db},
+		Collections:     collectionModel{DB: db},
+		Series:          seriesModel{DB: db},
+		Search:          searchModel{DB: db},
+		Ratings:         ratingModel{DB: db},
+		Watchlist:       watchlistModel{DB: db},
+		History:         historyModel{DB: db},
+		Reviews:         reviewModel{DB: db},
+		Lists:           listModel{DB: db},
+		Follows:         followModel{DB: db},
+		Activities:      activityModel{DB: db},
+		Events:          eventModel{DB: db},
+		Dismissals:      dismissalModel{DB: db},
+		Recommendations: recommendationModel{DB: db},
 	}
 }
 
+// withTx runs fn inside a transaction, which is committed when fn succeeds and rolled back otherwise.
+func withTx(ctx context.Context, db *sql.DB, fn func(*sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	err = fn(tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
 // TODO: read about interface and how it should be for mocking dependency
@@ -0,0 +1,324 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// DismissedMovie is a movie the user is not interested in.
+type DismissedMovie struct {
+	MovieID     int64     `json:"movie_id" example:"1"`
+	Title       string    `json:"title" example:"The Shawshank Redemption"`
+	Year        int32     `json:"year" example:"1994"`
+	DismissedAt time.Time `json:"dismissed_at" example:"2025-01-01T00:00:00Z"`
+}
+
+// DismissedGenre is a genre the user wants less of.
+type DismissedGenre struct {
+	GenreID     int64     `json:"genre_id" example:"1"`
+	Name        string    `json:"name" example:"Horror"`
+	DismissedAt time.Time `json:"dismissed_at" example:"2025-01-01T00:00:00Z"`
+}
+
+// Dismissals are movies and genres the user is not interested in, the latest first.
+type Dismissals struct {
+	Movies []*DismissedMovie `json:"movies"`
+	Genres []*DismissedGenre `json:"genres"`
+}
+
+// Suppression tells how a recommended movie matches the user's dismissals:
+// whether the movie itself was dismissed and how many of its genres were.
+type Suppression struct {
+	Dismissed       bool
+	DismissedGenres int
+}
+
+type dismissalModel struct {
+	DB *sql.DB
+}
+
+// DismissMovie records that the user is not interested in the movie. Reports whether the movie
+// was dismissed now, returns ErrRecordNotFound if the movie doesn't exist or is in trash.
+func (m dismissalModel) DismissMovie(userID, movieID int64) (bool, error) {
+	query := `
+	WITH movie AS (
+		SELECT id FROM movies WHERE id = $2 AND deleted_at IS NULL
+	), dismissed AS (
+		INSERT INTO dismissed_movies (user_id, movie_id)
+		SELECT $1, id FROM movie
+		ON CONFLICT (user_id, movie_id) DO NOTHING
+		RETURNING movie_id
+	)
+	SELECT EXISTS (SELECT 1 FROM movie), EXISTS (SELECT 1 FROM dismissed)`
+
+	var found, dismissed bool
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, userID, movieID).Scan(&found, &dismissed)
+	if err != nil {
+		return false, err
+	}
+
+	if !found {
+		return false, ErrRecordNotFound
+	}
+
+	return dismissed, nil
+}
+
+// UndismissMovie removes the movie dismissal, returns ErrRecordNotFound if the user didn't dismiss the movie.
+func (m dismissalModel) UndismissMovie(userID, movieID int64) error {
+	return m.delete("DELETE FROM dismissed_movies WHERE user_id = $1 AND movie_id = $2", userID, movieID)
+}
+
+// DismissGenre records that the user wants less of the genre. Reports whether the genre
+// was dismissed now, returns ErrRecordNotFound if the genre doesn't exist.
+func (m dismissalModel) DismissGenre(userID, genreID int64) (bool, error) {
+	query := `
+	INSERT INTO dismissed_genres (user_id, genre_id)
+	VALUES ($1, $2)
+	ON CONFLICT (user_id, genre_id) DO NOTHING`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, userID, genreID)
+	if err != nil {
+		var pqErr *pq.Error
+
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code == "23503": // foreign_key_violation
+			return false, ErrRecordNotFound
+		default:
+			return false, err
+		}
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// UndismissGenre removes the genre dismissal, returns ErrRecordNotFound if the user didn't dismiss the genre.
+func (m dismissalModel) UndismissGenre(userID, genreID int64) error {
+	return m.delete("DELETE FROM dismissed_genres WHERE user_id = $1 AND genre_id = $2", userID, genreID)
+}
+
+// delete runs a query deleting a single dismissal of the user.
+func (m dismissalModel) delete(query string, userID, id int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, userID, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetAll returns the dismissals of the user, movies in trash are left out.
+func (m dismissalModel) GetAll(userID int64) (*Dismissals, error) {
+	query := `
+	SELECT d.movie_id, m.title, m.year, d.created_at
+	FROM dismissed_movies d
+	JOIN movies m ON m.id = d.movie_id
+	WHERE d.user_id = $1 AND m.deleted_at IS NULL
+	ORDER BY d.created_at DESC, d.movie_id ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	dismissals := &Dismissals{Movies: []*DismissedMovie{}, Genres: []*DismissedGenre{}}
+
+	for rows.Next() {
+		var movie DismissedMovie
+
+		err := rows.Scan(&movie.MovieID, &movie.Title, &movie.Year, &movie.DismissedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		dismissals.Movies = append(dismissals.Movies, &movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	query = `
+	SELECT d.genre_id, g.name, d.created_at
+	FROM dismissed_genres d
+	JOIN genres g ON g.id = d.genre_id
+	WHERE d.user_id = $1
+	ORDER BY d.created_at DESC, d.genre_id ASC`
+
+	genreRows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := genreRows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	for genreRows.Next() {
+		var genre DismissedGenre
+
+		err := genreRows.Scan(&genre.GenreID, &genre.Name, &genre.DismissedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		dismissals.Genres = append(dismissals.Genres, &genre)
+	}
+
+	if err = genreRows.Err(); err != nil {
+		return nil, err
+	}
+
+	return dismissals, nil
+}
+
+// TMDBIDs returns TMDB ids of the movies the user dismissed, the recommendation service knows movies by them.
+// Dismissed movies without a TMDB id are left out.
+func (m dismissalModel) TMDBIDs(userID int64) ([]int64, error) {
+	query := `
+	SELECT e.tmdb_id
+	FROM dismissed_movies d
+	JOIN movie_external_ids e ON e.movie_id = d.movie_id
+	WHERE d.user_id = $1 AND e.tmdb_id IS NOT NULL`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	tmdbIDs := []int64{}
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		tmdbIDs = append(tmdbIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tmdbIDs, nil
+}
+
+// Suppressions matches recommended movies against the dismissals of the user with a single query.
+// Movies matching no dismissal are left out of the result.
+func (m dismissalModel) Suppressions(userID int64, movieIDs []int64) (map[int64]Suppression, error) {
+	suppressions := make(map[int64]Suppression)
+
+	if len(movieIDs) == 0 {
+		return suppressions, nil
+	}
+
+	query := `
+	SELECT m.id,
+		EXISTS (
+			SELECT 1 FROM dismissed_movies d WHERE d.user_id = $1 AND d.movie_id = m.id),
+		(
+			SELECT count(*)
+			FROM dismissed_genres d
+			JOIN genres g ON g.id = d.genre_id
+			WHERE d.user_id = $1 AND g.name::text = ANY(m.genres))
+	FROM movies m
+	WHERE m.id = ANY($2)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, pq.Array(movieIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	for rows.Next() {
+		var id int64
+		var suppression Suppression
+
+		err := rows.Scan(&id, &suppression.Dismissed, &suppression.DismissedGenres)
+		if err != nil {
+			return nil, err
+		}
+
+		if suppression.Dismissed || suppression.DismissedGenres > 0 {
+			suppressions[id] = suppression
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suppressions, nil
+}
@@ -0,0 +1,192 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// normalizedTitle is an SQL expression comparing titles without case, spaces and punctuation,
+// it matches the expression of the movies_normalized_title_year_idx index.
+const normalizedTitle = "regexp_replace(lower(title), '[^[:alnum:]]+', '', 'g')"
+
+// FindDuplicate returns a movie that is likely the same as the given one: not deleted, with one of
+// the external ids of the given movie or with the same year and the same title ignoring case, spaces
+// and punctuation. Movies sharing an external id go first. Returns ErrRecordNotFound if there is no such movie.
+func (m movieModel) FindDuplicate(movie *Movie) (*Movie, error) {
+	// the branches are kept apart, so each of them can use its index,
+	// unset external ids are passed as NULL, which never equals anything
+	query := `
+	SELECT id, created_at, title, year, runtime, genres, version
+	FROM (
+		SELECT movies.*, 0 AS rank
+		FROM movies
+		JOIN movie_external_ids e ON e.movie_id = movies.id
+		WHERE e.imdb_id = $4 OR e.tmdb_id = $5 OR e.movielens_id = $6
+		UNION ALL
+		SELECT movies.*, 1
+		FROM movies
+		WHERE ` + normalizedTitle + ` = regexp_replace(lower($1), '[^[:alnum:]]+', '', 'g') AND year = $2
+	) candidates
+	WHERE id <> $3 AND deleted_at IS NULL
+	ORDER BY rank, id
+	LIMIT 1`
+
+	var ids ExternalIDs
+	if movie.ExternalIDs != nil {
+		ids = *movie.ExternalIDs
+	}
+
+	var duplicate Movie
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, movie.Title, movie.Year, movie.ID,
+		nullIfZero(ids.IMDb), nullIfZero(ids.TMDB), nullIfZero(ids.MovieLens)).Scan(
+		&duplicate.ID,
+		&duplicate.CreatedAt,
+		&duplicate.Title,
+		&duplicate.Year,
+		&duplicate.Runtime,
+		pq.Array(&duplicate.Genres),
+		&duplicate.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &duplicate, nil
+}
+
+// GetRedirect returns the id of the movie the merged movie with the given id was folded into.
+func (m movieModel) GetRedirect(id int64) (int64, error) {
+	if id < 1 {
+		return 0, ErrRecordNotFound
+	}
+
+	var newID int64
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, "SELECT new_id FROM movie_redirects WHERE old_id = $1", id).Scan(&newID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, ErrRecordNotFound
+		default:
+			return 0, err
+		}
+	}
+
+	return newID, nil
+}
+
+// Merge folds the source movie into the target one. Rows referencing the source movie are moved
+// to the target unless the target already has an equivalent row, the source movie is deleted
+// permanently and a redirect from its id to the target is left. Returns the updated target movie.
+func (m movieModel) Merge(sourceID, targetID int64, actorID int64) (*Movie, error) {
+	if sourceID < 1 || targetID < 1 || sourceID == targetID {
+		return nil, ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		// locking both movies in id order, so concurrent merges of the same pair can't deadlock
+		query := `
+		SELECT count(*) FROM (
+			SELECT id FROM movies
+			WHERE id IN ($1, $2) AND deleted_at IS NULL
+			ORDER BY id
+			FOR UPDATE
+		) AS locked`
+
+		var locked int
+
+		err := tx.QueryRowContext(ctx, query, sourceID, targetID).Scan(&locked)
+		if err != nil {
+			return err
+		}
+
+		if locked != 2 {
+			return ErrRecordNotFound
+		}
+
+		// translations, external ids, collection membership, list items, ratings, reviews, watchlist, seen and dismissed entries
+		// the target doesn't have yet are moved, the rest is deleted with the source,
+		// watch history, activities and feedback events are moved as a whole
+		queries := []string{
+			`UPDATE movie_translations SET movie_id = $2
+			WHERE movie_id = $1 AND language NOT IN (SELECT language FROM movie_translations WHERE movie_id = $2)`,
+			`WITH source AS (DELETE FROM movie_external_ids WHERE movie_id = $1 RETURNING imdb_id, tmdb_id, movielens_id)
+			INSERT INTO movie_external_ids (movie_id, imdb_id, tmdb_id, movielens_id)
+			SELECT $2, imdb_id, tmdb_id, movielens_id FROM source
+			ON CONFLICT (movie_id) DO UPDATE
+			SET imdb_id = COALESCE(movie_external_ids.imdb_id, EXCLUDED.imdb_id),
+				tmdb_id = COALESCE(movie_external_ids.tmdb_id, EXCLUDED.tmdb_id),
+				movielens_id = COALESCE(movie_external_ids.movielens_id, EXCLUDED.movielens_id)`,
+			`UPDATE collection_movies SET movie_id = $2
+			WHERE movie_id = $1 AND NOT EXISTS (SELECT 1 FROM collection_movies WHERE movie_id = $2)`,
+			`UPDATE list_items SET movie_id = $2
+			WHERE movie_id = $1 AND list_id NOT IN (SELECT list_id FROM list_items WHERE movie_id = $2)`,
+			`UPDATE ratings SET movie_id = $2
+			WHERE movie_id = $1 AND user_id NOT IN (SELECT user_id FROM ratings WHERE movie_id = $2)`,
+			`UPDATE reviews SET movie_id = $2
+			WHERE movie_id = $1 AND user_id NOT IN (SELECT user_id FROM reviews WHERE movie_id = $2)`,
+			`UPDATE watchlist SET movie_id = $2
+			WHERE movie_id = $1 AND user_id NOT IN (SELECT user_id FROM watchlist WHERE movie_id = $2)`,
+			`UPDATE seen_movies SET movie_id = $2
+			WHERE movie_id = $1 AND user_id NOT IN (SELECT user_id FROM seen_movies WHERE movie_id = $2)`,
+			`UPDATE dismissed_movies SET movie_id = $2
+			WHERE movie_id = $1 AND user_id NOT IN (SELECT user_id FROM dismissed_movies WHERE movie_id = $2)`,
+			`UPDATE watch_events SET movie_id = $2 WHERE movie_id = $1`,
+			`UPDATE activities SET movie_id = $2 WHERE movie_id = $1`,
+			`UPDATE events SET movie_id = $2 WHERE movie_id = $1`,
+			`UPDATE movie_redirects SET new_id = $2 WHERE new_id = $1`,
+			`INSERT INTO movie_redirects (old_id, new_id) VALUES ($1, $2)`,
+		}
+
+		for _, query := range queries {
+			_, err = tx.ExecContext(ctx, query, sourceID, targetID)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM movies WHERE id = $1", sourceID)
+		if err != nil {
+			return err
+		}
+
+		// rating aggregates are recalculated as moved ratings can't be added incrementally
+		query = `
+		UPDATE movies
+		SET version = version + 1,
+			rating_sum = (SELECT COALESCE(sum(score), 0) FROM ratings WHERE movie_id = $1),
+			rating_count = (SELECT count(*) FROM ratings WHERE movie_id = $1)
+		WHERE id = $1`
+
+		_, err = tx.ExecContext(ctx, query, targetID)
+		if err != nil {
+			return err
+		}
+
+		return insertRevision(ctx, tx, targetID, RevisionMerge, []string{}, actorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Get(targetID)
+}
@@ -0,0 +1,88 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Event types.
+const (
+	EventClick       = "click"
+	EventDetailView  = "detail_view"
+	EventTrailerPlay = "trailer_play"
+	EventDwell       = "dwell"
+)
+
+var EventTypes = []any{EventClick, EventDetailView, EventTrailerPlay, EventDwell}
+
+// Event is an implicit feedback signal of a user about a movie. OccurredAt is the client time of the event
+// and DedupeKey, unique per user, lets clients resend batches without counting events twice.
+type Event struct {
+	UserID     int64     `json:"-"`
+	Type       string    `json:"type" example:"dwell"`
+	MovieID    int64     `json:"movie_id" example:"1"`
+	DwellMS    *int32    `json:"dwell_ms,omitempty" example:"45000"`
+	OccurredAt time.Time `json:"occurred_at" example:"2025-01-01T20:00:00Z"`
+	DedupeKey  string    `json:"dedupe_key" example:"3f2b6c1e-4d8a-4f6e-9c1d-2a7b5e8f0c3d"`
+}
+
+type eventModel struct {
+	DB *sql.DB
+}
+
+// InsertBatch saves the events in a single query and returns how many were saved.
+// Events already saved under the same dedupe key and events about unknown movies are skipped.
+func (m eventModel) InsertBatch(events []*Event) (int64, error) {
+	if len(events) == 0 {
+		return 0, nil
+	}
+
+	userIDs := make([]int64, len(events))
+	types := make([]string, len(events))
+	movieIDs := make([]int64, len(events))
+	dwells := make([]sql.NullInt32, len(events))
+	occurredAts := make([]time.Time, len(events))
+	dedupeKeys := make([]string, len(events))
+
+	for i, event := range events {
+		userIDs[i] = event.UserID
+		types[i] = event.Type
+		movieIDs[i] = event.MovieID
+		occurredAts[i] = event.OccurredAt
+		dedupeKeys[i] = event.DedupeKey
+
+		if event.DwellMS != nil {
+			dwells[i] = sql.NullInt32{Int32: *event.DwellMS, Valid: true}
+		}
+	}
+
+	query := `
+	INSERT INTO events (user_id, type, movie_id, dwell_ms, occurred_at, dedupe_key)
+	SELECT e.user_id, e.type, e.movie_id, e.dwell_ms, e.occurred_at, e.dedupe_key
+	FROM unnest($1::bigint[], $2::text[], $3::bigint[], $4::integer[], $5::timestamptz[], $6::text[])
+		AS e (user_id, type, movie_id, dwell_ms, occurred_at, dedupe_key)
+	JOIN movies m ON m.id = e.movie_id
+	ON CONFLICT (user_id, dedupe_key) DO NOTHING`
+
+	args := []any{
+		pq.Array(userIDs),
+		pq.Array(types),
+		pq.Array(movieIDs),
+		pq.GenericArray{A: dwells},
+		pq.GenericArray{A: occurredAts},
+		pq.Array(dedupeKeys),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
@@ -0,0 +1,304 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	ExternalIMDb      = "imdb"
+	ExternalTMDB      = "tmdb"
+	ExternalMovieLens = "movielens"
+)
+
+var ErrDuplicateExternalID = errors.New("is already assigned to another movie")
+
+// externalIDColumns maps external id sources to movie_external_ids columns.
+var externalIDColumns = map[string]string{
+	ExternalIMDb:      "imdb_id",
+	ExternalTMDB:      "tmdb_id",
+	ExternalMovieLens: "movielens_id",
+}
+
+// externalIDsSQL selects external ids of a movies row as a JSON object, keys match ExternalIDs JSON tags.
+const externalIDsSQL = "(SELECT jsonb_strip_nulls(jsonb_build_object('imdb', imdb_id, 'tmdb', tmdb_id, 'movielens', movielens_id)) " +
+	"FROM movie_external_ids WHERE movie_id = movies.id)"
+
+// ExternalIDs holds identifiers of the movie in other catalogs and datasets.
+type ExternalIDs struct {
+	IMDb      string `json:"imdb,omitempty" example:"tt0111161"`
+	TMDB      int64  `json:"tmdb,omitempty" example:"278"`
+	MovieLens int64  `json:"movielens,omitempty" example:"318"`
+}
+
+// IsEmpty reports whether none of the external ids is set.
+func (e ExternalIDs) IsEmpty() bool {
+	return e == ExternalIDs{}
+}
+
+// Scan implements sql.Scanner for the JSON object built by externalIDsSQL.
+func (e *ExternalIDs) Scan(src any) error {
+	b, ok := src.([]byte)
+	if !ok {
+		return fmt.Errorf("unsupported external ids type %T", src)
+	}
+
+	return json.Unmarshal(b, e)
+}
+
+// nullIfZero lets unset external ids be stored as NULL, so they don't collide on unique constraints.
+func nullIfZero[T comparable](v T) any {
+	var zero T
+	if v == zero {
+		return nil
+	}
+
+	return v
+}
+
+// GetByExternalID returns the movie with the first of the IMDb, TMDB and MovieLens ids that is set,
+// the id is compared with its typed column, so the unique index is used.
+func (m movieModel) GetByExternalID(ids ExternalIDs) (*Movie, error) {
+	var column string
+	var externalID any
+
+	switch {
+	case ids.IMDb != "":
+		column, externalID = externalIDColumns[ExternalIMDb], ids.IMDb
+	case ids.TMDB != 0:
+		column, externalID = externalIDColumns[ExternalTMDB], ids.TMDB
+	case ids.MovieLens != 0:
+		column, externalID = externalIDColumns[ExternalMovieLens], ids.MovieLens
+	default:
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+		SELECT id, created_at, title, year, runtime, genres, version, rating, rating_count, ` + externalIDsSQL + `, ` + movieCollectionSQL + `
+		FROM movies
+		WHERE id = (SELECT movie_id FROM movie_external_ids WHERE ` + column + ` = $1) AND deleted_at IS NULL
+	`
+
+	var movie Movie
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, externalID).Scan(
+		&movie.ID,
+		&movie.CreatedAt,
+		&movie.Title,
+		&movie.Year,
+		&movie.Runtime,
+		pq.Array(&movie.Genres),
+		&movie.Version,
+		&movie.Rating,
+		&movie.RatingCount,
+		&movie.ExternalIDs,
+		&movie.Collection,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &movie, nil
+}
+
+// SetExternalIDs replaces external ids of the movie, empty ids remove the mapping.
+// The movie version is bumped so cached representations are invalidated.
+// Returns ErrDuplicateExternalID wrapped with the source if another movie already has one of the ids.
+func (m movieModel) SetExternalIDs(movie *Movie, ids ExternalIDs, actorID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		query := `
+		UPDATE movies SET version = version + 1
+		WHERE id = $1 AND version = $2 AND deleted_at IS NULL
+		RETURNING version`
+
+		err := tx.QueryRowContext(ctx, query, movie.ID, movie.Version).Scan(&movie.Version)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEditConflict
+			default:
+				return err
+			}
+		}
+
+		if ids.IsEmpty() {
+			_, err = tx.ExecContext(ctx, "DELETE FROM movie_external_ids WHERE movie_id = $1", movie.ID)
+		} else {
+			err = upsertExternalIDs(ctx, tx, movie.ID, ids)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = insertRevision(ctx, tx, movie.ID, RevisionUpdate, []string{"external_ids"}, actorID)
+		if err != nil {
+			return err
+		}
+
+		movie.ExternalIDs = nil
+		if !ids.IsEmpty() {
+			movie.ExternalIDs = &ids
+		}
+
+		return nil
+	})
+}
+
+// upsertExternalIDs saves external ids of the movie replacing the previous ones.
+// Returns ErrDuplicateExternalID wrapped with the source if another movie already has one of the ids.
+func upsertExternalIDs(ctx context.Context, tx *sql.Tx, movieID int64, ids ExternalIDs) error {
+	query := `
+	INSERT INTO movie_external_ids (movie_id, imdb_id, tmdb_id, movielens_id)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (movie_id) DO UPDATE
+	SET imdb_id = EXCLUDED.imdb_id, tmdb_id = EXCLUDED.tmdb_id, movielens_id = EXCLUDED.movielens_id`
+
+	_, err := tx.ExecContext(ctx, query, movieID, nullIfZero(ids.IMDb), nullIfZero(ids.TMDB), nullIfZero(ids.MovieLens))
+	if err != nil {
+		var pqErr *pq.Error
+
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+			for source, column := range externalIDColumns {
+				if pqErr.Constraint == "movie_external_ids_"+column+"_key" {
+					return fmt.Errorf("%s: %w", source, ErrDuplicateExternalID)
+				}
+			}
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// GetAllByTMDBID returns the movies with the given TMDB ids with a single query, in the order of the ids.
+// Ids of movies missing from the catalog or in trash are left out.
+func (m movieModel) GetAllByTMDBID(ids []int64) ([]*Movie, error) {
+	movies := []*Movie{}
+
+	if len(ids) == 0 {
+		return movies, nil
+	}
+
+	query := `
+		SELECT movies.id, movies.created_at, movies.title, movies.year, movies.runtime, movies.genres, movies.version,
+			movies.rating, movies.rating_count, ` + externalIDsSQL + `, ` + movieCollectionSQL + `
+		FROM unnest($1::bigint[]) WITH ORDINALITY AS t (tmdb_id, position)
+		JOIN movie_external_ids e ON e.tmdb_id = t.tmdb_id
+		JOIN movies ON movies.id = e.movie_id
+		WHERE movies.deleted_at IS NULL
+		ORDER BY t.position
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	for rows.Next() {
+		var movie Movie
+
+		err := rows.Scan(
+			&movie.ID,
+			&movie.CreatedAt,
+			&movie.Title,
+			&movie.Year,
+			&movie.Runtime,
+			pq.Array(&movie.Genres),
+			&movie.Version,
+			&movie.Rating,
+			&movie.RatingCount,
+			&movie.ExternalIDs,
+			&movie.Collection,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		movies = append(movies, &movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return movies, nil
+}
+
+// TMDBIDs returns TMDB ids of the movies, movies without one are left out.
+func (m movieModel) TMDBIDs(movieIDs []int64) ([]int64, error) {
+	tmdbIDs := []int64{}
+
+	if len(movieIDs) == 0 {
+		return tmdbIDs, nil
+	}
+
+	query := `
+		SELECT tmdb_id
+		FROM movie_external_ids
+		WHERE movie_id = ANY($1) AND tmdb_id IS NOT NULL
+	`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(movieIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		tmdbIDs = append(tmdbIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tmdbIDs, nil
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalIDsScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    ExternalIDs
+		wantErr bool
+	}{
+		{
+			name: "All ids",
+			src:  []byte(`{"imdb": "tt0111161", "tmdb": 278, "movielens": 318}`),
+			want: ExternalIDs{IMDb: "tt0111161", TMDB: 278, MovieLens: 318},
+		},
+		{
+			name: "Stripped nulls",
+			src:  []byte(`{"tmdb": 278}`),
+			want: ExternalIDs{TMDB: 278},
+		},
+		{
+			name:    "Unsupported type",
+			src:     "tt0111161",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ids ExternalIDs
+
+			err := ids.Scan(tt.src)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, ids)
+		})
+	}
+}
+
+func TestNullIfZero(t *testing.T) {
+	assert.Nil(t, nullIfZero(""))
+	assert.Nil(t, nullIfZero(int64(0)))
+	assert.Equal(t, "tt0111161", nullIfZero("tt0111161"))
+	assert.Equal(t, int64(278), nullIfZero(int64(278)))
+}
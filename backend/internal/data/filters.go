@@ -1,15 +1,28 @@
 package data
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"math"
 	"strings"
 )
 
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+// Filters holds pagination and sorting options for list queries.
+// When Cursor is set keyset pagination is used and Page is ignored.
+// IncludeTotal controls whether the (potentially expensive) total number of records is calculated.
 type Filters struct {
 	Page         int
 	PageSize     int
 	Sort         string
 	SortSafeList []string
+	Cursor       string
+	IncludeTotal bool
 }
 
 // sortColumn returns the column name for sorting without the "-" prefix if it exists.
@@ -48,12 +61,65 @@ func (f Filters) offset() int {
 	return (f.Page - 1) * f.PageSize
 }
 
+// cursor is the decoded form of an opaque pagination cursor.
+// It holds the sort key the page was ordered by and the sort value and id of the last returned row.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    int64  `json:"id"`
+}
+
+// encodeCursor converts cursor to an opaque url-safe string.
+func encodeCursor(c cursor) string {
+	js, err := json.Marshal(c)
+	if err != nil {
+		// marshaling of plain struct with strings and integers can not fail
+		panic(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(js)
+}
+
+// decodeCursor decodes Cursor and checks that it was created for the current sort parameter.
+func (f Filters) decodeCursor() (cursor, error) {
+	js, err := base64.RawURLEncoding.DecodeString(f.Cursor)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	var c cursor
+
+	err = json.Unmarshal(js, &c)
+	if err != nil {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	if c.Sort != f.Sort || c.ID < 1 {
+		return cursor{}, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// keysetCondition returns an SQL condition selecting rows that come after the cursor position
+// for the ORDER BY <column> <direction>, id ASC ordering.
+// valueArg and idArg are the positions of the cursor value and id placeholders.
+func (f Filters) keysetCondition(column string, valueArg, idArg int) string {
+	op := ">"
+	if f.sortDirection() == "DESC" {
+		op = "<"
+	}
+
+	return fmt.Sprintf("(%[1]s %[2]s $%[3]d OR (%[1]s = $%[3]d AND id > $%[4]d))", column, op, valueArg, idArg)
+}
+
 type Metadata struct {
-	CurrentPage  int `json:"current_page,omitempty" example:"1"`
-	PageSize     int `json:"page_page,omitempty" example:"20"`
-	FirstPage    int `json:"first_page,omitempty" example:"1"`
-	LastPage     int `json:"last_page,omitempty" example:"5"`
-	TotalRecords int `json:"total_records,omitempty" example:"100"`
+	CurrentPage  int    `json:"current_page,omitempty" example:"1"`
+	PageSize     int    `json:"page_page,omitempty" example:"20"`
+	FirstPage    int    `json:"first_page,omitempty" example:"1"`
+	LastPage     int    `json:"last_page,omitempty" example:"5"`
+	TotalRecords int    `json:"total_records,omitempty" example:"100"`
+	NextCursor   string `json:"next_cursor,omitempty" example:"eyJzIjoiaWQiLCJ2IjoiMjAiLCJpZCI6MjB9"`
 }
 
 func calculateMetadata(totalRecords, page, pageSize int) Metadata {
@@ -69,3 +135,17 @@ func calculateMetadata(totalRecords, page, pageSize int) Metadata {
 		TotalRecords: totalRecords,
 	}
 }
+
+// calculatePageMetadata returns metadata for a page when the total number of records is not known.
+// For keyset pagination only the page size is meaningful.
+func calculatePageMetadata(filters Filters) Metadata {
+	if filters.Cursor != "" {
+		return Metadata{PageSize: filters.PageSize}
+	}
+
+	return Metadata{
+		CurrentPage: filters.Page,
+		PageSize:    filters.PageSize,
+		FirstPage:   1,
+	}
+}
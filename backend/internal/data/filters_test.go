@@ -0,0 +1,86 @@
+package data
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDecodeCursor(t *testing.T) {
+	valid := encodeCursor(cursor{Sort: "-year", Value: "1994", ID: 42})
+
+	tests := []struct {
+		name    string
+		filters Filters
+		want    cursor
+		wantErr error
+	}{
+		{
+			name:    "Valid",
+			filters: Filters{Sort: "-year", Cursor: valid},
+			want:    cursor{Sort: "-year", Value: "1994", ID: 42},
+		},
+		{
+			name:    "Different sort",
+			filters: Filters{Sort: "year", Cursor: valid},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "Not base64",
+			filters: Filters{Sort: "-year", Cursor: "not a cursor!"},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "Not JSON",
+			filters: Filters{Sort: "-year", Cursor: "bm90IGpzb24"},
+			wantErr: ErrInvalidCursor,
+		},
+		{
+			name:    "Missing id",
+			filters: Filters{Sort: "-year", Cursor: encodeCursor(cursor{Sort: "-year", Value: "1994"})},
+			wantErr: ErrInvalidCursor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.filters.decodeCursor()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v; want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("got %+v; want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	tests := []struct {
+		name string
+		sort string
+		want string
+	}{
+		{
+			name: "Ascending",
+			sort: "title",
+			want: "(title > $3 OR (title = $3 AND id > $4))",
+		},
+		{
+			name: "Descending",
+			sort: "-title",
+			want: "(title < $3 OR (title = $3 AND id > $4))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := Filters{Sort: tt.sort}
+
+			got := f.keysetCondition("title", 3, 4)
+			if got != tt.want {
+				t.Errorf("got %q; want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrSelfFollow = errors.New("users can't follow themselves")
+)
+
+// Follow is a user on the other side of a follow relation.
+type Follow struct {
+	UserID     int64     `json:"user_id" example:"2"`
+	Name       string    `json:"name" example:"Anna"`
+	FollowedAt time.Time `json:"followed_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type followModel struct {
+	DB *sql.DB
+}
+
+// Follow makes the follower follow the followee. Reports whether the follow is new,
+// returns ErrSelfFollow for the user itself and ErrRecordNotFound if the followee doesn't exist.
+func (m followModel) Follow(followerID, followeeID int64) (bool, error) {
+	if followerID == followeeID {
+		return false, ErrSelfFollow
+	}
+
+	query := `
+	INSERT INTO follows (follower_id, followee_id)
+	VALUES ($1, $2)
+	ON CONFLICT (follower_id, followee_id) DO NOTHING`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		var pqErr *pq.Error
+
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code == "23503": // foreign_key_violation
+			return false, ErrRecordNotFound
+		default:
+			return false, err
+		}
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// Unfollow removes the follow relation, returns ErrRecordNotFound if the follower doesn't follow the followee.
+func (m followModel) Unfollow(followerID, followeeID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := "DELETE FROM follows WHERE follower_id = $1 AND followee_id = $2"
+
+	result, err := m.DB.ExecContext(ctx, query, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// IsFollowing reports whether the follower follows the followee.
+func (m followModel) IsFollowing(followerID, followeeID int64) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	query := "SELECT EXISTS (SELECT 1 FROM follows WHERE follower_id = $1 AND followee_id = $2)"
+
+	var following bool
+
+	err := m.DB.QueryRowContext(ctx, query, followerID, followeeID).Scan(&following)
+
+	return following, err
+}
+
+// GetFollowing returns a page of users the user follows.
+func (m followModel) GetFollowing(userID int64, filters Filters) ([]*Follow, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), u.id, u.name, f.created_at AS followed_at
+	FROM follows f
+	JOIN users u ON u.id = f.followee_id
+	WHERE f.follower_id = $1
+	ORDER BY %s %s, u.id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	return m.getAll(query, userID, filters)
+}
+
+// GetFollowers returns a page of users following the user.
+func (m followModel) GetFollowers(userID int64, filters Filters) ([]*Follow, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), u.id, u.name, f.created_at AS followed_at
+	FROM follows f
+	JOIN users u ON u.id = f.follower_id
+	WHERE f.followee_id = $1
+	ORDER BY %s %s, u.id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	return m.getAll(query, userID, filters)
+}
+
+// getAll runs a page query selecting the total count and Follow columns for the user.
+func (m followModel) getAll(query string, userID int64, filters Filters) ([]*Follow, Metadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	follows := []*Follow{}
+
+	for rows.Next() {
+		var follow Follow
+
+		err := rows.Scan(&totalRecords, &follow.UserID, &follow.Name, &follow.FollowedAt)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		follows = append(follows, &follow)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return follows, metadata, nil
+}
@@ -0,0 +1,459 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrDuplicateGenre = errors.New("genre name or alias is already used")
+	ErrGenreInUse     = errors.New("genre is used by movies or series, merge it into another genre instead")
+)
+
+// Genre is a canonical genre name. Aliases are alternative spellings that resolve to the genre.
+// MovieCount is only filled in by GetAll and Get.
+type Genre struct {
+	ID         int64     `json:"id" example:"1"`
+	CreatedAt  time.Time `json:"-"`
+	Name       string    `json:"name" example:"Science Fiction"`
+	Aliases    []string  `json:"aliases" example:"Sci-Fi,SF"`
+	MovieCount int       `json:"movie_count" example:"42"`
+	Version    int32     `json:"version" example:"1"`
+}
+
+type genreModel struct {
+	DB *sql.DB
+}
+
+// genreSelect selects genres with their aliases and number of movies.
+const genreSelect = `
+	SELECT g.id, g.created_at, g.name, g.version,
+		ARRAY(SELECT a.alias::text FROM genre_aliases a WHERE a.genre_id = g.id ORDER BY a.alias),
+		(SELECT count(*) FROM movies m WHERE m.genres @> ARRAY[g.name::text] AND m.deleted_at IS NULL)
+	FROM genres g`
+
+// scanGenre scans a row selected with genreSelect.
+func scanGenre(row interface{ Scan(...any) error }, genre *Genre) error {
+	return row.Scan(
+		&genre.ID,
+		&genre.CreatedAt,
+		&genre.Name,
+		&genre.Version,
+		pq.Array(&genre.Aliases),
+		&genre.MovieCount,
+	)
+}
+
+// GetAll returns all genres ordered by name.
+func (m genreModel) GetAll() ([]*Genre, error) {
+	query := genreSelect + `
+	ORDER BY g.name ASC`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	genres := []*Genre{}
+
+	for rows.Next() {
+		var genre Genre
+
+		err := scanGenre(rows, &genre)
+		if err != nil {
+			return nil, err
+		}
+
+		genres = append(genres, &genre)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return genres, nil
+}
+
+func (m genreModel) Get(id int64) (*Genre, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := genreSelect + `
+	WHERE g.id = $1`
+
+	var genre Genre
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := scanGenre(m.DB.QueryRowContext(ctx, query, id), &genre)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &genre, nil
+}
+
+// Resolve maps genre names and aliases (case insensitive) to canonical genre names keeping the order.
+// Names that are not in the catalog are returned as unknown.
+func (m genreModel) Resolve(names []string) ([]string, []string, error) {
+	query := `
+	SELECT u.name, g.name::text
+	FROM unnest($1::text[]) AS u(name)
+	LEFT JOIN genre_aliases a ON a.alias = u.name::citext
+	INNER JOIN genres g ON g.name = u.name::citext OR g.id = a.genre_id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(names))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	canonicalNames := make(map[string]string, len(names))
+
+	for rows.Next() {
+		var name, canonical string
+
+		err := rows.Scan(&name, &canonical)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		canonicalNames[name] = canonical
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var resolved, unknown []string
+
+	for _, name := range names {
+		canonical, ok := canonicalNames[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		resolved = append(resolved, canonical)
+	}
+
+	return resolved, unknown, nil
+}
+
+// Insert creates a genre with its aliases.
+// Returns ErrDuplicateGenre if the name or any alias is already used by another genre.
+func (m genreModel) Insert(genre *Genre) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		err := checkGenreNamesFree(ctx, tx, 0, append([]string{genre.Name}, genre.Aliases...))
+		if err != nil {
+			return err
+		}
+
+		query := `
+		INSERT INTO genres (name)
+		VALUES ($1)
+		RETURNING id, created_at, version`
+
+		err = tx.QueryRowContext(ctx, query, genre.Name).Scan(&genre.ID, &genre.CreatedAt, &genre.Version)
+		if err != nil {
+			return err
+		}
+
+		return insertGenreAliases(ctx, tx, genre.ID, genre.Aliases)
+	})
+}
+
+// genreRevisionsSQL saves a revision for every movie returned by the "updated" CTE
+// when genres are rewritten by renaming or merging, $3 is the actor and $4 the action.
+// The CTE is aliased as movies for movieSnapshotSQL.
+const genreRevisionsSQL = `
+	INSERT INTO movie_revisions (movie_id, version, action, user_id, changed_fields, snapshot)
+	SELECT id, version, $4, $3, ARRAY['genres'], ` + movieSnapshotSQL + `
+	FROM updated AS movies`
+
+// Update renames the genre and replaces its aliases.
+// When the genre is renamed, the old name is replaced in all movies and series.
+func (m genreModel) Update(genre *Genre, actorID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		oldName, err := lockGenreName(ctx, tx, genre.ID)
+		if err != nil {
+			return err
+		}
+
+		err = checkGenreNamesFree(ctx, tx, genre.ID, append([]string{genre.Name}, genre.Aliases...))
+		if err != nil {
+			return err
+		}
+
+		query := `
+		UPDATE genres
+		SET name = $1, version = version + 1
+		WHERE id = $2 AND version = $3
+		RETURNING version`
+
+		err = tx.QueryRowContext(ctx, query, genre.Name, genre.ID, genre.Version).Scan(&genre.Version)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEditConflict
+			default:
+				return err
+			}
+		}
+
+		if genre.Name != oldName {
+			query = `
+			WITH updated AS (
+				UPDATE movies
+				SET genres = array_replace(genres, $1, $2), version = version + 1
+				WHERE genres @> ARRAY[$1]
+				RETURNING *
+			)
+			` + genreRevisionsSQL
+
+			_, err = tx.ExecContext(ctx, query, oldName, genre.Name, actorParam(actorID), RevisionUpdate)
+			if err != nil {
+				return err
+			}
+
+			query = `
+			UPDATE series
+			SET genres = array_replace(genres, $1, $2), version = version + 1
+			WHERE genres @> ARRAY[$1]`
+
+			_, err = tx.ExecContext(ctx, query, oldName, genre.Name)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM genre_aliases WHERE genre_id = $1", genre.ID)
+		if err != nil {
+			return err
+		}
+
+		return insertGenreAliases(ctx, tx, genre.ID, genre.Aliases)
+	})
+}
+
+// Delete removes a genre that is not used by any movie or series, otherwise returns ErrGenreInUse.
+// Movies in trash count as well, so they can still be restored.
+func (m genreModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+	DELETE FROM genres g
+	WHERE g.id = $1
+	AND NOT EXISTS (SELECT 1 FROM movies m WHERE m.genres @> ARRAY[g.name::text])
+	AND NOT EXISTS (SELECT 1 FROM series s WHERE s.genres @> ARRAY[g.name::text])`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		// checking which of the conditions failed
+		_, err = m.Get(id)
+		if err != nil {
+			return err
+		}
+
+		return ErrGenreInUse
+	}
+
+	return nil
+}
+
+// Merge folds the source genre into the target one.
+// Movies and series get the target genre instead of the source one, source name and aliases become target aliases
+// and the source genre is deleted.
+func (m genreModel) Merge(sourceID, targetID int64, actorID int64) (*Genre, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		// locking both genres, so they can't be renamed while merging
+		sourceName, err := lockGenreName(ctx, tx, sourceID)
+		if err != nil {
+			return err
+		}
+
+		targetName, err := lockGenreName(ctx, tx, targetID)
+		if err != nil {
+			return err
+		}
+
+		// movies already having target genre just lose the source one, so genres stay unique
+		query := `
+		WITH updated AS (
+			UPDATE movies
+			SET genres = CASE
+					WHEN genres @> ARRAY[$2] THEN array_remove(genres, $1)
+					ELSE array_replace(genres, $1, $2)
+				END,
+				version = version + 1
+			WHERE genres @> ARRAY[$1]
+			RETURNING *
+		)
+		` + genreRevisionsSQL
+
+		_, err = tx.ExecContext(ctx, query, sourceName, targetName, actorParam(actorID), RevisionUpdate)
+		if err != nil {
+			return err
+		}
+
+		query = `
+		UPDATE series
+		SET genres = CASE
+				WHEN genres @> ARRAY[$2] THEN array_remove(genres, $1)
+				ELSE array_replace(genres, $1, $2)
+			END,
+			version = version + 1
+		WHERE genres @> ARRAY[$1]`
+
+		_, err = tx.ExecContext(ctx, query, sourceName, targetName)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE genre_aliases SET genre_id = $1 WHERE genre_id = $2", targetID, sourceID)
+		if err != nil {
+			return err
+		}
+
+		// dismissals of users who haven't dismissed the target are moved, the rest is deleted with the source
+		query = `
+		UPDATE dismissed_genres SET genre_id = $1
+		WHERE genre_id = $2 AND user_id NOT IN (SELECT user_id FROM dismissed_genres WHERE genre_id = $1)`
+
+		_, err = tx.ExecContext(ctx, query, targetID, sourceID)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM genres WHERE id = $1", sourceID)
+		if err != nil {
+			return err
+		}
+
+		err = insertGenreAliases(ctx, tx, targetID, []string{sourceName})
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE genres SET version = version + 1 WHERE id = $1", targetID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Get(targetID)
+}
+
+// lockGenreName locks the genre row until the end of transaction and returns its current name.
+func lockGenreName(ctx context.Context, tx *sql.Tx, id int64) (string, error) {
+	var name string
+
+	err := tx.QueryRowContext(ctx, "SELECT name::text FROM genres WHERE id = $1 FOR UPDATE", id).Scan(&name)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return "", ErrRecordNotFound
+		default:
+			return "", err
+		}
+	}
+
+	return name, nil
+}
+
+// checkGenreNamesFree returns ErrDuplicateGenre if any of the names is used as a name or alias
+// by a genre other than genreID.
+func checkGenreNamesFree(ctx context.Context, tx *sql.Tx, genreID int64, names []string) error {
+	query := `
+	SELECT EXISTS (
+		SELECT 1 FROM genres WHERE name = ANY($1::citext[]) AND id <> $2
+		UNION ALL
+		SELECT 1 FROM genre_aliases WHERE alias = ANY($1::citext[]) AND genre_id <> $2
+	)`
+
+	var exists bool
+
+	err := tx.QueryRowContext(ctx, query, pq.Array(names), genreID).Scan(&exists)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		return ErrDuplicateGenre
+	}
+
+	return nil
+}
+
+// insertGenreAliases adds aliases to the genre.
+func insertGenreAliases(ctx context.Context, tx *sql.Tx, genreID int64, aliases []string) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	query := `
+	INSERT INTO genre_aliases (alias, genre_id)
+	SELECT alias, $2 FROM unnest($1::citext[]) AS alias`
+
+	_, err := tx.ExecContext(ctx, query, pq.Array(aliases), genreID)
+	return err
+}
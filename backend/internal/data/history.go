@@ -0,0 +1,231 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SeenProgress is the progress in percent from which a watch counts as seen, the rest is usually credits.
+const SeenProgress = 90
+
+// WatchEvent is a single viewing of a movie by a user.
+// Progress is the watched part in percent, nil means the movie was watched to the end.
+type WatchEvent struct {
+	ID        int64     `json:"id" example:"1"`
+	MovieID   int64     `json:"movie_id" example:"1"`
+	Title     string    `json:"title" example:"The Shawshank Redemption"`
+	Year      int32     `json:"year" example:"1994"`
+	WatchedAt time.Time `json:"watched_at" example:"2025-01-01T20:00:00Z"`
+	Progress  *int32    `json:"progress,omitempty" example:"45"`
+	Rewatch   bool      `json:"rewatch" example:"false"`
+}
+
+// Seen reports whether the event marks the movie as seen.
+func (e WatchEvent) Seen() bool {
+	return e.Progress == nil || *e.Progress >= SeenProgress
+}
+
+type historyModel struct {
+	DB *sql.DB
+}
+
+// GetAll returns the user's watch history, the latest events first.
+// History grows without bounds, so it is paged with cursors only and the total is never counted.
+func (m historyModel) GetAll(userID int64, filters Filters) ([]*WatchEvent, Metadata, error) {
+	args := []any{userID}
+	where := "user_id = $1"
+
+	if filters.Cursor != "" {
+		c, err := filters.decodeCursor()
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		args = append(args, c.Value, c.ID)
+		where += " AND " + filters.keysetCondition("watched_at", len(args)-1, len(args))
+	}
+
+	args = append(args, filters.limit()+1)
+
+	// events are paged first, so movies are only joined to a single page
+	query := fmt.Sprintf(`
+	SELECT e.id, e.movie_id, m.title, m.year, e.watched_at, e.progress, e.rewatch, e.watched_at::text
+	FROM (
+		SELECT id, movie_id, watched_at, progress, rewatch
+		FROM watch_events
+		WHERE %s
+		ORDER BY %s %s, id ASC
+		LIMIT $%d
+	) AS e
+	JOIN movies m ON m.id = e.movie_id
+	ORDER BY e.%s %s, e.id ASC`, where, filters.sortColumn(), filters.sortDirection(), len(args),
+		filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	events := []*WatchEvent{}
+	var sortValues []string
+
+	for rows.Next() {
+		var event WatchEvent
+		var sortValue string
+
+		err := rows.Scan(
+			&event.ID,
+			&event.MovieID,
+			&event.Title,
+			&event.Year,
+			&event.WatchedAt,
+			&event.Progress,
+			&event.Rewatch,
+			&sortValue,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		events = append(events, &event)
+		sortValues = append(sortValues, sortValue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := Metadata{PageSize: filters.PageSize}
+
+	if len(events) > filters.limit() {
+		events = events[:filters.limit()]
+		last := events[len(events)-1]
+
+		metadata.NextCursor = encodeCursor(cursor{
+			Sort:  filters.Sort,
+			Value: sortValues[len(events)-1],
+			ID:    last.ID,
+		})
+	}
+
+	return events, metadata, nil
+}
+
+// Insert records the watch event and marks the movie as seen when the event is Seen.
+// Returns ErrRecordNotFound if the movie doesn't exist or is in trash.
+func (m historyModel) Insert(userID int64, event *WatchEvent) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		query := `
+		WITH movie AS (
+			SELECT id, title, year FROM movies WHERE id = $2 AND deleted_at IS NULL
+		), inserted AS (
+			INSERT INTO watch_events (user_id, movie_id, watched_at, progress, rewatch)
+			SELECT $1, id, $3, $4, $5 FROM movie
+			RETURNING id
+		)
+		SELECT inserted.id, movie.title, movie.year
+		FROM movie, inserted`
+
+		args := []any{userID, event.MovieID, event.WatchedAt, event.Progress, event.Rewatch}
+
+		err := tx.QueryRowContext(ctx, query, args...).Scan(&event.ID, &event.Title, &event.Year)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrRecordNotFound
+			default:
+				return err
+			}
+		}
+
+		if !event.Seen() {
+			return nil
+		}
+
+		// seen movies are kept apart from the events, so flagging them doesn't depend on the history size
+		query = `
+		INSERT INTO seen_movies (user_id, movie_id, seen_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, movie_id) DO UPDATE
+		SET seen_at = LEAST(seen_movies.seen_at, EXCLUDED.seen_at)`
+
+		_, err = tx.ExecContext(ctx, query, userID, event.MovieID, event.WatchedAt)
+
+		return err
+	})
+}
+
+// Mark sets Seen of the movies for the user with a single query.
+func (m historyModel) Mark(userID int64, movies []*Movie) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.ID)
+	}
+
+	query := "SELECT movie_id FROM seen_movies WHERE user_id = $1 AND movie_id = ANY($2)"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	seen := make(map[int64]bool)
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		seen[id] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, movie := range movies {
+		movieSeen := seen[movie.ID]
+		movie.Seen = &movieSeen
+	}
+
+	return nil
+}
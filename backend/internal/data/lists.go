@@ -0,0 +1,386 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// List visibilities. Unlisted lists are readable by anyone with the link but are left out of search.
+const (
+	ListPrivate  = "private"
+	ListUnlisted = "unlisted"
+	ListPublic   = "public"
+)
+
+// ListVisibilities holds all list visibilities.
+var ListVisibilities = []string{ListPrivate, ListUnlisted, ListPublic}
+
+// List is an ordered list of movies curated by a user. Items are only filled in by Get and Copy.
+type List struct {
+	ID          int64       `json:"id" example:"1"`
+	UserID      int64       `json:"user_id" example:"1"`
+	UserName    string      `json:"user_name" example:"John Doe"`
+	Name        string      `json:"name" example:"Best 90s thrillers"`
+	Description string      `json:"description,omitempty" example:"Twists guaranteed"`
+	Visibility  string      `json:"visibility" example:"public"`
+	CopiedFrom  *int64      `json:"copied_from,omitempty" example:"3"`
+	ItemCount   int         `json:"item_count" example:"10"`
+	Items       []*ListItem `json:"items,omitempty"`
+	CreatedAt   time.Time   `json:"created_at" example:"2025-01-01T00:00:00Z"`
+	UpdatedAt   time.Time   `json:"updated_at" example:"2025-01-01T00:00:00Z"`
+	Version     int32       `json:"version" example:"1"`
+}
+
+// VisibleTo reports whether the user can read the list, owners can read their private lists.
+func (l *List) VisibleTo(userID int64) bool {
+	return l.Visibility != ListPrivate || l.UserID == userID
+}
+
+// ListItem is a movie on a list with the curator's note.
+type ListItem struct {
+	MovieID  int64  `json:"movie_id" example:"1"`
+	Title    string `json:"title" example:"Se7en"`
+	Year     int32  `json:"year" example:"1995"`
+	Position int    `json:"position" example:"1"`
+	Note     string `json:"note,omitempty" example:"the ending"`
+}
+
+type listModel struct {
+	DB *sql.DB
+}
+
+// listSelect selects lists with their owners and the number of their items.
+const listSelect = `
+	SELECT l.id, l.user_id, u.name, l.name, l.description, l.visibility, l.copied_from,
+		(SELECT count(*) FROM list_items li WHERE li.list_id = l.id),
+		l.created_at, l.updated_at, l.version
+	FROM lists l
+	JOIN users u ON u.id = l.user_id`
+
+// scanList scans a row selected with listSelect, dest are scanned before the list columns.
+func scanList(row interface{ Scan(...any) error }, list *List, dest ...any) error {
+	return row.Scan(append(dest,
+		&list.ID,
+		&list.UserID,
+		&list.UserName,
+		&list.Name,
+		&list.Description,
+		&list.Visibility,
+		&list.CopiedFrom,
+		&list.ItemCount,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+		&list.Version,
+	)...)
+}
+
+// Search returns a page of public lists, name is matched case insensitive as a substring.
+func (m listModel) Search(name string, filters Filters) ([]*List, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), l.id, l.user_id, u.name, l.name, l.description, l.visibility, l.copied_from,
+		(SELECT count(*) FROM list_items li WHERE li.list_id = l.id),
+		l.created_at, l.updated_at, l.version
+	FROM lists l
+	JOIN users u ON u.id = l.user_id
+	WHERE l.visibility = 'public' AND l.name ILIKE '%%' || $1 || '%%'
+	ORDER BY l.%s %s, l.id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	return m.getAll(query, name, filters)
+}
+
+// GetAllForUser returns a page of the user's lists of any visibility.
+func (m listModel) GetAllForUser(userID int64, filters Filters) ([]*List, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), l.id, l.user_id, u.name, l.name, l.description, l.visibility, l.copied_from,
+		(SELECT count(*) FROM list_items li WHERE li.list_id = l.id),
+		l.created_at, l.updated_at, l.version
+	FROM lists l
+	JOIN users u ON u.id = l.user_id
+	WHERE l.user_id = $1
+	ORDER BY l.%s %s, l.id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	return m.getAll(query, userID, filters)
+}
+
+// getAll runs a page query selecting the total count and listSelect columns, arg is its first parameter.
+func (m listModel) getAll(query string, arg any, filters Filters) ([]*List, Metadata, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, arg, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	lists := []*List{}
+
+	for rows.Next() {
+		var list List
+
+		err := scanList(rows, &list, &totalRecords)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		lists = append(lists, &list)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return lists, metadata, nil
+}
+
+// Get returns the list with its items in list order, movies in trash are left out.
+func (m listModel) Get(id int64) (*List, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	var list List
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := scanList(m.DB.QueryRowContext(ctx, listSelect+`
+	WHERE l.id = $1`, id), &list)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	query := `
+	SELECT li.movie_id, m.title, m.year, li.position, li.note
+	FROM list_items li
+	JOIN movies m ON m.id = li.movie_id
+	WHERE li.list_id = $1 AND m.deleted_at IS NULL
+	ORDER BY li.position`
+
+	rows, err := m.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	list.Items = []*ListItem{}
+
+	for rows.Next() {
+		var item ListItem
+
+		err := rows.Scan(&item.MovieID, &item.Title, &item.Year, &item.Position, &item.Note)
+		if err != nil {
+			return nil, err
+		}
+
+		list.Items = append(list.Items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &list, nil
+}
+
+// Insert creates an empty list of the user.
+func (m listModel) Insert(list *List) error {
+	query := `
+	INSERT INTO lists (user_id, name, description, visibility)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id, created_at, updated_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, list.UserID, list.Name, list.Description, list.Visibility).Scan(
+		&list.ID,
+		&list.CreatedAt,
+		&list.UpdatedAt,
+		&list.Version,
+	)
+}
+
+// Update changes list name, description and visibility.
+func (m listModel) Update(list *List) error {
+	query := `
+	UPDATE lists
+	SET name = $1, description = $2, visibility = $3, updated_at = NOW(), version = version + 1
+	WHERE id = $4 AND version = $5
+	RETURNING updated_at, version`
+
+	args := []any{list.Name, list.Description, list.Visibility, list.ID, list.Version}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&list.UpdatedAt, &list.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the list with its items, copies of the list are kept.
+func (m listModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, "DELETE FROM lists WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// SetItems replaces items of the list, positions follow the order of the items.
+// Returns ErrUnknownMovie if one of the movies doesn't exist.
+func (m listModel) SetItems(list *List, items []*ListItem) error {
+	movieIDs := make([]int64, 0, len(items))
+	notes := make([]string, 0, len(items))
+
+	for _, item := range items {
+		movieIDs = append(movieIDs, item.MovieID)
+		notes = append(notes, item.Note)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		query := `
+		UPDATE lists SET updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND version = $2
+		RETURNING updated_at, version`
+
+		err := tx.QueryRowContext(ctx, query, list.ID, list.Version).Scan(&list.UpdatedAt, &list.Version)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrEditConflict
+			default:
+				return err
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM list_items WHERE list_id = $1", list.ID)
+		if err != nil {
+			return err
+		}
+
+		query = `
+		INSERT INTO list_items (list_id, movie_id, note, position)
+		SELECT $1, movie_id, note, position
+		FROM unnest($2::bigint[], $3::text[]) WITH ORDINALITY AS items (movie_id, note, position)`
+
+		_, err = tx.ExecContext(ctx, query, list.ID, pq.Array(movieIDs), pq.Array(notes))
+		if err != nil {
+			var pqErr *pq.Error
+
+			switch {
+			case errors.As(err, &pqErr) && pqErr.Code == "23503": // foreign_key_violation
+				return ErrUnknownMovie
+			default:
+				return err
+			}
+		}
+
+		list.ItemCount = len(items)
+
+		return nil
+	})
+}
+
+// Copy creates a private copy of the list with its items for the user and returns it.
+func (m listModel) Copy(source *List, userID int64) (*List, error) {
+	list := &List{
+		UserID:      userID,
+		Name:        source.Name,
+		Description: source.Description,
+		Visibility:  ListPrivate,
+		CopiedFrom:  &source.ID,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		query := `
+		INSERT INTO lists (user_id, name, description, visibility, copied_from)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at, version`
+
+		args := []any{list.UserID, list.Name, list.Description, list.Visibility, source.ID}
+
+		err := tx.QueryRowContext(ctx, query, args...).Scan(&list.ID, &list.CreatedAt, &list.UpdatedAt, &list.Version)
+		if err != nil {
+			return err
+		}
+
+		query = `
+		INSERT INTO list_items (list_id, movie_id, position, note)
+		SELECT $1, movie_id, position, note
+		FROM list_items
+		WHERE list_id = $2`
+
+		_, err = tx.ExecContext(ctx, query, list.ID, source.ID)
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Get(list.ID)
+}
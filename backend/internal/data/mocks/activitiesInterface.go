@@ -0,0 +1,119 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// activitiesInterface is an autogenerated mock type for the activitiesInterface type
+type ActivitiesInterface struct {
+	mock.Mock
+}
+
+// GetFeed provides a mock function with given fields: _a0, _a1
+func (_m *ActivitiesInterface) GetFeed(_a0 int64, _a1 data.Filters) ([]*data.Activity, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFeed")
+	}
+
+	var r0 []*data.Activity
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) ([]*data.Activity, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) []*data.Activity); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Activity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetForUser provides a mock function with given fields: _a0, _a1
+func (_m *ActivitiesInterface) GetForUser(_a0 int64, _a1 data.Filters) ([]*data.Activity, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForUser")
+	}
+
+	var r0 []*data.Activity
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) ([]*data.Activity, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) []*data.Activity); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Activity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Insert provides a mock function with given fields: _a0
+func (_m *ActivitiesInterface) Insert(_a0 *data.Activity) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.Activity) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// newActivitiesInterface creates a new instance of activitiesInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewActivitiesInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ActivitiesInterface {
+	mock := &ActivitiesInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,166 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// collectionsInterface is an autogenerated mock type for the collectionsInterface type
+type CollectionsInterface struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: _a0
+func (_m *CollectionsInterface) Delete(_a0 int64) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: _a0
+func (_m *CollectionsInterface) Get(_a0 int64) (*data.Collection, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *data.Collection
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*data.Collection, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *data.Collection); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Collection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: _a0, _a1
+func (_m *CollectionsInterface) GetAll(_a0 string, _a1 data.Filters) ([]*data.Collection, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []*data.Collection
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, data.Filters) ([]*data.Collection, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, data.Filters) []*data.Collection); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Collection)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Insert provides a mock function with given fields: _a0
+func (_m *CollectionsInterface) Insert(_a0 *data.Collection) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.Collection) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetMovies provides a mock function with given fields: _a0, _a1
+func (_m *CollectionsInterface) SetMovies(_a0 *data.Collection, _a1 []int64) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMovies")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.Collection, []int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: _a0
+func (_m *CollectionsInterface) Update(_a0 *data.Collection) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.Collection) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// newCollectionsInterface creates a new instance of collectionsInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCollectionsInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CollectionsInterface {
+	mock := &CollectionsInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
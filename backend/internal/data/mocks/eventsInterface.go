@@ -0,0 +1,55 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// eventsInterface is an autogenerated mock type for the eventsInterface type
+type EventsInterface struct {
+	mock.Mock
+}
+
+// InsertBatch provides a mock function with given fields: _a0
+func (_m *EventsInterface) InsertBatch(_a0 []*data.Event) (int64, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertBatch")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]*data.Event) (int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]*data.Event) int64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func([]*data.Event) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// newEventsInterface creates a new instance of eventsInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEventsInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EventsInterface {
+	mock := &EventsInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,175 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// followsInterface is an autogenerated mock type for the followsInterface type
+type FollowsInterface struct {
+	mock.Mock
+}
+
+// Follow provides a mock function with given fields: _a0, _a1
+func (_m *FollowsInterface) Follow(_a0 int64, _a1 int64) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Follow")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFollowers provides a mock function with given fields: _a0, _a1
+func (_m *FollowsInterface) GetFollowers(_a0 int64, _a1 data.Filters) ([]*data.Follow, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowers")
+	}
+
+	var r0 []*data.Follow
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) ([]*data.Follow, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) []*data.Follow); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetFollowing provides a mock function with given fields: _a0, _a1
+func (_m *FollowsInterface) GetFollowing(_a0 int64, _a1 data.Filters) ([]*data.Follow, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetFollowing")
+	}
+
+	var r0 []*data.Follow
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) ([]*data.Follow, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) []*data.Follow); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Follow)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// IsFollowing provides a mock function with given fields: _a0, _a1
+func (_m *FollowsInterface) IsFollowing(_a0 int64, _a1 int64) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsFollowing")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (bool, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Unfollow provides a mock function with given fields: _a0, _a1
+func (_m *FollowsInterface) Unfollow(_a0 int64, _a1 int64) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unfollow")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// newFollowsInterface creates a new instance of followsInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewFollowsInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *FollowsInterface {
+	mock := &FollowsInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
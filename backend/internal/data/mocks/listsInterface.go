@@ -0,0 +1,233 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// listsInterface is an autogenerated mock type for the listsInterface type
+type ListsInterface struct {
+	mock.Mock
+}
+
+// Copy provides a mock function with given fields: _a0, _a1
+func (_m *ListsInterface) Copy(_a0 *data.List, _a1 int64) (*data.List, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Copy")
+	}
+
+	var r0 *data.List
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*data.List, int64) (*data.List, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(*data.List, int64) *data.List); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.List)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*data.List, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Delete provides a mock function with given fields: _a0
+func (_m *ListsInterface) Delete(_a0 int64) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: _a0
+func (_m *ListsInterface) Get(_a0 int64) (*data.List, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *data.List
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*data.List, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *data.List); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.List)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAllForUser provides a mock function with given fields: _a0, _a1
+func (_m *ListsInterface) GetAllForUser(_a0 int64, _a1 data.Filters) ([]*data.List, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllForUser")
+	}
+
+	var r0 []*data.List
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) ([]*data.List, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) []*data.List); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.List)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Insert provides a mock function with given fields: _a0
+func (_m *ListsInterface) Insert(_a0 *data.List) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.List) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: _a0, _a1
+func (_m *ListsInterface) Search(_a0 string, _a1 data.Filters) ([]*data.List, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*data.List
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, data.Filters) ([]*data.List, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, data.Filters) []*data.List); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.List)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SetItems provides a mock function with given fields: _a0, _a1
+func (_m *ListsInterface) SetItems(_a0 *data.List, _a1 []*data.ListItem) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetItems")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.List, []*data.ListItem) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Update provides a mock function with given fields: _a0
+func (_m *ListsInterface) Update(_a0 *data.List) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.List) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// newListsInterface creates a new instance of listsInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewListsInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ListsInterface {
+	mock := &ListsInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	mock "github.com/stretchr/testify/mock"
 	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+
+	time "time"
 )
 
 // moviesInterface is an autogenerated mock type for the moviesInterface type
@@ -12,17 +14,35 @@ type MoviesInterface struct {
 	mock.Mock
 }
 
-// Delete provides a mock function with given fields: _a0
-func (_m *MoviesInterface) Delete(_a0 int64) error {
-	ret := _m.Called(_a0)
+// Batch provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MoviesInterface) Batch(_a0 []*data.MovieOperation, _a1 bool, _a2 int64) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Batch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func([]*data.MovieOperation, bool, int64) error); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Delete provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MoviesInterface) Delete(_a0 int64, _a1 int32, _a2 int64) error {
+	ret := _m.Called(_a0, _a1, _a2)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Delete")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(int64) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(int64, int32, int64) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -30,6 +50,66 @@ func (_m *MoviesInterface) Delete(_a0 int64) error {
 	return r0
 }
 
+// Facets provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) Facets(_a0 data.MovieFilter, _a1 []string) (data.Facets, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Facets")
+	}
+
+	var r0 data.Facets
+	var r1 error
+	if rf, ok := ret.Get(0).(func(data.MovieFilter, []string) (data.Facets, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(data.MovieFilter, []string) data.Facets); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(data.Facets)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(data.MovieFilter, []string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindDuplicate provides a mock function with given fields: _a0
+func (_m *MoviesInterface) FindDuplicate(_a0 *data.Movie) (*data.Movie, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDuplicate")
+	}
+
+	var r0 *data.Movie
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*data.Movie) (*data.Movie, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(*data.Movie) *data.Movie); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Movie)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(*data.Movie) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Get provides a mock function with given fields: _a0
 func (_m *MoviesInterface) Get(_a0 int64) (*data.Movie, error) {
 	ret := _m.Called(_a0)
@@ -60,9 +140,9 @@ func (_m *MoviesInterface) Get(_a0 int64) (*data.Movie, error) {
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields: _a0, _a1, _a2
-func (_m *MoviesInterface) GetAll(_a0 string, _a1 []string, _a2 data.Filters) ([]*data.Movie, data.Metadata, error) {
-	ret := _m.Called(_a0, _a1, _a2)
+// GetAll provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) GetAll(_a0 data.MovieFilter, _a1 data.Filters) ([]*data.Movie, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetAll")
@@ -71,25 +151,122 @@ func (_m *MoviesInterface) GetAll(_a0 string, _a1 []string, _a2 data.Filters) ([
 	var r0 []*data.Movie
 	var r1 data.Metadata
 	var r2 error
-	if rf, ok := ret.Get(0).(func(string, []string, data.Filters) ([]*data.Movie, data.Metadata, error)); ok {
-		return rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(data.MovieFilter, data.Filters) ([]*data.Movie, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
 	}
-	if rf, ok := ret.Get(0).(func(string, []string, data.Filters) []*data.Movie); ok {
-		r0 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(0).(func(data.MovieFilter, data.Filters) []*data.Movie); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]*data.Movie)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string, []string, data.Filters) data.Metadata); ok {
-		r1 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(1).(func(data.MovieFilter, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(data.MovieFilter, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAllByTMDBID provides a mock function with given fields: _a0
+func (_m *MoviesInterface) GetAllByTMDBID(_a0 []int64) ([]*data.Movie, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllByTMDBID")
+	}
+
+	var r0 []*data.Movie
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int64) ([]*data.Movie, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]int64) []*data.Movie); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Movie)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByExternalID provides a mock function with given fields: _a0
+func (_m *MoviesInterface) GetByExternalID(_a0 data.ExternalIDs) (*data.Movie, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByExternalID")
+	}
+
+	var r0 *data.Movie
+	var r1 error
+	if rf, ok := ret.Get(0).(func(data.ExternalIDs) (*data.Movie, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(data.ExternalIDs) *data.Movie); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Movie)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(data.ExternalIDs) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDeleted provides a mock function with given fields: _a0
+func (_m *MoviesInterface) GetDeleted(_a0 data.Filters) ([]*data.Movie, data.Metadata, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDeleted")
+	}
+
+	var r0 []*data.Movie
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(data.Filters) ([]*data.Movie, data.Metadata, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(data.Filters) []*data.Movie); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Movie)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(data.Filters) data.Metadata); ok {
+		r1 = rf(_a0)
 	} else {
 		r1 = ret.Get(1).(data.Metadata)
 	}
 
-	if rf, ok := ret.Get(2).(func(string, []string, data.Filters) error); ok {
-		r2 = rf(_a0, _a1, _a2)
+	if rf, ok := ret.Get(2).(func(data.Filters) error); ok {
+		r2 = rf(_a0)
 	} else {
 		r2 = ret.Error(2)
 	}
@@ -97,17 +274,218 @@ func (_m *MoviesInterface) GetAll(_a0 string, _a1 []string, _a2 data.Filters) ([
 	return r0, r1, r2
 }
 
-// Insert provides a mock function with given fields: _a0
-func (_m *MoviesInterface) Insert(_a0 *data.Movie) error {
+// GetRedirect provides a mock function with given fields: _a0
+func (_m *MoviesInterface) GetRedirect(_a0 int64) (int64, error) {
 	ret := _m.Called(_a0)
 
+	if len(ret) == 0 {
+		panic("no return value specified for GetRedirect")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int64) int64); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetRevision provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) GetRevision(_a0 int64, _a1 int32) (*data.MovieRevision, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetRevision")
+	}
+
+	var r0 *data.MovieRevision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int32) (*data.MovieRevision, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int32) *data.MovieRevision); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.MovieRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int32) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// History provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) History(_a0 int64, _a1 data.Filters) ([]*data.MovieRevision, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 []*data.MovieRevision
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) ([]*data.MovieRevision, data.Metadata, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, data.Filters) []*data.MovieRevision); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.MovieRevision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, data.Filters) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Insert provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) Insert(_a0 *data.Movie, _a1 int64) error {
+	ret := _m.Called(_a0, _a1)
+
 	if len(ret) == 0 {
 		panic("no return value specified for Insert")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*data.Movie) error); ok {
+	if rf, ok := ret.Get(0).(func(*data.Movie, int64) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Merge provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MoviesInterface) Merge(_a0 int64, _a1 int64, _a2 int64) (*data.Movie, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Merge")
+	}
+
+	var r0 *data.Movie
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64, int64) (*data.Movie, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, int64) *data.Movie); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Movie)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, int64) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeDeleted provides a mock function with given fields: _a0
+func (_m *MoviesInterface) PurgeDeleted(_a0 time.Time) (int64, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeleted")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
 		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) Restore(_a0 int64, _a1 int64) (*data.Movie, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Restore")
+	}
+
+	var r0 *data.Movie
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (*data.Movie, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) *data.Movie); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Movie)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetExternalIDs provides a mock function with given fields: _a0, _a1, _a2
+func (_m *MoviesInterface) SetExternalIDs(_a0 *data.Movie, _a1 data.ExternalIDs, _a2 int64) error {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetExternalIDs")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.Movie, data.ExternalIDs, int64) error); ok {
+		r0 = rf(_a0, _a1, _a2)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -115,17 +493,77 @@ func (_m *MoviesInterface) Insert(_a0 *data.Movie) error {
 	return r0
 }
 
-// Update provides a mock function with given fields: _a0
-func (_m *MoviesInterface) Update(_a0 *data.Movie) error {
+// Suggest provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) Suggest(_a0 string, _a1 int) ([]*data.MovieSuggestion, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Suggest")
+	}
+
+	var r0 []*data.MovieSuggestion
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, int) ([]*data.MovieSuggestion, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(string, int) []*data.MovieSuggestion); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.MovieSuggestion)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, int) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// TMDBIDs provides a mock function with given fields: _a0
+func (_m *MoviesInterface) TMDBIDs(_a0 []int64) ([]int64, error) {
 	ret := _m.Called(_a0)
 
+	if len(ret) == 0 {
+		panic("no return value specified for TMDBIDs")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int64) ([]int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]int64) []int64); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: _a0, _a1
+func (_m *MoviesInterface) Update(_a0 *data.Movie, _a1 int64) error {
+	ret := _m.Called(_a0, _a1)
+
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(*data.Movie) error); ok {
-		r0 = rf(_a0)
+	if rf, ok := ret.Get(0).(func(*data.Movie, int64) error); ok {
+		r0 = rf(_a0, _a1)
 	} else {
 		r0 = ret.Error(0)
 	}
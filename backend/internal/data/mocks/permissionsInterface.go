@@ -0,0 +1,82 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// permissionsInterface is an autogenerated mock type for the permissionsInterface type
+type PermissionsInterface struct {
+	mock.Mock
+}
+
+// AddForUser provides a mock function with given fields: _a0, _a1
+func (_m *PermissionsInterface) AddForUser(_a0 int64, _a1 ...string) error {
+	_va := make([]interface{}, len(_a1))
+	for _i := range _a1 {
+		_va[_i] = _a1[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, _a0)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, ...string) error); ok {
+		r0 = rf(_a0, _a1...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAllForUser provides a mock function with given fields: _a0
+func (_m *PermissionsInterface) GetAllForUser(_a0 int64) (data.Permissions, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllForUser")
+	}
+
+	var r0 data.Permissions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (data.Permissions, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int64) data.Permissions); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(data.Permissions)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// newPermissionsInterface creates a new instance of permissionsInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPermissionsInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PermissionsInterface {
+	mock := &PermissionsInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
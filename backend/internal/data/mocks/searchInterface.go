@@ -0,0 +1,64 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// searchInterface is an autogenerated mock type for the searchInterface type
+type SearchInterface struct {
+	mock.Mock
+}
+
+// Search provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SearchInterface) Search(_a0 string, _a1 []string, _a2 data.Filters) ([]*data.SearchResult, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Search")
+	}
+
+	var r0 []*data.SearchResult
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, []string, data.Filters) ([]*data.SearchResult, data.Metadata, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string, data.Filters) []*data.SearchResult); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, []string, data.Filters) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// newSearchInterface creates a new instance of searchInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSearchInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SearchInterface {
+	mock := &SearchInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
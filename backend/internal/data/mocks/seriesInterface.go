@@ -0,0 +1,274 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+	data "github.com/vladgrskkh/movie_recomendation_system/internal/data"
+)
+
+// seriesInterface is an autogenerated mock type for the seriesInterface type
+type SeriesInterface struct {
+	mock.Mock
+}
+
+// Delete provides a mock function with given fields: _a0
+func (_m *SeriesInterface) Delete(_a0 int64) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSeason provides a mock function with given fields: _a0, _a1
+func (_m *SeriesInterface) DeleteSeason(_a0 int64, _a1 int32) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteSeason")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, int32) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: _a0
+func (_m *SeriesInterface) Get(_a0 int64) (*data.Series, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *data.Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*data.Series, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *data.Series); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAll provides a mock function with given fields: _a0, _a1, _a2
+func (_m *SeriesInterface) GetAll(_a0 string, _a1 []string, _a2 data.Filters) ([]*data.Series, data.Metadata, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAll")
+	}
+
+	var r0 []*data.Series
+	var r1 data.Metadata
+	var r2 error
+	if rf, ok := ret.Get(0).(func(string, []string, data.Filters) ([]*data.Series, data.Metadata, error)); ok {
+		return rf(_a0, _a1, _a2)
+	}
+	if rf, ok := ret.Get(0).(func(string, []string, data.Filters) []*data.Series); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []string, data.Filters) data.Metadata); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Get(1).(data.Metadata)
+	}
+
+	if rf, ok := ret.Get(2).(func(string, []string, data.Filters) error); ok {
+		r2 = rf(_a0, _a1, _a2)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAllByTMDBID provides a mock function with given fields: _a0
+func (_m *SeriesInterface) GetAllByTMDBID(_a0 []int64) ([]*data.Series, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllByTMDBID")
+	}
+
+	var r0 []*data.Series
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int64) ([]*data.Series, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]int64) []*data.Series); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*data.Series)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSeason provides a mock function with given fields: _a0, _a1
+func (_m *SeriesInterface) GetSeason(_a0 int64, _a1 int32) (*data.Season, error) {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetSeason")
+	}
+
+	var r0 *data.Season
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int32) (*data.Season, error)); ok {
+		return rf(_a0, _a1)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int32) *data.Season); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Season)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int32) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Insert provides a mock function with given fields: _a0
+func (_m *SeriesInterface) Insert(_a0 *data.Series) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Insert")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.Series) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PutSeason provides a mock function with given fields: _a0, _a1
+func (_m *SeriesInterface) PutSeason(_a0 int64, _a1 *data.Season) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PutSeason")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, *data.Season) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// TMDBIDs provides a mock function with given fields: _a0
+func (_m *SeriesInterface) TMDBIDs(_a0 []int64) ([]int64, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TMDBIDs")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]int64) ([]int64, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func([]int64) []int64); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func([]int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: _a0
+func (_m *SeriesInterface) Update(_a0 *data.Series) error {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*data.Series) error); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// newSeriesInterface creates a new instance of seriesInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSeriesInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SeriesInterface {
+	mock := &SeriesInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
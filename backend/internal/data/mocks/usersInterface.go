@@ -8,12 +8,12 @@ import (
 )
 
 // usersInterface is an autogenerated mock type for the usersInterface type
-type usersInterface struct {
+type UsersInterface struct {
 	mock.Mock
 }
 
 // GetByEmail provides a mock function with given fields: _a0
-func (_m *usersInterface) GetByEmail(_a0 string) (*data.User, error) {
+func (_m *UsersInterface) GetByEmail(_a0 string) (*data.User, error) {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
@@ -43,7 +43,7 @@ func (_m *usersInterface) GetByEmail(_a0 string) (*data.User, error) {
 }
 
 // GetByID provides a mock function with given fields: _a0
-func (_m *usersInterface) GetByID(_a0 int64) (*data.User, error) {
+func (_m *UsersInterface) GetByID(_a0 int64) (*data.User, error) {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
@@ -73,7 +73,7 @@ func (_m *usersInterface) GetByID(_a0 int64) (*data.User, error) {
 }
 
 // GetForToken provides a mock function with given fields: _a0, _a1
-func (_m *usersInterface) GetForToken(_a0 string, _a1 string) (*data.User, error) {
+func (_m *UsersInterface) GetForToken(_a0 string, _a1 string) (*data.User, error) {
 	ret := _m.Called(_a0, _a1)
 
 	if len(ret) == 0 {
@@ -102,8 +102,38 @@ func (_m *usersInterface) GetForToken(_a0 string, _a1 string) (*data.User, error
 	return r0, r1
 }
 
+// GetPrivacy provides a mock function with given fields: _a0
+func (_m *UsersInterface) GetPrivacy(_a0 int64) (*data.Privacy, error) {
+	ret := _m.Called(_a0)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPrivacy")
+	}
+
+	var r0 *data.Privacy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (*data.Privacy, error)); ok {
+		return rf(_a0)
+	}
+	if rf, ok := ret.Get(0).(func(int64) *data.Privacy); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*data.Privacy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Insert provides a mock function with given fields: _a0
-func (_m *usersInterface) Insert(_a0 *data.User) error {
+func (_m *UsersInterface) Insert(_a0 *data.User) error {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
@@ -121,7 +151,7 @@ func (_m *usersInterface) Insert(_a0 *data.User) error {
 }
 
 // Update provides a mock function with given fields: _a0
-func (_m *usersInterface) Update(_a0 *data.User) error {
+func (_m *UsersInterface) Update(_a0 *data.User) error {
 	ret := _m.Called(_a0)
 
 	if len(ret) == 0 {
@@ -138,13 +168,31 @@ func (_m *usersInterface) Update(_a0 *data.User) error {
 	return r0
 }
 
+// UpdatePrivacy provides a mock function with given fields: _a0, _a1
+func (_m *UsersInterface) UpdatePrivacy(_a0 int64, _a1 *data.Privacy) error {
+	ret := _m.Called(_a0, _a1)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePrivacy")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, *data.Privacy) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // newUsersInterface creates a new instance of usersInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUsersInterface(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *usersInterface {
-	mock := &usersInterface{}
+}) *UsersInterface {
+	mock := &UsersInterface{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -5,21 +5,179 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/lib/pq"
 )
 
 type Movie struct {
-	ID        int64     `json:"id" example:"1"`
-	CreatedAt time.Time `json:"-"`
-	Title     string    `json:"title" example:"The Shawshank Redemption"`
-	Year      int32     `json:"year" example:"1994"`
-	Runtime   int32     `json:"runtime,omitempty" example:"142"`
-	Genres    []string  `json:"genres" example:"Drama,Crime"`
-	Version   int32     `json:"version" example:"1"`
+	ID        int64      `json:"id" example:"1"`
+	CreatedAt time.Time  `json:"-"`
+	Title     string     `json:"title" example:"The Shawshank Redemption"`
+	Year      int32      `json:"year" example:"1994"`
+	Runtime   int32      `json:"runtime,omitempty" example:"142"`
+	Genres    []string   `json:"genres" example:"Drama,Crime"`
+	Version   int32      `json:"version" example:"1"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" example:"2025-01-01T00:00:00Z"`
+	// set only when the movie is localized, Title then holds the translated title
+	OriginalTitle string           `json:"original_title,omitempty" example:"The Shawshank Redemption"`
+	Overview      string           `json:"overview,omitempty" example:"Two imprisoned men bond over a number of years..."`
+	Language      string           `json:"language,omitempty" example:"en"`
+	ExternalIDs   *ExternalIDs     `json:"external_ids,omitempty"`
+	Collection    *MovieCollection `json:"collection,omitempty"`
+	// average user score from 1 to 10, zero while the movie has no ratings
+	Rating      float64 `json:"rating" example:"8.7"`
+	RatingCount int32   `json:"rating_count" example:"2914"`
+	// set only for responses to an authenticated user
+	InWatchlist *bool `json:"in_watchlist,omitempty" example:"true"`
+	Seen        *bool `json:"seen,omitempty" example:"false"`
 }
 
+// MovieSuggestion is a lightweight movie representation used for typeahead.
+type MovieSuggestion struct {
+	ID    int64  `json:"id" example:"1"`
+	Title string `json:"title" example:"The Shawshank Redemption"`
+	Year  int32  `json:"year" example:"1994"`
+}
+
+const (
+	GenresMatchAll = "all"
+	GenresMatchAny = "any"
+)
+
+// MovieFilter holds the conditions movies are filtered by in GetAll.
+// Zero values mean that the condition is not applied.
+type MovieFilter struct {
+	Title         string
+	Genres        []string
+	GenresMatch   string
+	ExcludeGenres []string
+	YearMin       int
+	YearMax       int
+	RuntimeMin    int
+	RuntimeMax    int
+	RatingMin     float64
+	RatingMax     float64
+	CreatedAfter  time.Time
+	CollectionID  int64
+}
+
+// where builds the SQL WHERE condition for the filter and returns it with its arguments.
+// The title is always the first argument ($1), so it can be referenced in ORDER BY expressions.
+func (f MovieFilter) where() (string, []any) {
+	args := []any{f.Title}
+
+	conditions := []string{"deleted_at IS NULL"}
+
+	if f.Title != "" {
+		conditions = append(conditions,
+			"(to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 <% title OR id IN ("+translatedTitleMatch+"))")
+	} else {
+		// $1 has to be referenced even when title is empty, otherwise its type can't be determined
+		conditions = append(conditions, "$1::text = ''")
+	}
+
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if len(f.Genres) > 0 {
+		if f.GenresMatch == GenresMatchAny {
+			add("genres && $%d", pq.Array(f.Genres))
+		} else {
+			add("genres @> $%d", pq.Array(f.Genres))
+		}
+	}
+
+	if len(f.ExcludeGenres) > 0 {
+		add("NOT genres && $%d", pq.Array(f.ExcludeGenres))
+	}
+
+	if f.YearMin != 0 {
+		add("year >= $%d", f.YearMin)
+	}
+
+	if f.YearMax != 0 {
+		add("year <= $%d", f.YearMax)
+	}
+
+	if f.RuntimeMin != 0 {
+		add("runtime >= $%d", f.RuntimeMin)
+	}
+
+	if f.RuntimeMax != 0 {
+		add("runtime <= $%d", f.RuntimeMax)
+	}
+
+	// unrated movies have rating 0, they are left out as soon as a rating bound is set
+	if f.RatingMin != 0 || f.RatingMax != 0 {
+		conditions = append(conditions, "rating_count > 0")
+	}
+
+	if f.RatingMin != 0 {
+		add("rating >= $%d", f.RatingMin)
+	}
+
+	if f.RatingMax != 0 {
+		add("rating <= $%d", f.RatingMax)
+	}
+
+	if !f.CreatedAfter.IsZero() {
+		add("created_at > $%d", f.CreatedAfter)
+	}
+
+	if f.CollectionID != 0 {
+		add("id IN (SELECT movie_id FROM collection_movies WHERE collection_id = $%d)", f.CollectionID)
+	}
+
+	return strings.Join(conditions, "\n\tAND "), args
+}
+
+const (
+	FacetGenres = "genres"
+	FacetDecade = "decade"
+)
+
+// FacetBucket is a single facet value with the number of movies having it.
+type FacetBucket struct {
+	Value string `json:"value" example:"Drama"`
+	Count int    `json:"count" example:"42"`
+}
+
+// Facets maps facet name to its buckets.
+type Facets map[string][]FacetBucket
+
+// facetQueries holds the SQL queries for each facet, %s is replaced with the filter condition.
+var facetQueries = map[string]string{
+	FacetGenres: `
+	SELECT genre, count(*)
+	FROM movies, unnest(genres) AS genre
+	WHERE %s
+	GROUP BY genre
+	ORDER BY count(*) DESC, genre ASC`,
+	FacetDecade: `
+	SELECT (year / 10 * 10)::text, count(*)
+	FROM movies
+	WHERE %s
+	GROUP BY year / 10
+	ORDER BY year / 10 ASC`,
+}
+
+// translatedTitleMatch selects ids of movies with a translated title matching the search query in $1,
+// each translation is searched with the text search configuration of its language.
+const translatedTitleMatch = "SELECT movie_id FROM movie_translations " +
+	"WHERE to_tsvector(search_config, title) @@ plainto_tsquery(search_config, $1) OR $1 <% title"
+
+// movieRelevance is an SQL expression ranking how well the title matches the search query in $1.
+// Full-text rank rewards whole word matches, word similarity rewards typos and partial words.
+// The best matching of the original and translated titles is used.
+const movieRelevance = "GREATEST(" +
+	"ts_rank(to_tsvector('simple', title), plainto_tsquery('simple', $1)) + word_similarity($1, title), " +
+	"(SELECT max(ts_rank(to_tsvector(t.search_config, t.title), plainto_tsquery(t.search_config, $1)) + word_similarity($1, t.title)) " +
+	"FROM movie_translations t WHERE t.movie_id = movies.id))::float8"
+
 type movieModel struct {
 	DB *sql.DB
 }
@@ -30,9 +188,9 @@ func (m movieModel) Get(id int64) (*Movie, error) {
 	}
 
 	query := `
-		SELECT id, created_at, title, year, runtime, genres, version
+		SELECT id, created_at, title, year, runtime, genres, version, rating, rating_count, ` + externalIDsSQL + `, ` + movieCollectionSQL + `
 		FROM movies
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var movie Movie
@@ -48,6 +206,10 @@ func (m movieModel) Get(id int64) (*Movie, error) {
 		&movie.Runtime,
 		pq.Array(&movie.Genres),
 		&movie.Version,
+		&movie.Rating,
+		&movie.RatingCount,
+		&movie.ExternalIDs,
+		&movie.Collection,
 	)
 	if err != nil {
 		switch {
@@ -61,16 +223,132 @@ func (m movieModel) Get(id int64) (*Movie, error) {
 	return &movie, nil
 }
 
-func (m movieModel) Insert(movie *Movie) error {
+// Insert adds the movie and saves its first revision made by the actor.
+func (m movieModel) Insert(movie *Movie, actorID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		return insertMovie(ctx, tx, movie, actorID)
+	})
+}
+
+// Delete moves the movie to trash, it is purged permanently after the retention period by PurgeDeleted.
+// When version is not zero the movie is deleted only if it still has this version, otherwise ErrEditConflict is returned.
+func (m movieModel) Delete(id int64, version int32, actorID int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		return deleteMovie(ctx, tx, id, version, actorID)
+	})
+}
+
+// Update saves movie changes and a revision listing the changed fields.
+// Returns ErrEditConflict if the movie version has changed since it was read.
+func (m movieModel) Update(movie *Movie, actorID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		return updateMovie(ctx, tx, movie, actorID)
+	})
+}
+
+const (
+	MovieOpCreate = "create"
+	MovieOpUpdate = "update"
+	MovieOpDelete = "delete"
+)
+
+// ErrBatchRolledBack is returned by Batch in atomic mode when an operation failed and nothing was saved.
+var ErrBatchRolledBack = errors.New("batch rolled back because an operation failed")
+
+// MovieOperation is a single create, update or delete operation of a movie batch.
+type MovieOperation struct {
+	Action string
+	// Movie is the created or updated movie, ID and Version of the updated movie are used for optimistic locking.
+	Movie *Movie
+	// ID and Version identify the deleted movie, zero version deletes any version.
+	ID      int64
+	Version int32
+	// Err is set to ErrRecordNotFound or ErrEditConflict when the operation failed.
+	Err error
+}
+
+// Batch applies the operations in a single transaction.
+// In atomic mode the first failed operation rolls back the batch and ErrBatchRolledBack is returned,
+// otherwise every operation runs in its own savepoint, so failed operations are skipped and the rest is saved.
+// Failed operations have their Err set, any other error aborts the whole batch.
+func (m movieModel) Batch(ops []*MovieOperation, atomic bool, actorID int64) error {
+	// a batch may contain many operations, so it gets more time than a single query
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		for _, op := range ops {
+			if !atomic {
+				_, err := tx.ExecContext(ctx, "SAVEPOINT movie_operation")
+				if err != nil {
+					return err
+				}
+			}
+
+			err := applyMovieOperation(ctx, tx, op, actorID)
+
+			switch {
+			case err == nil:
+				if !atomic {
+					_, err = tx.ExecContext(ctx, "RELEASE SAVEPOINT movie_operation")
+					if err != nil {
+						return err
+					}
+				}
+			case errors.Is(err, ErrRecordNotFound), errors.Is(err, ErrEditConflict):
+				op.Err = err
+
+				if atomic {
+					return ErrBatchRolledBack
+				}
+
+				_, err = tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT movie_operation")
+				if err != nil {
+					return err
+				}
+			default:
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func applyMovieOperation(ctx context.Context, tx *sql.Tx, op *MovieOperation, actorID int64) error {
+	switch op.Action {
+	case MovieOpCreate:
+		return insertMovie(ctx, tx, op.Movie, actorID)
+	case MovieOpUpdate:
+		return updateMovie(ctx, tx, op.Movie, actorID)
+	case MovieOpDelete:
+		return deleteMovie(ctx, tx, op.ID, op.Version, actorID)
+	default:
+		return fmt.Errorf("unknown movie operation %q", op.Action)
+	}
+}
+
+func insertMovie(ctx context.Context, tx *sql.Tx, movie *Movie, actorID int64) error {
 	query := `
 		INSERT INTO movies (title, year, runtime, genres)
 		VALUES ($1, $2, $3, $4)
 		RETURNING id, created_at, version
 	`
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query,
+	err := tx.QueryRowContext(ctx, query,
 		movie.Title,
 		movie.Year,
 		movie.Runtime,
@@ -80,23 +358,24 @@ func (m movieModel) Insert(movie *Movie) error {
 		return err
 	}
 
-	return nil
-}
-
-func (m movieModel) Delete(id int64) error {
-	if id < 1 {
-		return ErrRecordNotFound
+	if movie.ExternalIDs != nil && !movie.ExternalIDs.IsEmpty() {
+		err = upsertExternalIDs(ctx, tx, movie.ID, *movie.ExternalIDs)
+		if err != nil {
+			return err
+		}
 	}
 
+	return insertRevision(ctx, tx, movie.ID, RevisionCreate, changedFieldNames(nil, snapshotOf(movie)), actorID)
+}
+
+func deleteMovie(ctx context.Context, tx *sql.Tx, id int64, version int32, actorID int64) error {
 	query := `
-	DELETE FROM movies
-	WHERE id = $1
+	UPDATE movies
+	SET deleted_at = NOW(), version = version + 1
+	WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	result, err := m.DB.ExecContext(ctx, query, id)
+	result, err := tx.ExecContext(ctx, query, id, version)
 	if err != nil {
 		return err
 	}
@@ -107,24 +386,49 @@ func (m movieModel) Delete(id int64) error {
 	}
 
 	if rowsAffected == 0 {
+		if version != 0 {
+			return ErrEditConflict
+		}
+
 		return ErrRecordNotFound
 	}
 
-	return nil
+	return insertRevision(ctx, tx, id, RevisionDelete, []string{}, actorID)
 }
 
-func (m movieModel) Update(movie *Movie) error {
+func updateMovie(ctx context.Context, tx *sql.Tx, movie *Movie, actorID int64) error {
+	// row is locked so the changed fields are computed against the state being replaced
 	query := `
+	SELECT title, year, runtime, genres
+	FROM movies
+	WHERE id = $1 AND version = $2 AND deleted_at IS NULL
+	FOR UPDATE`
+
+	var previous MovieSnapshot
+
+	err := tx.QueryRowContext(ctx, query, movie.ID, movie.Version).Scan(
+		&previous.Title,
+		&previous.Year,
+		&previous.Runtime,
+		pq.Array(&previous.Genres),
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return err
+		}
+	}
+
+	query = `
 	UPDATE movies
 	SET title = $1, year = $2, runtime = $3, genres = $4, version = version + 1
-	WHERE id = $5 AND version = $6
+	WHERE id = $5 AND version = $6 AND deleted_at IS NULL
 	RETURNING version
 	`
 
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
-	defer cancel()
-
-	err := m.DB.QueryRowContext(ctx, query, movie.Title, movie.Year, movie.Runtime, pq.Array(movie.Genres), movie.ID, movie.Version).Scan(
+	err = tx.QueryRowContext(ctx, query, movie.Title, movie.Year, movie.Runtime, pq.Array(movie.Genres), movie.ID, movie.Version).Scan(
 		&movie.Version)
 	if err != nil {
 		switch {
@@ -135,22 +439,262 @@ func (m movieModel) Update(movie *Movie) error {
 		}
 	}
 
-	return nil
+	return insertRevision(ctx, tx, movie.ID, RevisionUpdate, changedFieldNames(&previous, snapshotOf(movie)), actorID)
 }
 
-func (m movieModel) GetAll(title string, genres []string, filters Filters) ([]*Movie, Metadata, error) {
+// GetAll returns a page of movies matching the filter.
+// Title is matched both with full-text search and with trigram word similarity, so typos and partial words are found.
+// Pages are selected with LIMIT/OFFSET unless filters.Cursor is set, in which case keyset pagination is used.
+// One extra row is fetched to find out whether there is a next page and build the next cursor.
+func (m movieModel) GetAll(filter MovieFilter, filters Filters) ([]*Movie, Metadata, error) {
+	column := filters.sortColumn()
+	if column == "relevance" {
+		column = movieRelevance
+	}
+
+	where, args := filter.where()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	totalRecords := 0
+	totalColumn := "0"
+
+	offset := filters.offset()
+
+	if filters.Cursor != "" {
+		c, err := filters.decodeCursor()
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		// count(*) OVER() would only count rows after the cursor, so total is calculated separately
+		if filters.IncludeTotal {
+			err = m.DB.QueryRowContext(ctx, "SELECT count(*) FROM movies WHERE "+where, args...).Scan(&totalRecords)
+			if err != nil {
+				return nil, Metadata{}, err
+			}
+		}
+
+		args = append(args, c.Value, c.ID)
+		where += "\n\tAND " + filters.keysetCondition(column, len(args)-1, len(args))
+		offset = 0
+	} else if filters.IncludeTotal {
+		totalColumn = "count(*) OVER()"
+	}
+
+	args = append(args, filters.limit()+1, offset)
+
 	query := fmt.Sprintf(`
-	SELECT count(*) OVER(), id, created_at, title, year, runtime, genres, version
+	SELECT %s, id, created_at, title, year, runtime, genres, version, rating, rating_count, %s, %s, %s::text
 	FROM movies
-	WHERE (to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 = '')
-	AND (genres @> $2 OR $2 = '{}')
+	WHERE %s
 	ORDER BY %s %s, id ASC
-	LIMIT $3 OFFSET $4`, filters.sortColumn(), filters.sortDirection())
+	LIMIT $%d OFFSET $%d`, totalColumn, externalIDsSQL, movieCollectionSQL, column, where, column, filters.sortDirection(), len(args)-1, len(args))
+
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	var movies []*Movie
+	var sortValues []string
+
+	for rows.Next() {
+		var movie Movie
+		var sortValue string
+
+		err := rows.Scan(
+			&totalRecords,
+			&movie.ID,
+			&movie.CreatedAt,
+			&movie.Title,
+			&movie.Year,
+			&movie.Runtime,
+			pq.Array(&movie.Genres),
+			&movie.Version,
+			&movie.Rating,
+			&movie.RatingCount,
+			&movie.ExternalIDs,
+			&movie.Collection,
+			&sortValue,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		movies = append(movies, &movie)
+		sortValues = append(sortValues, sortValue)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	var metadata Metadata
+
+	switch {
+	case !filters.IncludeTotal:
+		metadata = calculatePageMetadata(filters)
+	case filters.Cursor != "":
+		metadata = Metadata{PageSize: filters.PageSize, TotalRecords: totalRecords}
+	default:
+		metadata = calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+	}
+
+	if len(movies) > filters.limit() {
+		movies = movies[:filters.limit()]
+		last := movies[len(movies)-1]
+
+		metadata.NextCursor = encodeCursor(cursor{
+			Sort:  filters.Sort,
+			Value: sortValues[len(movies)-1],
+			ID:    last.ID,
+		})
+	}
+
+	return movies, metadata, nil
+}
+
+// Suggest returns up to limit movies whose title, or a translated title, starts with query
+// or has words similar to it by trigram word similarity. Prefix matches go first, the rest is
+// ordered by word similarity. Text in the middle of a title matches only through similarity.
+func (m movieModel) Suggest(query string, limit int) ([]*MovieSuggestion, error) {
+	sqlQuery := `
+	SELECT id, title, year
+	FROM movies
+	WHERE (title ILIKE $2 OR $1 <% title OR id IN (
+		SELECT movie_id FROM movie_translations WHERE title ILIKE $2 OR $1 <% title
+	)) AND deleted_at IS NULL
+	ORDER BY title ILIKE $2 DESC, word_similarity($1, title) DESC, id ASC
+	LIMIT $3`
+
+	// escaping LIKE wildcards so they are matched literally
+	prefix := strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(query) + "%"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, sqlQuery, query, prefix, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	suggestions := []*MovieSuggestion{}
+
+	for rows.Next() {
+		var suggestion MovieSuggestion
+
+		err := rows.Scan(&suggestion.ID, &suggestion.Title, &suggestion.Year)
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions = append(suggestions, &suggestion)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return suggestions, nil
+}
+
+// Facets counts movies matching the filter for every bucket of the requested facets.
+// Facet names must be validated against FacetGenres and FacetDecade in the handler.
+func (m movieModel) Facets(filter MovieFilter, names []string) (Facets, error) {
+	where, args := filter.where()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	facets := make(Facets, len(names))
+
+	for _, name := range names {
+		query, ok := facetQueries[name]
+		if !ok {
+			// panic here because we should already be validating user input in the handler
+			panic("unknown facet: " + name)
+		}
+
+		buckets, err := m.facetBuckets(ctx, fmt.Sprintf(query, where), args)
+		if err != nil {
+			return nil, err
+		}
+
+		facets[name] = buckets
+	}
+
+	return facets, nil
+}
+
+// facetBuckets runs a single facet query and scans the buckets.
+func (m movieModel) facetBuckets(ctx context.Context, query string, args []any) ([]FacetBucket, error) {
+	rows, err := m.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	buckets := []FacetBucket{}
+
+	for rows.Next() {
+		var bucket FacetBucket
+
+		err := rows.Scan(&bucket.Value, &bucket.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return buckets, nil
+}
+
+// GetDeleted returns a page of movies in trash.
+func (m movieModel) GetDeleted(filters Filters) ([]*Movie, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), id, created_at, title, year, runtime, genres, version, deleted_at
+	FROM movies
+	WHERE deleted_at IS NOT NULL
+	ORDER BY %s %s, id ASC
+	LIMIT $1 OFFSET $2`, filters.sortColumn(), filters.sortDirection())
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	rows, err := m.DB.QueryContext(ctx, query, title, pq.Array(genres), filters.limit(), filters.offset())
+	rows, err := m.DB.QueryContext(ctx, query, filters.limit(), filters.offset())
 	if err != nil {
 		return nil, Metadata{}, err
 	}
@@ -165,7 +709,7 @@ func (m movieModel) GetAll(title string, genres []string, filters Filters) ([]*M
 	}()
 
 	totalRecords := 0
-	var movies []*Movie
+	movies := []*Movie{}
 
 	for rows.Next() {
 		var movie Movie
@@ -179,6 +723,7 @@ func (m movieModel) GetAll(title string, genres []string, filters Filters) ([]*M
 			&movie.Runtime,
 			pq.Array(&movie.Genres),
 			&movie.Version,
+			&movie.DeletedAt,
 		)
 		if err != nil {
 			return nil, Metadata{}, err
@@ -190,7 +735,72 @@ func (m movieModel) GetAll(title string, genres []string, filters Filters) ([]*M
 	if err = rows.Err(); err != nil {
 		return nil, Metadata{}, err
 	}
+
 	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
 
 	return movies, metadata, nil
 }
+
+// Restore brings the movie back from trash. Returns ErrRecordNotFound if the movie is not in trash.
+func (m movieModel) Restore(id int64, actorID int64) (*Movie, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+	UPDATE movies
+	SET deleted_at = NULL, version = version + 1
+	WHERE id = $1 AND deleted_at IS NOT NULL
+	RETURNING id, created_at, title, year, runtime, genres, version`
+
+	var movie Movie
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, query, id).Scan(
+			&movie.ID,
+			&movie.CreatedAt,
+			&movie.Title,
+			&movie.Year,
+			&movie.Runtime,
+			pq.Array(&movie.Genres),
+			&movie.Version,
+		)
+		if err != nil {
+			return err
+		}
+
+		return insertRevision(ctx, tx, id, RevisionRestore, []string{}, actorID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &movie, nil
+}
+
+// PurgeDeleted permanently deletes movies moved to trash before the given time
+// and returns the number of deleted movies.
+func (m movieModel) PurgeDeleted(before time.Time) (int64, error) {
+	query := `
+	DELETE FROM movies
+	WHERE deleted_at < $1`
+
+	// purging can touch a lot of rows, so it gets more time than usual queries
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected()
+}
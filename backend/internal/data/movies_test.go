@@ -0,0 +1,74 @@
+package data
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMovieFilterWhere(t *testing.T) {
+	titleCondition := "deleted_at IS NULL\n\tAND $1::text = ''"
+
+	tests := []struct {
+		name     string
+		filter   MovieFilter
+		want     string
+		wantArgs int
+	}{
+		{
+			name:     "Empty",
+			filter:   MovieFilter{},
+			want:     titleCondition,
+			wantArgs: 1,
+		},
+		{
+			name:     "All genres",
+			filter:   MovieFilter{Genres: []string{"Drama"}, GenresMatch: GenresMatchAll},
+			want:     titleCondition + "\n\tAND genres @> $2",
+			wantArgs: 2,
+		},
+		{
+			name:     "Any genre with exclusion",
+			filter:   MovieFilter{Genres: []string{"Drama"}, GenresMatch: GenresMatchAny, ExcludeGenres: []string{"Horror"}},
+			want:     titleCondition + "\n\tAND genres && $2\n\tAND NOT genres && $3",
+			wantArgs: 3,
+		},
+		{
+			name:     "Ranges",
+			filter:   MovieFilter{YearMin: 1990, YearMax: 1999, RuntimeMax: 120, CreatedAfter: time.Now()},
+			want:     titleCondition + "\n\tAND year >= $2\n\tAND year <= $3\n\tAND runtime <= $4\n\tAND created_at > $5",
+			wantArgs: 5,
+		},
+		{
+			name:   "Title",
+			filter: MovieFilter{Title: "shawshank"},
+			want: "deleted_at IS NULL\n\tAND (to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 <% title OR id IN (" +
+				"SELECT movie_id FROM movie_translations WHERE to_tsvector(search_config, title) @@ plainto_tsquery(search_config, $1) OR $1 <% title))",
+			wantArgs: 1,
+		},
+		{
+			name:     "Rating range",
+			filter:   MovieFilter{RatingMin: 7.5, RatingMax: 9},
+			want:     titleCondition + "\n\tAND rating_count > 0\n\tAND rating >= $2\n\tAND rating <= $3",
+			wantArgs: 3,
+		},
+		{
+			name:     "Collection",
+			filter:   MovieFilter{CollectionID: 1},
+			want:     titleCondition + "\n\tAND id IN (SELECT movie_id FROM collection_movies WHERE collection_id = $2)",
+			wantArgs: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, args := tt.filter.where()
+			if got != tt.want {
+				t.Errorf("got %q; want %q", got, tt.want)
+			}
+
+			if len(args) != tt.wantArgs {
+				t.Errorf("got %d args; want %d", len(args), tt.wantArgs)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Rating is the score a user gave to a movie, RatedAt is the time of the last change.
+type Rating struct {
+	MovieID int64     `json:"movie_id" example:"1"`
+	Title   string    `json:"title" example:"The Shawshank Redemption"`
+	Year    int32     `json:"year" example:"1994"`
+	Score   int32     `json:"score" example:"9"`
+	RatedAt time.Time `json:"rated_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type ratingModel struct {
+	DB *sql.DB
+}
+
+// GetAllForUser returns a page of movies rated by the user, movies in trash are left out.
+func (m ratingModel) GetAllForUser(userID int64, filters Filters) ([]*Rating, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), r.movie_id, m.title, m.year, r.score, r.updated_at AS rated_at
+	FROM ratings r
+	JOIN movies m ON m.id = r.movie_id
+	WHERE r.user_id = $1 AND m.deleted_at IS NULL
+	ORDER BY %s %s, r.movie_id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	ratings := []*Rating{}
+
+	for rows.Next() {
+		var rating Rating
+
+		err := rows.Scan(
+			&totalRecords,
+			&rating.MovieID,
+			&rating.Title,
+			&rating.Year,
+			&rating.Score,
+			&rating.RatedAt,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		ratings = append(ratings, &rating)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return ratings, metadata, nil
+}
+
+// Upsert saves the user's score of the movie and updates the movie rating aggregates.
+// Returns ErrRecordNotFound if the movie doesn't exist or is in trash.
+func (m ratingModel) Upsert(userID int64, rating *Rating) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		// the movie row lock serializes rating changes of the movie, so the aggregates stay consistent
+		query := `
+		SELECT title, year FROM movies
+		WHERE id = $1 AND deleted_at IS NULL
+		FOR UPDATE`
+
+		err := tx.QueryRowContext(ctx, query, rating.MovieID).Scan(&rating.Title, &rating.Year)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrRecordNotFound
+			default:
+				return err
+			}
+		}
+
+		var oldScore int32
+
+		query = "SELECT score FROM ratings WHERE user_id = $1 AND movie_id = $2"
+
+		err = tx.QueryRowContext(ctx, query, userID, rating.MovieID).Scan(&oldScore)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		query = `
+		INSERT INTO ratings (user_id, movie_id, score)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, movie_id) DO UPDATE
+		SET score = EXCLUDED.score, updated_at = NOW()
+		RETURNING updated_at`
+
+		err = tx.QueryRowContext(ctx, query, userID, rating.MovieID, rating.Score).Scan(&rating.RatedAt)
+		if err != nil {
+			return err
+		}
+
+		// a zero old score means the user rates the movie for the first time
+		var added int32
+		if oldScore == 0 {
+			added = 1
+		}
+
+		query = `
+		UPDATE movies
+		SET rating_sum = rating_sum + $2, rating_count = rating_count + $3
+		WHERE id = $1`
+
+		_, err = tx.ExecContext(ctx, query, rating.MovieID, rating.Score-oldScore, added)
+
+		return err
+	})
+}
+
+// Delete removes the user's score of the movie and updates the movie rating aggregates.
+// Returns ErrRecordNotFound if the user hasn't rated the movie.
+func (m ratingModel) Delete(userID, movieID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		// locking the movie as in Upsert, so a concurrent upsert can't count the deleted score
+		_, err := tx.ExecContext(ctx, "SELECT 1 FROM movies WHERE id = $1 FOR UPDATE", movieID)
+		if err != nil {
+			return err
+		}
+
+		var score int32
+
+		query := "DELETE FROM ratings WHERE user_id = $1 AND movie_id = $2 RETURNING score"
+
+		err = tx.QueryRowContext(ctx, query, userID, movieID).Scan(&score)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrRecordNotFound
+			default:
+				return err
+			}
+		}
+
+		query = `
+		UPDATE movies
+		SET rating_sum = rating_sum - $2, rating_count = rating_count - 1
+		WHERE id = $1`
+
+		_, err = tx.ExecContext(ctx, query, movieID, score)
+
+		return err
+	})
+}
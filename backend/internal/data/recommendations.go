@@ -0,0 +1,123 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// ProfileMovie is a movie of the user's taste profile, Weight tells how much the user liked it from -1 to 1.
+type ProfileMovie struct {
+	// TMDB id of the movie, the recommendation service knows movies by them
+	MovieID int64
+	Weight  float64
+}
+
+// unratedSeenWeight is the weight of movies the user has seen but not rated.
+const unratedSeenWeight = 0.5
+
+type recommendationModel struct {
+	DB *sql.DB
+}
+
+// Profile returns the taste profile of the user built from ratings and watch history, the latest movies first.
+// Scores from 1 to 10 map to weights from -1 to 1, seen movies without a rating weigh unratedSeenWeight.
+// Movies without a TMDB id are unknown to the recommendation service and are left out.
+func (m recommendationModel) Profile(userID int64, limit int) ([]*ProfileMovie, error) {
+	query := `
+	SELECT e.tmdb_id, COALESCE((r.score - 5.5) / 4.5, $2)
+	FROM movies m
+	JOIN movie_external_ids e ON e.movie_id = m.id AND e.tmdb_id IS NOT NULL
+	LEFT JOIN ratings r ON r.movie_id = m.id AND r.user_id = $1
+	LEFT JOIN seen_movies s ON s.movie_id = m.id AND s.user_id = $1
+	WHERE (r.movie_id IS NOT NULL OR s.movie_id IS NOT NULL) AND m.deleted_at IS NULL
+	ORDER BY GREATEST(r.updated_at, s.seen_at) DESC, m.id ASC
+	LIMIT $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, unratedSeenWeight, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	profile := []*ProfileMovie{}
+
+	for rows.Next() {
+		var movie ProfileMovie
+
+		err := rows.Scan(&movie.MovieID, &movie.Weight)
+		if err != nil {
+			return nil, err
+		}
+
+		profile = append(profile, &movie)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// Excluded returns TMDB ids of movies that are not recommended to the user:
+// movies the user rated, has seen or dismissed.
+func (m recommendationModel) Excluded(userID int64) ([]int64, error) {
+	query := `
+	SELECT tmdb_id
+	FROM movie_external_ids
+	WHERE tmdb_id IS NOT NULL AND movie_id IN (
+		SELECT movie_id FROM ratings WHERE user_id = $1
+		UNION
+		SELECT movie_id FROM seen_movies WHERE user_id = $1
+		UNION
+		SELECT movie_id FROM dismissed_movies WHERE user_id = $1)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	tmdbIDs := []int64{}
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		tmdbIDs = append(tmdbIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tmdbIDs, nil
+}
@@ -0,0 +1,448 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+var (
+	ErrOwnReview = errors.New("own reviews can't be voted for or reported")
+)
+
+// Review is a user's text about a movie. Score is the author's rating of the movie, nil if the author hasn't rated it.
+type Review struct {
+	ID             int64     `json:"id" example:"1"`
+	MovieID        int64     `json:"movie_id" example:"1"`
+	UserID         int64     `json:"user_id" example:"1"`
+	UserName       string    `json:"user_name" example:"John Doe"`
+	Body           string    `json:"body" example:"A slow but rewarding story about hope."`
+	Spoiler        bool      `json:"spoiler" example:"false"`
+	Score          *int32    `json:"score,omitempty" example:"9"`
+	HelpfulCount   int32     `json:"helpful_count" example:"12"`
+	UnhelpfulCount int32     `json:"unhelpful_count" example:"1"`
+	Hidden         bool      `json:"hidden,omitempty" example:"false"`
+	CreatedAt      time.Time `json:"created_at" example:"2025-01-01T00:00:00Z"`
+	UpdatedAt      time.Time `json:"updated_at" example:"2025-01-01T00:00:00Z"`
+	Version        int32     `json:"version" example:"1"`
+}
+
+// ReportedReview is a review in the moderation queue with its open reports.
+type ReportedReview struct {
+	Review
+	ReportCount    int32     `json:"report_count" example:"3"`
+	Reasons        []string  `json:"reasons" example:"spam"`
+	LastReportedAt time.Time `json:"last_reported_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type reviewModel struct {
+	DB *sql.DB
+}
+
+// GetAllForMovie returns a page of visible reviews of the movie.
+func (m reviewModel) GetAllForMovie(movieID int64, filters Filters) ([]*Review, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), r.id, r.movie_id, r.user_id, u.name, r.body, r.spoiler, rt.score,
+		r.helpful_count, r.unhelpful_count, r.created_at, r.updated_at, r.version
+	FROM reviews r
+	JOIN users u ON u.id = r.user_id
+	LEFT JOIN ratings rt ON rt.user_id = r.user_id AND rt.movie_id = r.movie_id
+	WHERE r.movie_id = $1 AND r.hidden_at IS NULL
+	ORDER BY r.%s %s, r.id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, movieID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	reviews := []*Review{}
+
+	for rows.Next() {
+		var review Review
+
+		err := rows.Scan(
+			&totalRecords,
+			&review.ID,
+			&review.MovieID,
+			&review.UserID,
+			&review.UserName,
+			&review.Body,
+			&review.Spoiler,
+			&review.Score,
+			&review.HelpfulCount,
+			&review.UnhelpfulCount,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+			&review.Version,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		reviews = append(reviews, &review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return reviews, metadata, nil
+}
+
+// Upsert saves the user's review of the movie, a user has a single review per movie.
+// Votes are kept on edits and a hidden review stays hidden. Reports whether the review was created,
+// returns ErrRecordNotFound if the movie doesn't exist or is in trash.
+func (m reviewModel) Upsert(review *Review) (bool, error) {
+	// xmax is zero only for freshly inserted rows, as in watchlistModel.Add
+	query := `
+	WITH movie AS (
+		SELECT id FROM movies WHERE id = $2 AND deleted_at IS NULL
+	), saved AS (
+		INSERT INTO reviews (user_id, movie_id, body, spoiler)
+		SELECT $1, id, $3, $4 FROM movie
+		ON CONFLICT (user_id, movie_id) DO UPDATE
+		SET body = EXCLUDED.body, spoiler = EXCLUDED.spoiler, updated_at = NOW(), version = reviews.version + 1
+		RETURNING id, helpful_count, unhelpful_count, hidden_at IS NOT NULL AS hidden,
+			created_at, updated_at, version, xmax = 0 AS inserted
+	)
+	SELECT saved.id, u.name, rt.score, saved.helpful_count, saved.unhelpful_count, saved.hidden,
+		saved.created_at, saved.updated_at, saved.version, saved.inserted
+	FROM saved
+	JOIN users u ON u.id = $1
+	LEFT JOIN ratings rt ON rt.user_id = $1 AND rt.movie_id = $2`
+
+	args := []any{review.UserID, review.MovieID, review.Body, review.Spoiler}
+
+	var inserted bool
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(
+		&review.ID,
+		&review.UserName,
+		&review.Score,
+		&review.HelpfulCount,
+		&review.UnhelpfulCount,
+		&review.Hidden,
+		&review.CreatedAt,
+		&review.UpdatedAt,
+		&review.Version,
+		&inserted,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return false, ErrRecordNotFound
+		default:
+			return false, err
+		}
+	}
+
+	return inserted, nil
+}
+
+// Delete removes the user's review of the movie together with its votes and reports.
+func (m reviewModel) Delete(userID, movieID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, "DELETE FROM reviews WHERE user_id = $1 AND movie_id = $2", userID, movieID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// lockVisible locks the review row, so vote counters stay consistent, and returns its author.
+// Returns ErrRecordNotFound if the review doesn't exist or is hidden.
+func (m reviewModel) lockVisible(ctx context.Context, tx *sql.Tx, reviewID int64) (int64, error) {
+	var authorID int64
+
+	query := "SELECT user_id FROM reviews WHERE id = $1 AND hidden_at IS NULL FOR UPDATE"
+
+	err := tx.QueryRowContext(ctx, query, reviewID).Scan(&authorID)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return 0, ErrRecordNotFound
+		default:
+			return 0, err
+		}
+	}
+
+	return authorID, nil
+}
+
+// Vote saves the user's helpful or unhelpful vote for the review, a previous vote is replaced.
+// Returns ErrRecordNotFound if the review doesn't exist or is hidden and ErrOwnReview for the author.
+func (m reviewModel) Vote(userID, reviewID int64, helpful bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		authorID, err := m.lockVisible(ctx, tx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		if authorID == userID {
+			return ErrOwnReview
+		}
+
+		var old sql.NullBool
+
+		query := "SELECT helpful FROM review_votes WHERE review_id = $1 AND user_id = $2"
+
+		err = tx.QueryRowContext(ctx, query, reviewID, userID).Scan(&old)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if old.Valid && old.Bool == helpful {
+			return nil
+		}
+
+		query = `
+		INSERT INTO review_votes (review_id, user_id, helpful)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (review_id, user_id) DO UPDATE SET helpful = EXCLUDED.helpful`
+
+		_, err = tx.ExecContext(ctx, query, reviewID, userID, helpful)
+		if err != nil {
+			return err
+		}
+
+		helpfulDelta, unhelpfulDelta := voteDelta(helpful, 1)
+		if old.Valid {
+			oldHelpful, oldUnhelpful := voteDelta(old.Bool, -1)
+			helpfulDelta += oldHelpful
+			unhelpfulDelta += oldUnhelpful
+		}
+
+		query = `
+		UPDATE reviews
+		SET helpful_count = helpful_count + $2, unhelpful_count = unhelpful_count + $3
+		WHERE id = $1`
+
+		_, err = tx.ExecContext(ctx, query, reviewID, helpfulDelta, unhelpfulDelta)
+
+		return err
+	})
+}
+
+// Unvote removes the user's vote for the review.
+// Returns ErrRecordNotFound if the review is hidden or the user hasn't voted for it.
+func (m reviewModel) Unvote(userID, reviewID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		_, err := m.lockVisible(ctx, tx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		var helpful bool
+
+		query := "DELETE FROM review_votes WHERE review_id = $1 AND user_id = $2 RETURNING helpful"
+
+		err = tx.QueryRowContext(ctx, query, reviewID, userID).Scan(&helpful)
+		if err != nil {
+			switch {
+			case errors.Is(err, sql.ErrNoRows):
+				return ErrRecordNotFound
+			default:
+				return err
+			}
+		}
+
+		helpfulDelta, unhelpfulDelta := voteDelta(helpful, -1)
+
+		query = `
+		UPDATE reviews
+		SET helpful_count = helpful_count + $2, unhelpful_count = unhelpful_count + $3
+		WHERE id = $1`
+
+		_, err = tx.ExecContext(ctx, query, reviewID, helpfulDelta, unhelpfulDelta)
+
+		return err
+	})
+}
+
+// voteDelta returns the changes of the helpful and unhelpful counters for adding (n = 1) or removing (n = -1) a vote.
+func voteDelta(helpful bool, n int32) (int32, int32) {
+	if helpful {
+		return n, 0
+	}
+
+	return 0, n
+}
+
+// Report files the user's report of the review for moderators, reporting again replaces the reason
+// and reopens a resolved report. Returns ErrRecordNotFound if the review doesn't exist or is hidden
+// and ErrOwnReview for the author.
+func (m reviewModel) Report(userID, reviewID int64, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		authorID, err := m.lockVisible(ctx, tx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		if authorID == userID {
+			return ErrOwnReview
+		}
+
+		query := `
+		INSERT INTO review_reports (review_id, user_id, reason)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (review_id, user_id) DO UPDATE
+		SET reason = EXCLUDED.reason, created_at = NOW(), resolved_at = NULL`
+
+		_, err = tx.ExecContext(ctx, query, reviewID, userID, reason)
+
+		return err
+	})
+}
+
+// GetReported returns a page of the moderation queue, reviews with open reports, hidden ones included.
+func (m reviewModel) GetReported(filters Filters) ([]*ReportedReview, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), r.id, r.movie_id, r.user_id, u.name, r.body, r.spoiler, r.helpful_count,
+		r.unhelpful_count, r.hidden_at IS NOT NULL, r.created_at, r.updated_at, r.version,
+		rp.report_count, rp.reasons, rp.last_reported_at
+	FROM (
+		SELECT review_id, count(*) AS report_count, array_agg(reason ORDER BY created_at) AS reasons,
+			max(created_at) AS last_reported_at
+		FROM review_reports
+		WHERE resolved_at IS NULL
+		GROUP BY review_id
+	) AS rp
+	JOIN reviews r ON r.id = rp.review_id
+	JOIN users u ON u.id = r.user_id
+	ORDER BY %s %s, r.id ASC
+	LIMIT $1 OFFSET $2`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	reviews := []*ReportedReview{}
+
+	for rows.Next() {
+		var review ReportedReview
+
+		err := rows.Scan(
+			&totalRecords,
+			&review.ID,
+			&review.MovieID,
+			&review.UserID,
+			&review.UserName,
+			&review.Body,
+			&review.Spoiler,
+			&review.HelpfulCount,
+			&review.UnhelpfulCount,
+			&review.Hidden,
+			&review.CreatedAt,
+			&review.UpdatedAt,
+			&review.Version,
+			&review.ReportCount,
+			pq.Array(&review.Reasons),
+			&review.LastReportedAt,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		reviews = append(reviews, &review)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return reviews, metadata, nil
+}
+
+// Moderate hides or restores the review and resolves its open reports, so it leaves the moderation queue.
+// Returns ErrRecordNotFound if the review doesn't exist.
+func (m reviewModel) Moderate(reviewID int64, hidden bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		query := `
+		UPDATE reviews
+		SET hidden_at = CASE WHEN $2 THEN COALESCE(hidden_at, NOW()) END
+		WHERE id = $1`
+
+		result, err := tx.ExecContext(ctx, query, reviewID, hidden)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+
+		query = "UPDATE review_reports SET resolved_at = NOW() WHERE review_id = $1 AND resolved_at IS NULL"
+
+		_, err = tx.ExecContext(ctx, query, reviewID)
+
+		return err
+	})
+}
@@ -0,0 +1,280 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	RevisionCreate  = "create"
+	RevisionUpdate  = "update"
+	RevisionDelete  = "delete"
+	RevisionRestore = "restore"
+	RevisionMerge   = "merge"
+)
+
+// movieSnapshotSQL builds the revision snapshot from a movies row, keys match MovieSnapshot JSON tags.
+// The row has to be selected as movies, external ids are looked up by movies.id.
+const movieSnapshotSQL = "jsonb_build_object('title', title, 'year', year, 'runtime', runtime, 'genres', genres, " +
+	"'external_ids', COALESCE(" + externalIDsSQL + ", '{}'))"
+
+// MovieSnapshot holds the editable movie fields stored with every revision.
+// Snapshots saved before external ids were recorded have them empty.
+type MovieSnapshot struct {
+	Title       string      `json:"title"`
+	Year        int32       `json:"year"`
+	Runtime     int32       `json:"runtime"`
+	Genres      []string    `json:"genres"`
+	ExternalIDs ExternalIDs `json:"external_ids"`
+}
+
+func snapshotOf(movie *Movie) MovieSnapshot {
+	snapshot := MovieSnapshot{
+		Title:   movie.Title,
+		Year:    movie.Year,
+		Runtime: movie.Runtime,
+		Genres:  movie.Genres,
+	}
+
+	if movie.ExternalIDs != nil {
+		snapshot.ExternalIDs = *movie.ExternalIDs
+	}
+
+	return snapshot
+}
+
+// Apply copies snapshot fields to the movie, leaving id and version untouched.
+// External ids are not applied, they are changed only with SetExternalIDs, which checks their uniqueness.
+func (s MovieSnapshot) Apply(movie *Movie) {
+	movie.Title = s.Title
+	movie.Year = s.Year
+	movie.Runtime = s.Runtime
+	movie.Genres = s.Genres
+}
+
+// FieldChange describes a change of a single movie field between two revisions.
+// From is null for the first revision.
+type FieldChange struct {
+	Field string `json:"field" example:"title"`
+	From  any    `json:"from" swaggertype:"string" example:"The Shawshank Redemtion"`
+	To    any    `json:"to" swaggertype:"string" example:"The Shawshank Redemption"`
+}
+
+// MovieRevision is a movie state saved after every change together with who and when made it.
+type MovieRevision struct {
+	ID        int64         `json:"id" example:"1"`
+	MovieID   int64         `json:"movie_id" example:"1"`
+	Version   int32         `json:"version" example:"2"`
+	Action    string        `json:"action" example:"update"`
+	UserID    *int64        `json:"user_id" example:"1"`
+	CreatedAt time.Time     `json:"created_at" example:"2025-01-01T00:00:00Z"`
+	Changes   []FieldChange `json:"changes"`
+	Snapshot  MovieSnapshot `json:"-"`
+}
+
+// diffSnapshots returns changed fields between the previous and the current snapshot.
+// When there is no previous snapshot all fields are reported as changed.
+func diffSnapshots(previous *MovieSnapshot, current MovieSnapshot) []FieldChange {
+	var prev MovieSnapshot
+	if previous != nil {
+		prev = *previous
+	}
+
+	fields := []FieldChange{
+		{Field: "title", From: prev.Title, To: current.Title},
+		{Field: "year", From: prev.Year, To: current.Year},
+		{Field: "runtime", From: prev.Runtime, To: current.Runtime},
+		{Field: "genres", From: prev.Genres, To: current.Genres},
+		{Field: "external_ids", From: prev.ExternalIDs, To: current.ExternalIDs},
+	}
+
+	changes := []FieldChange{}
+
+	for _, field := range fields {
+		if previous == nil {
+			// movies created without external ids don't list them
+			if field.Field == "external_ids" && current.ExternalIDs.IsEmpty() {
+				continue
+			}
+
+			field.From = nil
+			changes = append(changes, field)
+			continue
+		}
+
+		if !reflect.DeepEqual(field.From, field.To) {
+			changes = append(changes, field)
+		}
+	}
+
+	return changes
+}
+
+// changedFieldNames returns names of the fields changed between two snapshots.
+func changedFieldNames(previous *MovieSnapshot, current MovieSnapshot) []string {
+	names := []string{}
+
+	for _, change := range diffSnapshots(previous, current) {
+		names = append(names, change.Field)
+	}
+
+	return names
+}
+
+// actorParam converts user id to a nullable query parameter, anonymous user (id 0) is stored as NULL.
+func actorParam(userID int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: userID, Valid: userID > 0}
+}
+
+// insertRevision saves the current state of the movie as a revision within the transaction.
+func insertRevision(ctx context.Context, tx *sql.Tx, movieID int64, action string, changedFields []string, actorID int64) error {
+	query := `
+	INSERT INTO movie_revisions (movie_id, version, action, user_id, changed_fields, snapshot)
+	SELECT id, version, $2, $3, $4, ` + movieSnapshotSQL + `
+	FROM movies
+	WHERE id = $1`
+
+	_, err := tx.ExecContext(ctx, query, movieID, action, actorParam(actorID), pq.Array(changedFields))
+	return err
+}
+
+// History returns a page of movie revisions, newest first, with changes compared to the previous revision.
+// Returns ErrRecordNotFound if the movie has no revisions.
+func (m movieModel) History(movieID int64, filters Filters) ([]*MovieRevision, Metadata, error) {
+	if movieID < 1 {
+		return nil, Metadata{}, ErrRecordNotFound
+	}
+
+	// previous snapshot is taken before paging, so the oldest revision on a page still gets its diff
+	query := `
+	SELECT count(*) OVER(), id, movie_id, version, action, user_id, created_at, snapshot, previous
+	FROM (
+		SELECT *, lag(snapshot) OVER (ORDER BY version) AS previous
+		FROM movie_revisions
+		WHERE movie_id = $1
+	) AS revisions
+	ORDER BY version DESC
+	LIMIT $2 OFFSET $3`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, movieID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	revisions := []*MovieRevision{}
+
+	for rows.Next() {
+		var revision MovieRevision
+		var snapshot, previous []byte
+
+		err := rows.Scan(
+			&totalRecords,
+			&revision.ID,
+			&revision.MovieID,
+			&revision.Version,
+			&revision.Action,
+			&revision.UserID,
+			&revision.CreatedAt,
+			&snapshot,
+			&previous,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		err = json.Unmarshal(snapshot, &revision.Snapshot)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		var previousSnapshot *MovieSnapshot
+
+		if previous != nil {
+			previousSnapshot = &MovieSnapshot{}
+
+			err = json.Unmarshal(previous, previousSnapshot)
+			if err != nil {
+				return nil, Metadata{}, err
+			}
+		}
+
+		revision.Changes = diffSnapshots(previousSnapshot, revision.Snapshot)
+
+		revisions = append(revisions, &revision)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	if totalRecords == 0 && filters.Page == 1 {
+		return nil, Metadata{}, ErrRecordNotFound
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return revisions, metadata, nil
+}
+
+// GetRevision returns the movie revision with the given version.
+func (m movieModel) GetRevision(movieID int64, version int32) (*MovieRevision, error) {
+	if movieID < 1 || version < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+	SELECT id, movie_id, version, action, user_id, created_at, snapshot
+	FROM movie_revisions
+	WHERE movie_id = $1 AND version = $2`
+
+	var revision MovieRevision
+	var snapshot []byte
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, movieID, version).Scan(
+		&revision.ID,
+		&revision.MovieID,
+		&revision.Version,
+		&revision.Action,
+		&revision.UserID,
+		&revision.CreatedAt,
+		&snapshot,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	err = json.Unmarshal(snapshot, &revision.Snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &revision, nil
+}
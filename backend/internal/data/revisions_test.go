@@ -0,0 +1,71 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSnapshots(t *testing.T) {
+	previous := MovieSnapshot{Title: "Heat", Year: 1995, Runtime: 170, Genres: []string{"Crime"}}
+
+	tests := []struct {
+		name     string
+		previous *MovieSnapshot
+		current  MovieSnapshot
+		want     []FieldChange
+	}{
+		{
+			name:     "First revision",
+			previous: nil,
+			current:  previous,
+			want: []FieldChange{
+				{Field: "title", To: "Heat"},
+				{Field: "year", To: int32(1995)},
+				{Field: "runtime", To: int32(170)},
+				{Field: "genres", To: []string{"Crime"}},
+			},
+		},
+		{
+			name:     "No changes",
+			previous: &previous,
+			current:  previous,
+			want:     []FieldChange{},
+		},
+		{
+			name:     "First revision with external ids",
+			previous: nil,
+			current:  MovieSnapshot{Title: "Heat", Year: 1995, Runtime: 170, Genres: []string{"Crime"}, ExternalIDs: ExternalIDs{TMDB: 949}},
+			want: []FieldChange{
+				{Field: "title", To: "Heat"},
+				{Field: "year", To: int32(1995)},
+				{Field: "runtime", To: int32(170)},
+				{Field: "genres", To: []string{"Crime"}},
+				{Field: "external_ids", To: ExternalIDs{TMDB: 949}},
+			},
+		},
+		{
+			name:     "Changed external ids",
+			previous: &previous,
+			current:  MovieSnapshot{Title: "Heat", Year: 1995, Runtime: 170, Genres: []string{"Crime"}, ExternalIDs: ExternalIDs{IMDb: "tt0113277"}},
+			want: []FieldChange{
+				{Field: "external_ids", From: ExternalIDs{}, To: ExternalIDs{IMDb: "tt0113277"}},
+			},
+		},
+		{
+			name:     "Changed fields",
+			previous: &previous,
+			current:  MovieSnapshot{Title: "Heat", Year: 1995, Runtime: 171, Genres: []string{"Crime", "Drama"}},
+			want: []FieldChange{
+				{Field: "runtime", From: int32(170), To: int32(171)},
+				{Field: "genres", From: []string{"Crime"}, To: []string{"Crime", "Drama"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, diffSnapshots(tt.previous, tt.current))
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// SearchResult is a movie or series matching a search query, Year is the release or start year.
+type SearchResult struct {
+	Type  string  `json:"type" example:"movie"`
+	ID    int64   `json:"id" example:"1"`
+	Title string  `json:"title" example:"The Shawshank Redemption"`
+	Year  int32   `json:"year" example:"1994"`
+	Score float64 `json:"score" example:"1.06"`
+}
+
+type searchModel struct {
+	DB *sql.DB
+}
+
+// seriesRelevance is an SQL expression ranking how well the series title matches the search query in $1,
+// it is calculated the same way as movieRelevance, so movies and series can be ordered together.
+const seriesRelevance = "(ts_rank(to_tsvector('simple', title), plainto_tsquery('simple', $1)) + word_similarity($1, title))::float8"
+
+// Search returns a page of movies and series of the given content types matching the query, best matches first.
+func (m searchModel) Search(query string, types []string, filters Filters) ([]*SearchResult, Metadata, error) {
+	sqlQuery := `
+	SELECT count(*) OVER(), type, id, title, year, score
+	FROM (
+		SELECT '` + ContentTypeMovie + `' AS type, id, title, year, ` + movieRelevance + ` AS score
+		FROM movies
+		WHERE deleted_at IS NULL
+		AND (to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 <% title OR id IN (` + translatedTitleMatch + `))
+		UNION ALL
+		SELECT '` + ContentTypeSeries + `', id, title, start_year, ` + seriesRelevance + `
+		FROM series
+		WHERE to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 <% title
+	) AS results
+	WHERE type = ANY($2)
+	ORDER BY score DESC, type ASC, id ASC
+	LIMIT $3 OFFSET $4`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, sqlQuery, query, pq.Array(types), filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	results := []*SearchResult{}
+
+	for rows.Next() {
+		var result SearchResult
+
+		err := rows.Scan(
+			&totalRecords,
+			&result.Type,
+			&result.ID,
+			&result.Title,
+			&result.Year,
+			&result.Score,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		results = append(results, &result)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return results, metadata, nil
+}
@@ -0,0 +1,597 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const (
+	ContentTypeMovie  = "movie"
+	ContentTypeSeries = "series"
+)
+
+var (
+	ErrUnknownSeries         = errors.New("series doesn't exist")
+	ErrDuplicateSeriesTMDBID = errors.New("is already assigned to another series")
+)
+
+// Series is a TV show, its seasons are filled in by Get without episodes.
+type Series struct {
+	ID        int64     `json:"id" example:"1"`
+	CreatedAt time.Time `json:"-"`
+	Title     string    `json:"title" example:"Breaking Bad"`
+	StartYear int32     `json:"start_year" example:"2008"`
+	// zero while the series is still running
+	EndYear  int32    `json:"end_year,omitempty" example:"2013"`
+	Genres   []string `json:"genres" example:"Drama,Crime"`
+	Overview string   `json:"overview,omitempty" example:"A chemistry teacher diagnosed with cancer turns to making meth..."`
+	// TMDB id of the series, the recommendation service knows series by them
+	TMDBID  int64     `json:"tmdb_id,omitempty" example:"1396"`
+	Seasons []*Season `json:"seasons,omitempty"`
+	Version int32     `json:"version" example:"1"`
+}
+
+// Season of a series, number 0 is used for specials.
+// Air dates are kept as YYYY-MM-DD strings, empty when unknown.
+type Season struct {
+	Number       int32      `json:"number" example:"1"`
+	Title        string     `json:"title,omitempty" example:"Season 1"`
+	AirDate      string     `json:"air_date,omitempty" example:"2008-01-20"`
+	EpisodeCount int        `json:"episode_count" example:"7"`
+	Episodes     []*Episode `json:"episodes,omitempty"`
+	Version      int32      `json:"version" example:"1"`
+}
+
+type Episode struct {
+	Number  int32  `json:"number" example:"1"`
+	Title   string `json:"title" example:"Pilot"`
+	Runtime int32  `json:"runtime" example:"58"`
+	AirDate string `json:"air_date,omitempty" example:"2008-01-20"`
+}
+
+type seriesModel struct {
+	DB *sql.DB
+}
+
+// GetAll returns a page of series, title is matched like movie titles and
+// the series have to include all of the genres.
+func (m seriesModel) GetAll(title string, genres []string, filters Filters) ([]*Series, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), id, created_at, title, start_year, COALESCE(end_year, 0), genres, overview, COALESCE(tmdb_id, 0), version
+	FROM series
+	WHERE (to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 <%% title OR $1 = '')
+	AND genres @> $2
+	ORDER BY %s %s, id ASC
+	LIMIT $3 OFFSET $4`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, title, pq.Array(genres), filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	series := []*Series{}
+
+	for rows.Next() {
+		var s Series
+
+		err := rows.Scan(
+			&totalRecords,
+			&s.ID,
+			&s.CreatedAt,
+			&s.Title,
+			&s.StartYear,
+			&s.EndYear,
+			pq.Array(&s.Genres),
+			&s.Overview,
+			&s.TMDBID,
+			&s.Version,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		series = append(series, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return series, metadata, nil
+}
+
+// Get returns the series with its seasons in order, episodes are only counted.
+func (m seriesModel) Get(id int64) (*Series, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+	SELECT id, created_at, title, start_year, COALESCE(end_year, 0), genres, overview, COALESCE(tmdb_id, 0), version
+	FROM series
+	WHERE id = $1`
+
+	var series Series
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(
+		&series.ID,
+		&series.CreatedAt,
+		&series.Title,
+		&series.StartYear,
+		&series.EndYear,
+		pq.Array(&series.Genres),
+		&series.Overview,
+		&series.TMDBID,
+		&series.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	query = `
+	SELECT s.number, s.title, COALESCE(s.air_date::text, ''), s.version,
+		(SELECT count(*) FROM episodes e WHERE e.series_id = s.series_id AND e.season_number = s.number)
+	FROM seasons s
+	WHERE s.series_id = $1
+	ORDER BY s.number`
+
+	rows, err := m.DB.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	series.Seasons = []*Season{}
+
+	for rows.Next() {
+		var season Season
+
+		err := rows.Scan(
+			&season.Number,
+			&season.Title,
+			&season.AirDate,
+			&season.Version,
+			&season.EpisodeCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		series.Seasons = append(series.Seasons, &season)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return &series, nil
+}
+
+// Insert adds the series, returns ErrDuplicateSeriesTMDBID if another series has its TMDB id.
+func (m seriesModel) Insert(series *Series) error {
+	query := `
+	INSERT INTO series (title, start_year, end_year, genres, overview, tmdb_id)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	RETURNING id, created_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	args := []any{
+		series.Title,
+		series.StartYear,
+		nullIfZero(series.EndYear),
+		pq.Array(series.Genres),
+		series.Overview,
+		nullIfZero(series.TMDBID),
+	}
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(
+		&series.ID,
+		&series.CreatedAt,
+		&series.Version,
+	)
+	if err != nil {
+		return seriesTMDBIDError(err)
+	}
+
+	return nil
+}
+
+// seriesTMDBIDError maps a unique violation of the series TMDB id to ErrDuplicateSeriesTMDBID.
+func seriesTMDBIDError(err error) error {
+	var pqErr *pq.Error
+
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" { // unique_violation
+		return ErrDuplicateSeriesTMDBID
+	}
+
+	return err
+}
+
+// Update saves series fields, seasons are changed with PutSeason and DeleteSeason.
+// Returns ErrEditConflict if the series version has changed since it was read
+// and ErrDuplicateSeriesTMDBID if another series has its TMDB id.
+func (m seriesModel) Update(series *Series) error {
+	query := `
+	UPDATE series
+	SET title = $1, start_year = $2, end_year = $3, genres = $4, overview = $5, tmdb_id = $6, version = version + 1
+	WHERE id = $7 AND version = $8
+	RETURNING version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	args := []any{
+		series.Title,
+		series.StartYear,
+		nullIfZero(series.EndYear),
+		pq.Array(series.Genres),
+		series.Overview,
+		nullIfZero(series.TMDBID),
+		series.ID,
+		series.Version,
+	}
+
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&series.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return seriesTMDBIDError(err)
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the series with its seasons and episodes.
+func (m seriesModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, "DELETE FROM series WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetAllByTMDBID returns the series with the given TMDB ids with a single query, in the order of the ids.
+// Ids of series missing from the catalog are left out.
+func (m seriesModel) GetAllByTMDBID(ids []int64) ([]*Series, error) {
+	series := []*Series{}
+
+	if len(ids) == 0 {
+		return series, nil
+	}
+
+	query := `
+	SELECT s.id, s.created_at, s.title, s.start_year, COALESCE(s.end_year, 0), s.genres, s.overview, s.tmdb_id, s.version
+	FROM unnest($1::bigint[]) WITH ORDINALITY AS t (tmdb_id, position)
+	JOIN series s ON s.tmdb_id = t.tmdb_id
+	ORDER BY t.position`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	for rows.Next() {
+		var s Series
+
+		err := rows.Scan(
+			&s.ID,
+			&s.CreatedAt,
+			&s.Title,
+			&s.StartYear,
+			&s.EndYear,
+			pq.Array(&s.Genres),
+			&s.Overview,
+			&s.TMDBID,
+			&s.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		series = append(series, &s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return series, nil
+}
+
+// TMDBIDs maps catalog series ids to TMDB ids, series without a TMDB id are left out.
+func (m seriesModel) TMDBIDs(seriesIDs []int64) ([]int64, error) {
+	tmdbIDs := []int64{}
+
+	if len(seriesIDs) == 0 {
+		return tmdbIDs, nil
+	}
+
+	query := `
+	SELECT tmdb_id
+	FROM series
+	WHERE id = ANY($1) AND tmdb_id IS NOT NULL`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(seriesIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, err
+		}
+
+		tmdbIDs = append(tmdbIDs, id)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tmdbIDs, nil
+}
+
+// GetSeason returns the season with its episodes in order.
+func (m seriesModel) GetSeason(seriesID int64, number int32) (*Season, error) {
+	if seriesID < 1 || number < 0 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+	SELECT number, title, COALESCE(air_date::text, ''), version
+	FROM seasons
+	WHERE series_id = $1 AND number = $2`
+
+	var season Season
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, seriesID, number).Scan(
+		&season.Number,
+		&season.Title,
+		&season.AirDate,
+		&season.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	query = `
+	SELECT number, title, runtime, COALESCE(air_date::text, '')
+	FROM episodes
+	WHERE series_id = $1 AND season_number = $2
+	ORDER BY number`
+
+	rows, err := m.DB.QueryContext(ctx, query, seriesID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	season.Episodes = []*Episode{}
+
+	for rows.Next() {
+		var episode Episode
+
+		err := rows.Scan(
+			&episode.Number,
+			&episode.Title,
+			&episode.Runtime,
+			&episode.AirDate,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		season.Episodes = append(season.Episodes, &episode)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	season.EpisodeCount = len(season.Episodes)
+
+	return &season, nil
+}
+
+// PutSeason creates or replaces the season together with all of its episodes.
+// The series version is bumped as its season list changes, ErrRecordNotFound is returned if the series doesn't exist.
+func (m seriesModel) PutSeason(seriesID int64, season *Season) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return withTx(ctx, m.DB, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE series SET version = version + 1 WHERE id = $1", seriesID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if rowsAffected == 0 {
+			return ErrRecordNotFound
+		}
+
+		query := `
+		INSERT INTO seasons (series_id, number, title, air_date)
+		VALUES ($1, $2, $3, NULLIF($4, '')::date)
+		ON CONFLICT (series_id, number) DO UPDATE
+		SET title = EXCLUDED.title, air_date = EXCLUDED.air_date, version = seasons.version + 1
+		RETURNING version`
+
+		err = tx.QueryRowContext(ctx, query, seriesID, season.Number, season.Title, season.AirDate).Scan(&season.Version)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "DELETE FROM episodes WHERE series_id = $1 AND season_number = $2", seriesID, season.Number)
+		if err != nil {
+			return err
+		}
+
+		numbers := make([]int32, len(season.Episodes))
+		titles := make([]string, len(season.Episodes))
+		runtimes := make([]int32, len(season.Episodes))
+		airDates := make([]string, len(season.Episodes))
+
+		for i, episode := range season.Episodes {
+			numbers[i] = episode.Number
+			titles[i] = episode.Title
+			runtimes[i] = episode.Runtime
+			airDates[i] = episode.AirDate
+		}
+
+		query = `
+		INSERT INTO episodes (series_id, season_number, number, title, runtime, air_date)
+		SELECT $1, $2, number, title, runtime, NULLIF(air_date, '')::date
+		FROM unnest($3::integer[], $4::text[], $5::integer[], $6::text[]) AS e (number, title, runtime, air_date)`
+
+		_, err = tx.ExecContext(ctx, query, seriesID, season.Number,
+			pq.Array(numbers), pq.Array(titles), pq.Array(runtimes), pq.Array(airDates))
+		if err != nil {
+			return err
+		}
+
+		season.EpisodeCount = len(season.Episodes)
+
+		return nil
+	})
+}
+
+// DeleteSeason removes the season with its episodes and bumps the series version.
+func (m seriesModel) DeleteSeason(seriesID int64, number int32) error {
+	if seriesID < 1 || number < 0 {
+		return ErrRecordNotFound
+	}
+
+	query := `
+	WITH deleted AS (
+		DELETE FROM seasons
+		WHERE series_id = $1 AND number = $2
+		RETURNING series_id
+	)
+	UPDATE series SET version = version + 1
+	WHERE id IN (SELECT series_id FROM deleted)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, seriesID, number)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,233 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// searchConfigs maps languages to PostgreSQL text search configurations,
+// other languages are searched with the 'simple' configuration.
+var searchConfigs = map[string]string{
+	"ar": "arabic",
+	"da": "danish",
+	"de": "german",
+	"el": "greek",
+	"en": "english",
+	"es": "spanish",
+	"fi": "finnish",
+	"fr": "french",
+	"hu": "hungarian",
+	"it": "italian",
+	"nl": "dutch",
+	"no": "norwegian",
+	"pt": "portuguese",
+	"ro": "romanian",
+	"ru": "russian",
+	"sv": "swedish",
+	"tr": "turkish",
+}
+
+func searchConfig(language string) string {
+	if config, ok := searchConfigs[language]; ok {
+		return config
+	}
+
+	return "simple"
+}
+
+// Translation holds movie title and overview in a language other than the original.
+type Translation struct {
+	MovieID  int64  `json:"movie_id" example:"1"`
+	Language string `json:"language" example:"ru"`
+	Title    string `json:"title" example:"Побег из Шоушенка"`
+	Overview string `json:"overview,omitempty" example:"Бухгалтер Энди Дюфрейн обвинён в убийстве..."`
+	Version  int32  `json:"version" example:"1"`
+}
+
+type translationModel struct {
+	DB *sql.DB
+}
+
+// GetAll returns all translations of the movie ordered by language.
+func (m translationModel) GetAll(movieID int64) ([]*Translation, error) {
+	query := `
+	SELECT movie_id, language, title, overview, version
+	FROM movie_translations
+	WHERE movie_id = $1
+	ORDER BY language`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, movieID)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	translations := []*Translation{}
+
+	for rows.Next() {
+		var translation Translation
+
+		err := rows.Scan(
+			&translation.MovieID,
+			&translation.Language,
+			&translation.Title,
+			&translation.Overview,
+			&translation.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		translations = append(translations, &translation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return translations, nil
+}
+
+// Upsert adds the translation or replaces the existing one in the same language.
+// The text search configuration is chosen by the translation language.
+// Returns ErrRecordNotFound if the movie doesn't exist.
+func (m translationModel) Upsert(translation *Translation) error {
+	query := `
+	INSERT INTO movie_translations (movie_id, language, title, overview, search_config)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (movie_id, language) DO UPDATE
+	SET title = EXCLUDED.title, overview = EXCLUDED.overview, version = movie_translations.version + 1
+	RETURNING version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query,
+		translation.MovieID,
+		translation.Language,
+		translation.Title,
+		translation.Overview,
+		searchConfig(translation.Language),
+	).Scan(&translation.Version)
+	if err != nil {
+		var pqErr *pq.Error
+
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code == "23503": // foreign_key_violation
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Delete removes the movie translation in the given language.
+func (m translationModel) Delete(movieID int64, language string) error {
+	query := `
+	DELETE FROM movie_translations
+	WHERE movie_id = $1 AND language = $2`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, query, movieID, language)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Localize replaces titles of the movies with their translations in the most preferred of the languages
+// that has one. The original title is kept in OriginalTitle, movies without translations are left as is.
+func (m translationModel) Localize(movies []*Movie, languages []string) error {
+	if len(movies) == 0 || len(languages) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.ID)
+	}
+
+	query := `
+	SELECT DISTINCT ON (movie_id) movie_id, language, title, overview
+	FROM movie_translations
+	WHERE movie_id = ANY($1) AND language = ANY($2)
+	ORDER BY movie_id, array_position($2, language)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(ids), pq.Array(languages))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	translations := make(map[int64]Translation)
+
+	for rows.Next() {
+		var translation Translation
+
+		err := rows.Scan(&translation.MovieID, &translation.Language, &translation.Title, &translation.Overview)
+		if err != nil {
+			return err
+		}
+
+		translations[translation.MovieID] = translation
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, movie := range movies {
+		translation, ok := translations[movie.ID]
+		if !ok {
+			continue
+		}
+
+		movie.OriginalTitle = movie.Title
+		movie.Title = translation.Title
+		movie.Overview = translation.Overview
+		movie.Language = translation.Language
+	}
+
+	return nil
+}
@@ -218,3 +218,60 @@ func (m userModel) GetForToken(tokenScope, token string) (*User, error) {
 
 	return &user, nil
 }
+
+// Activity visibilities tell who sees the user's activity.
+const (
+	ActivityPublic    = "public"
+	ActivityFollowers = "followers"
+	ActivityPrivate   = "private"
+)
+
+// ActivityVisibilities holds all activity visibilities.
+var ActivityVisibilities = []string{ActivityPublic, ActivityFollowers, ActivityPrivate}
+
+// Privacy holds the user's privacy settings.
+type Privacy struct {
+	ActivityVisibility string `json:"activity_visibility" example:"followers"`
+}
+
+// GetPrivacy returns privacy settings of the user.
+func (m userModel) GetPrivacy(id int64) (*Privacy, error) {
+	var privacy Privacy
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, "SELECT activity_visibility FROM users WHERE id = $1", id).Scan(&privacy.ActivityVisibility)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	return &privacy, nil
+}
+
+// UpdatePrivacy saves privacy settings of the user.
+func (m userModel) UpdatePrivacy(id int64, privacy *Privacy) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, "UPDATE users SET activity_visibility = $1 WHERE id = $2", privacy.ActivityVisibility, id)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
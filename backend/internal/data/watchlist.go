@@ -0,0 +1,198 @@
+package data
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WatchlistItem is a movie the user saved for later.
+type WatchlistItem struct {
+	MovieID int64     `json:"movie_id" example:"1"`
+	Title   string    `json:"title" example:"The Shawshank Redemption"`
+	Year    int32     `json:"year" example:"1994"`
+	Note    string    `json:"note,omitempty" example:"recommended by Anna"`
+	AddedAt time.Time `json:"added_at" example:"2025-01-01T00:00:00Z"`
+}
+
+type watchlistModel struct {
+	DB *sql.DB
+}
+
+// GetAll returns a page of the user's watchlist, movies in trash are left out.
+func (m watchlistModel) GetAll(userID int64, filters Filters) ([]*WatchlistItem, Metadata, error) {
+	query := fmt.Sprintf(`
+	SELECT count(*) OVER(), w.movie_id, m.title, m.year, w.note, w.added_at
+	FROM watchlist w
+	JOIN movies m ON m.id = w.movie_id
+	WHERE w.user_id = $1 AND m.deleted_at IS NULL
+	ORDER BY %s %s, w.movie_id ASC
+	LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	totalRecords := 0
+	items := []*WatchlistItem{}
+
+	for rows.Next() {
+		var item WatchlistItem
+
+		err := rows.Scan(
+			&totalRecords,
+			&item.MovieID,
+			&item.Title,
+			&item.Year,
+			&item.Note,
+			&item.AddedAt,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+
+		items = append(items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return items, metadata, nil
+}
+
+// Add puts the movie on the user's watchlist, the note of a movie already on it is replaced
+// and its added-at time is kept. Reports whether the movie was added,
+// returns ErrRecordNotFound if the movie doesn't exist or is in trash.
+func (m watchlistModel) Add(userID int64, item *WatchlistItem) (bool, error) {
+	// xmax is zero only for freshly inserted rows, updated ones carry the id of the updating transaction
+	query := `
+	WITH movie AS (
+		SELECT id, title, year FROM movies WHERE id = $2 AND deleted_at IS NULL
+	), added AS (
+		INSERT INTO watchlist (user_id, movie_id, note)
+		SELECT $1, id, $3 FROM movie
+		ON CONFLICT (user_id, movie_id) DO UPDATE SET note = EXCLUDED.note
+		RETURNING added_at, xmax = 0 AS inserted
+	)
+	SELECT movie.title, movie.year, added.added_at, added.inserted
+	FROM movie, added`
+
+	var inserted bool
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := m.DB.QueryRowContext(ctx, query, userID, item.MovieID, item.Note).Scan(
+		&item.Title,
+		&item.Year,
+		&item.AddedAt,
+		&inserted,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return false, ErrRecordNotFound
+		default:
+			return false, err
+		}
+	}
+
+	return inserted, nil
+}
+
+// Remove takes the movie off the user's watchlist.
+func (m watchlistModel) Remove(userID, movieID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, "DELETE FROM watchlist WHERE user_id = $1 AND movie_id = $2", userID, movieID)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Mark sets InWatchlist of the movies for the user with a single query.
+func (m watchlistModel) Mark(userID int64, movies []*Movie) error {
+	if len(movies) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(movies))
+	for _, movie := range movies {
+		ids = append(ids, movie.ID)
+	}
+
+	query := "SELECT movie_id FROM watchlist WHERE user_id = $1 AND movie_id = ANY($2)"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, userID, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		e := rows.Close()
+		if err != nil {
+			err = fmt.Errorf("previous error: %w; close error: %w", err, e)
+		} else {
+			err = e
+		}
+	}()
+
+	saved := make(map[int64]bool)
+
+	for rows.Next() {
+		var id int64
+
+		err := rows.Scan(&id)
+		if err != nil {
+			return err
+		}
+
+		saved[id] = true
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, movie := range movies {
+		inWatchlist := saved[movie.ID]
+		movie.InWatchlist = &inWatchlist
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package validate
 
 import (
 	"errors"
+	"regexp"
 
 	"github.com/invopop/validation"
 )
@@ -11,6 +12,15 @@ var (
 	errUniqueValues = errors.New("values must be unique")
 )
 
+// linkRX matches web links with a scheme or a www prefix.
+var linkRX = regexp.MustCompile(`(?i)\b(https?://|www\.)\S+`)
+
+// NoLinks is a validation rule that ensures a string doesn't contain web links,
+// user written texts like reviews are a usual target for spam.
+var NoLinks = validation.NewStringRule(func(s string) bool {
+	return !linkRX.MatchString(s)
+}, "must not contain links")
+
 // Unique returns a validation rule that ensures the provided slice contains only unique values.
 // It validates the given values, not the input value, and is intended to be used with validation.By().
 func Unique[T any](values []T) validation.RuleFunc {
@@ -34,3 +34,39 @@ func TestUnique(t *testing.T) {
 		})
 	}
 }
+
+func TestNoLinks(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		wantErr bool
+	}{
+		{
+			name:  "Plain text",
+			value: "a slow but rewarding story about hope",
+		},
+		{
+			name:  "Dots in text",
+			value: "slow...but rewarding. 9/10",
+		},
+		{
+			name:    "Link with scheme",
+			value:   "watch it at https://example.com/free",
+			wantErr: true,
+		},
+		{
+			name:    "Link with www",
+			value:   "more on WWW.example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validation.Validate(tt.value, NoLinks)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("got %v; want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
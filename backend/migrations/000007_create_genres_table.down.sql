@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS genre_aliases;
+DROP TABLE IF EXISTS genres;
\ No newline at end of file
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS genres (
+    id bigserial PRIMARY KEY,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    name citext UNIQUE NOT NULL,
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE TABLE IF NOT EXISTS genre_aliases (
+    alias citext PRIMARY KEY,
+    genre_id bigint NOT NULL REFERENCES genres ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS genre_aliases_genre_id_idx ON genre_aliases (genre_id);
+
+-- seed the catalog with genres already used by movies, the first spelling in alphabetical order wins
+INSERT INTO genres (name)
+SELECT DISTINCT ON (lower(genre)) genre
+FROM movies, unnest(genres) AS genre
+ORDER BY lower(genre), genre
+ON CONFLICT DO NOTHING;
+
+-- rewrite movie genres to canonical spelling, dropping duplicates and keeping the order
+UPDATE movies
+SET genres = ARRAY(
+    SELECT g.name::text
+    FROM unnest(movies.genres) WITH ORDINALITY AS u(genre, n)
+    INNER JOIN genres g ON g.name = u.genre::citext
+    GROUP BY g.name
+    ORDER BY min(u.n)
+);
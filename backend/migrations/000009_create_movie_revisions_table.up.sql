@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS movie_revisions (
+    id bigserial PRIMARY KEY,
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    version integer NOT NULL,
+    action text NOT NULL,
+    user_id bigint REFERENCES users ON DELETE SET NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    changed_fields text[] NOT NULL,
+    snapshot jsonb NOT NULL,
+    UNIQUE (movie_id, version)
+);
+
+-- existing movies get their current state as the first revision
+INSERT INTO movie_revisions (movie_id, version, action, created_at, changed_fields, snapshot)
+SELECT id, version, 'baseline', created_at, '{}',
+    jsonb_build_object('title', title, 'year', year, 'runtime', runtime, 'genres', genres)
+FROM movies;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS movie_translations (
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    language text NOT NULL,
+    title text NOT NULL,
+    overview text NOT NULL DEFAULT '',
+    search_config regconfig NOT NULL DEFAULT 'simple',
+    version integer NOT NULL DEFAULT 1,
+    PRIMARY KEY (movie_id, language)
+);
+
+CREATE INDEX IF NOT EXISTS movie_translations_title_idx ON movie_translations USING GIN (to_tsvector(search_config, title));
+CREATE INDEX IF NOT EXISTS movie_translations_title_trgm_idx ON movie_translations USING GIN (title gin_trgm_ops);
\ No newline at end of file
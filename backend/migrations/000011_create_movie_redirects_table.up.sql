@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS movie_redirects (
+    old_id bigint PRIMARY KEY,
+    new_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS movie_redirects_new_id_idx ON movie_redirects (new_id);
+
+-- duplicates are looked up by title without case, spaces and punctuation
+CREATE INDEX IF NOT EXISTS movies_normalized_title_year_idx ON movies (regexp_replace(lower(title), '[^[:alnum:]]+', '', 'g'), year)
+WHERE deleted_at IS NULL;
\ No newline at end of file
@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS movie_external_ids (
+    movie_id bigint PRIMARY KEY REFERENCES movies ON DELETE CASCADE,
+    imdb_id text UNIQUE CHECK (imdb_id ~ '^tt[0-9]{7,10}$'),
+    tmdb_id integer UNIQUE CHECK (tmdb_id > 0),
+    movielens_id integer UNIQUE CHECK (movielens_id > 0)
+);
\ No newline at end of file
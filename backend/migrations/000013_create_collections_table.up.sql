@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS collections (
+    id bigserial PRIMARY KEY,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    name text NOT NULL,
+    overview text NOT NULL DEFAULT '',
+    version integer NOT NULL DEFAULT 1
+);
+
+-- a movie belongs to one collection at most, positions start with 1
+CREATE TABLE IF NOT EXISTS collection_movies (
+    collection_id bigint NOT NULL REFERENCES collections ON DELETE CASCADE,
+    movie_id bigint NOT NULL UNIQUE REFERENCES movies ON DELETE CASCADE,
+    position integer NOT NULL CHECK (position > 0),
+    PRIMARY KEY (collection_id, position)
+);
\ No newline at end of file
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS episodes;
+DROP TABLE IF EXISTS seasons;
+DROP TABLE IF EXISTS series;
\ No newline at end of file
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS series (
+    id bigserial PRIMARY KEY,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    title text NOT NULL,
+    start_year integer NOT NULL,
+    end_year integer,
+    genres text[] NOT NULL,
+    overview text NOT NULL DEFAULT '',
+    version integer NOT NULL DEFAULT 1
+);
+
+CREATE INDEX IF NOT EXISTS series_title_idx ON series USING GIN (to_tsvector('simple', title));
+CREATE INDEX IF NOT EXISTS series_title_trgm_idx ON series USING GIN (title gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS series_genres_idx ON series USING GIN (genres);
+
+CREATE TABLE IF NOT EXISTS seasons (
+    series_id bigint NOT NULL REFERENCES series ON DELETE CASCADE,
+    number integer NOT NULL CHECK (number >= 0),
+    title text NOT NULL DEFAULT '',
+    air_date date,
+    version integer NOT NULL DEFAULT 1,
+    PRIMARY KEY (series_id, number)
+);
+
+CREATE TABLE IF NOT EXISTS episodes (
+    series_id bigint NOT NULL,
+    season_number integer NOT NULL,
+    number integer NOT NULL CHECK (number > 0),
+    title text NOT NULL,
+    runtime integer NOT NULL,
+    air_date date,
+    PRIMARY KEY (series_id, season_number, number),
+    FOREIGN KEY (series_id, season_number) REFERENCES seasons ON DELETE CASCADE
+);
\ No newline at end of file
@@ -0,0 +1,4 @@
+ALTER TABLE movies DROP COLUMN IF EXISTS rating;
+ALTER TABLE movies DROP COLUMN IF EXISTS rating_count;
+ALTER TABLE movies DROP COLUMN IF EXISTS rating_sum;
+DROP TABLE IF EXISTS ratings;
\ No newline at end of file
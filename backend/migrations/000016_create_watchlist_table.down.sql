@@ -0,0 +1 @@
+DROP TABLE IF EXISTS watchlist;
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS seen_movies;
+DROP TABLE IF EXISTS watch_events;
\ No newline at end of file
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS watch_events (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    watched_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    progress smallint CHECK (progress BETWEEN 1 AND 100),
+    rewatch boolean NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS watch_events_user_id_watched_at_idx ON watch_events (user_id, watched_at DESC, id);
+CREATE INDEX IF NOT EXISTS watch_events_movie_id_idx ON watch_events (movie_id);
+
+CREATE TABLE IF NOT EXISTS seen_movies (
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    seen_at timestamp(0) with time zone NOT NULL,
+    PRIMARY KEY (user_id, movie_id)
+);
\ No newline at end of file
@@ -0,0 +1,40 @@
+CREATE TABLE IF NOT EXISTS reviews (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    body text NOT NULL,
+    spoiler boolean NOT NULL DEFAULT false,
+    helpful_count integer NOT NULL DEFAULT 0,
+    unhelpful_count integer NOT NULL DEFAULT 0,
+    helpfulness integer GENERATED ALWAYS AS (helpful_count - unhelpful_count) STORED,
+    hidden_at timestamp(0) with time zone,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    version integer NOT NULL DEFAULT 1,
+    UNIQUE (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS reviews_movie_id_helpfulness_idx ON reviews (movie_id, helpfulness) WHERE hidden_at IS NULL;
+CREATE INDEX IF NOT EXISTS reviews_movie_id_created_at_idx ON reviews (movie_id, created_at) WHERE hidden_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    review_id bigint NOT NULL REFERENCES reviews ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    helpful boolean NOT NULL,
+    PRIMARY KEY (review_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS review_reports (
+    review_id bigint NOT NULL REFERENCES reviews ON DELETE CASCADE,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    reason text NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    resolved_at timestamp(0) with time zone,
+    PRIMARY KEY (review_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS review_reports_open_idx ON review_reports (review_id) WHERE resolved_at IS NULL;
+
+INSERT INTO permissions (code)
+VALUES ('reviews:moderate')
+ON CONFLICT (code) DO NOTHING;
\ No newline at end of file
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS follows;
+
+ALTER TABLE users DROP COLUMN IF EXISTS activity_visibility;
\ No newline at end of file
@@ -0,0 +1,28 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS activity_visibility text NOT NULL DEFAULT 'public'
+    CHECK (activity_visibility IN ('public', 'followers', 'private'));
+
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    followee_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id)
+);
+
+CREATE INDEX IF NOT EXISTS follows_followee_id_idx ON follows (followee_id);
+
+-- activities reference what they describe, so the feed leaves out hidden reviews, non-public lists
+-- and movies in trash at read time
+CREATE TABLE IF NOT EXISTS activities (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    type text NOT NULL CHECK (type IN ('rating', 'review', 'list')),
+    movie_id bigint REFERENCES movies ON DELETE CASCADE,
+    score smallint,
+    review_id bigint REFERENCES reviews ON DELETE CASCADE,
+    list_id bigint REFERENCES lists ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS activities_user_id_created_at_idx ON activities (user_id, created_at DESC, id);
+CREATE INDEX IF NOT EXISTS activities_movie_id_idx ON activities (movie_id);
\ No newline at end of file
@@ -0,0 +1,16 @@
+-- implicit feedback events of users for model training, written in batches by the API
+CREATE TABLE IF NOT EXISTS events (
+    id bigserial PRIMARY KEY,
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    type text NOT NULL CHECK (type IN ('click', 'detail_view', 'trailer_play', 'dwell')),
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    dwell_ms integer CHECK (dwell_ms > 0),
+    occurred_at timestamp(3) with time zone NOT NULL,
+    received_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    dedupe_key text NOT NULL,
+    UNIQUE (user_id, dedupe_key),
+    CHECK ((type = 'dwell') = (dwell_ms IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS events_movie_id_idx ON events (movie_id);
+CREATE INDEX IF NOT EXISTS events_occurred_at_idx ON events (occurred_at);
\ No newline at end of file
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS dismissed_genres;
+DROP TABLE IF EXISTS dismissed_movies;
\ No newline at end of file
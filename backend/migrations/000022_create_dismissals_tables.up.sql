@@ -0,0 +1,18 @@
+-- movies and genres users are not interested in, used to filter and down-weight recommendations
+CREATE TABLE IF NOT EXISTS dismissed_movies (
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    movie_id bigint NOT NULL REFERENCES movies ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, movie_id)
+);
+
+CREATE INDEX IF NOT EXISTS dismissed_movies_movie_id_idx ON dismissed_movies (movie_id);
+
+CREATE TABLE IF NOT EXISTS dismissed_genres (
+    user_id bigint NOT NULL REFERENCES users ON DELETE CASCADE,
+    genre_id bigint NOT NULL REFERENCES genres ON DELETE CASCADE,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, genre_id)
+);
+
+CREATE INDEX IF NOT EXISTS dismissed_genres_genre_id_idx ON dismissed_genres (genre_id);
\ No newline at end of file
@@ -0,0 +1 @@
+ALTER TABLE series ADD COLUMN IF NOT EXISTS tmdb_id bigint UNIQUE;
@@ -24,7 +24,7 @@ _sym_db = _symbol_database.Default()
 
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12\x63ommon/types.proto\x12\x06\x63ommon\".\n\x0eRecommendation\x12\r\n\x05title\x18\x01 \x01(\t\x12\r\n\x05score\x18\x02 \x01(\x01\x42IZGgithub.com/vladgrskkh/movie_recomendation_system/genproto/common;commonb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x12\x63ommon/types.proto\x12\x06\x63ommon\"?\n\x0eRecommendation\x12\r\n\x05title\x18\x01 \x01(\t\x12\r\n\x05score\x18\x02 \x01(\x01\x12\x0f\n\x07movieId\x18\x03 \x01(\x03*\\\n\x0b\x43ontentType\x12\x1c\n\x18\x43ONTENT_TYPE_UNSPECIFIED\x10\x00\x12\x16\n\x12\x43ONTENT_TYPE_MOVIE\x10\x01\x12\x17\n\x13\x43ONTENT_TYPE_SERIES\x10\x02\x42IZGgithub.com/vladgrskkh/movie_recomendation_system/genproto/common;commonb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -33,5 +33,7 @@ if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'ZGgithub.com/vladgrskkh/movie_recomendation_system/genproto/common;common'
   _globals['_RECOMMENDATION']._serialized_start=30
-  _globals['_RECOMMENDATION']._serialized_end=76
+  _globals['_RECOMMENDATION']._serialized_end=93
+  _globals['_CONTENTTYPE']._serialized_start=95
+  _globals['_CONTENTTYPE']._serialized_end=187
 # @@protoc_insertion_point(module_scope)
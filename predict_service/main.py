@@ -8,16 +8,41 @@ from models.recommender_model import MovieRecommender
 
 class RecommendationService(predict_pb2_grpc.RecommendationServicer):
     def __init__(self):
-        print("Loading model")
+        print("Loading models")
         self.model = joblib.load("models/recommender.pkl")
-        print("Model loaded")
+        self.models = {
+            common_pb2.CONTENT_TYPE_UNSPECIFIED: self.model,
+            common_pb2.CONTENT_TYPE_MOVIE: self.model,
+            common_pb2.CONTENT_TYPE_SERIES: joblib.load("models/series_recommender.pkl"),
+        }
+        print("Models loaded")
 
     def Recommend(self, request, context):
-        movie_title = request.movieTitle
-        recs = self.model.recommend(movie_title)
+        # movies and series are recommended by separate models, ids of both are TMDB ids
+        model = self.models.get(request.contentType, self.model)
+        recs = model.recommend(
+            request.movieTitle,
+            top_k=request.topK or 5,
+            movie_id=request.movieId,
+            genres=request.filter.genres,
+            year_from=request.filter.yearFrom,
+            year_to=request.filter.yearTo,
+            exclude_ids=request.filter.excludeMovieIds,
+        )
         recommendations = [
-            common_pb2.Recommendation(title=title, score=score)
-            for title, score in recs
+            common_pb2.Recommendation(movieId=movie_id, title=title, score=score)
+            for movie_id, title, score in recs
+        ]
+        return predict_pb2.RecommendResponse(recommendations=recommendations)
+
+    def RecommendForUser(self, request, context):
+        profile = [(movie.movieId, movie.weight) for movie in request.profile]
+        recs = self.model.recommend_for_profile(
+            profile, exclude_ids=request.excludeMovieIds, top_k=request.topK or 20
+        )
+        recommendations = [
+            common_pb2.Recommendation(movieId=movie_id, title=title, score=score)
+            for movie_id, title, score in recs
         ]
         return predict_pb2.RecommendResponse(recommendations=recommendations)
 
@@ -1,14 +1,83 @@
+import numpy as np
+
+
 class MovieRecommender:
     def __init__(self, df, similarity):
         self.df = df
         self.similarity = similarity
 
-    def recommend(self, movie_title, top_k=5):
-        if movie_title not in self.df["title"].values:
-            return [("Movie not found", 0.0)]
+    def _column(self, name, default):
+        # models trained before ids, genres and years were kept have only titles
+        if name in self.df.columns:
+            return self.df[name].values
+        return [default] * len(self.df)
+
+    def recommend(self, movie_title="", top_k=5, movie_id=0, genres=(), year_from=0, year_to=0, exclude_ids=()):
+        """Recommends movies similar to a movie given by TMDB id or, when movie_id is 0, by title.
+
+        Returns up to top_k (id, title, score) triples, id is 0 for models trained without ids.
+        Recommended movies have one of the genres and a year within year_from and year_to
+        when those are set, movies with exclude_ids are never recommended.
+        """
+        ids = self._column("id", 0)
+        titles = self.df["title"].values
+        movie_genres = self._column("genres", [])
+        years = self._column("year", 0)
+
+        if movie_id:
+            matches = [i for i, id_ in enumerate(ids) if id_ == movie_id]
+        else:
+            matches = [i for i, title in enumerate(titles) if title == movie_title]
+        if not matches:
+            return []
+
+        idx = matches[0]
+        genres = set(genres)
+        excluded = set(exclude_ids)
+
+        recs = []
+        for i in np.argsort(self.similarity[idx])[::-1]:
+            if len(recs) == top_k:
+                break
+            if i == idx or (ids[i] and ids[i] in excluded):
+                continue
+            if genres and not genres.intersection(movie_genres[i]):
+                continue
+            if (year_from and years[i] < year_from) or (year_to and years[i] > year_to):
+                continue
+            recs.append((int(ids[i]), titles[i], float(self.similarity[idx][i])))
+        return recs
+
+    def recommend_for_profile(self, profile, exclude_ids=(), top_k=20):
+        """Recommends movies similar to the liked movies of a profile and unlike the disliked ones.
+
+        profile is a list of (TMDB id, weight) pairs with weights from -1 to 1, returns up to top_k
+        (id, title, score) triples. Movies of the profile and with exclude_ids are never recommended,
+        models trained without ids recommend nothing.
+        """
+        ids = self._column("id", 0)
+        titles = self.df["title"].values
+        index = {id_: i for i, id_ in enumerate(ids) if id_}
+
+        scores = np.zeros(len(titles))
+        total_weight = 0.0
+        for movie_id, weight in profile:
+            if movie_id in index and weight != 0:
+                scores += weight * self.similarity[index[movie_id]]
+                total_weight += abs(weight)
+
+        if total_weight == 0:
+            return []
+
+        scores /= total_weight
+
+        excluded = set(exclude_ids) | {movie_id for movie_id, _ in profile}
+        ranked = np.argsort(scores)[::-1]
 
-        idx = self.df[self.df["title"] == movie_title].index[0]
-        sim_scores = list(enumerate(self.similarity[idx]))
-        sim_scores = sorted(sim_scores, key=lambda x: x[1], reverse=True)[1:top_k+1]
-        recs = [(self.df.iloc[i]["title"], float(score)) for i, score in sim_scores]
+        recs = []
+        for i in ranked:
+            if len(recs) == top_k or scores[i] <= 0:
+                break
+            if ids[i] and ids[i] not in excluded:
+                recs.append((int(ids[i]), titles[i], float(scores[i])))
         return recs
\ No newline at end of file
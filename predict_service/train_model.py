@@ -7,28 +7,43 @@ import ast
 
 from models.recommender_model import MovieRecommender
 
-df = pd.read_csv("models/data/movies_dataset.csv")
-
-def parse_features(x):
+def parse_names(x):
     try:
         data = ast.literal_eval(x)
         if isinstance(data, list):
-            return " ".join([d["name"] for d in data])
+            return [d["name"] for d in data]
     except:
-        return ""
-    return ""
+        # series datasets list genres as plain comma separated names
+        return [name.strip() for name in str(x).split(",") if name.strip()]
+    return []
+
+def parse_features(x):
+    return " ".join(parse_names(x))
+
+def train(dataset, title_column, date_column, output):
+    """Trains a recommender on a TMDB dataset, titles are read from title_column and years from date_column."""
+    df = pd.read_csv(dataset)
+    if "keywords" not in df.columns:
+        df["keywords"] = "[]"
+
+    df["title"] = df[title_column]
+    df["genre_list"] = df["genres"].fillna("[]").apply(parse_names)
+    df["genres"] = df["genres"].fillna("[]").apply(parse_features)
+    df["keywords"] = df["keywords"].fillna("[]").apply(parse_features)
+    df["overview"] = df["overview"].fillna("")
+    df["year"] = pd.to_datetime(df[date_column], errors="coerce").dt.year.fillna(0).astype(int)
 
-df["genres"] = df["genres"].fillna("[]").apply(parse_features)
-df["keywords"] = df["keywords"].fillna("[]").apply(parse_features)
-df["overview"] = df["overview"].fillna("")
+    df["text_features"] = df["overview"] + " " + df["genres"] + " " + df["keywords"]
 
-df["text_features"] = df["overview"] + " " + df["genres"] + " " + df["keywords"]
+    vectorizer = TfidfVectorizer(stop_words="english", max_features=10000)
+    tfidf_matrix = vectorizer.fit_transform(df["text_features"])
 
-vectorizer = TfidfVectorizer(stop_words="english", max_features=10000)
-tfidf_matrix = vectorizer.fit_transform(df["text_features"])
+    similarity = cosine_similarity(tfidf_matrix)
 
-similarity = cosine_similarity(tfidf_matrix)
+    catalog = df[["id", "title", "genre_list", "year"]].rename(columns={"genre_list": "genres"})
+    model = MovieRecommender(catalog.reset_index(drop=True), similarity)
+    joblib.dump(model, output)
+    print(f"TMDB-based recommender model saved to {output}")
 
-model = MovieRecommender(df[["title"]], similarity)
-joblib.dump(model, "predict_service/models/recommender.pkl")
-print("TMDB-based recommender model saved to models/recommender.pkl")
+train("models/data/movies_dataset.csv", "title", "release_date", "predict_service/models/recommender.pkl")
+train("models/data/series_dataset.csv", "name", "first_air_date", "predict_service/models/series_recommender.pkl")
@@ -25,7 +25,7 @@ _sym_db = _symbol_database.Default()
 from common import types_pb2 as common_dot_types__pb2
 
 
-DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x18v1/predict/predict.proto\x12\nv1.predict\x1a\x12\x63ommon/types.proto\"&\n\x10RecommendRequest\x12\x12\n\nmovieTitle\x18\x01 \x01(\t\"D\n\x11RecommendResponse\x12/\n\x0frecommendations\x18\x01 \x03(\x0b\x32\x16.common.Recommendation2Z\n\x0eRecommendation\x12H\n\tRecommend\x12\x1c.v1.predict.RecommendRequest\x1a\x1d.v1.predict.RecommendResponseBNZLgithub.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict;predictb\x06proto3')
+DESCRIPTOR = _descriptor_pool.Default().AddSerializedFile(b'\n\x18v1/predict/predict.proto\x12\nv1.predict\x1a\x12\x63ommon/types.proto\"\x9c\x01\n\x10RecommendRequest\x12\x12\n\nmovieTitle\x18\x01 \x01(\t\x12(\n\x0b\x63ontentType\x18\x02 \x01(\x0e\x32\x13.common.ContentType\x12\x0f\n\x07movieId\x18\x03 \x01(\x03\x12\x0c\n\x04topK\x18\x04 \x01(\x05\x12+\n\x06\x66ilter\x18\x05 \x01(\x0b\x32\x1b.v1.predict.RecommendFilter\"\\\n\x0fRecommendFilter\x12\x0e\n\x06genres\x18\x01 \x03(\t\x12\x10\n\x08yearFrom\x18\x02 \x01(\x05\x12\x0e\n\x06yearTo\x18\x03 \x01(\x05\x12\x17\n\x0f\x65xcludeMovieIds\x18\x04 \x03(\x03\"D\n\x11RecommendResponse\x12/\n\x0frecommendations\x18\x01 \x03(\x0b\x32\x16.common.Recommendation\"<\n\x0cProfileMovie\x12\x0e\n\x06weight\x18\x02 \x01(\x01\x12\x0f\n\x07movieId\x18\x03 \x01(\x03J\x04\x08\x01\x10\x02R\x05title\"}\n\x14UserRecommendRequest\x12)\n\x07profile\x18\x01 \x03(\x0b\x32\x18.v1.predict.ProfileMovie\x12\x0c\n\x04topK\x18\x03 \x01(\x05\x12\x17\n\x0f\x65xcludeMovieIds\x18\x04 \x03(\x03J\x04\x08\x02\x10\x03R\rexcludeTitles2\xaf\x01\n\x0eRecommendation\x12H\n\tRecommend\x12\x1c.v1.predict.RecommendRequest\x1a\x1d.v1.predict.RecommendResponse\x12S\n\x10RecommendForUser\x12 .v1.predict.UserRecommendRequest\x1a\x1d.v1.predict.RecommendResponseBNZLgithub.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict;predictb\x06proto3')
 
 _globals = globals()
 _builder.BuildMessageAndEnumDescriptors(DESCRIPTOR, _globals)
@@ -33,10 +33,16 @@ _builder.BuildTopDescriptorsAndMessages(DESCRIPTOR, 'v1.predict.predict_pb2', _g
 if not _descriptor._USE_C_DESCRIPTORS:
   _globals['DESCRIPTOR']._loaded_options = None
   _globals['DESCRIPTOR']._serialized_options = b'ZLgithub.com/vladgrskkh/movie_recomendation_system/genproto/v1/predict;predict'
-  _globals['_RECOMMENDREQUEST']._serialized_start=60
-  _globals['_RECOMMENDREQUEST']._serialized_end=98
-  _globals['_RECOMMENDRESPONSE']._serialized_start=100
-  _globals['_RECOMMENDRESPONSE']._serialized_end=168
-  _globals['_RECOMMENDATION']._serialized_start=170
-  _globals['_RECOMMENDATION']._serialized_end=260
+  _globals['_RECOMMENDREQUEST']._serialized_start=61
+  _globals['_RECOMMENDREQUEST']._serialized_end=217
+  _globals['_RECOMMENDFILTER']._serialized_start=219
+  _globals['_RECOMMENDFILTER']._serialized_end=311
+  _globals['_RECOMMENDRESPONSE']._serialized_start=313
+  _globals['_RECOMMENDRESPONSE']._serialized_end=381
+  _globals['_PROFILEMOVIE']._serialized_start=383
+  _globals['_PROFILEMOVIE']._serialized_end=443
+  _globals['_USERRECOMMENDREQUEST']._serialized_start=445
+  _globals['_USERRECOMMENDREQUEST']._serialized_end=570
+  _globals['_RECOMMENDATION']._serialized_start=573
+  _globals['_RECOMMENDATION']._serialized_end=748
 # @@protoc_insertion_point(module_scope)
@@ -39,6 +39,11 @@ class RecommendationStub(object):
                 request_serializer=v1_dot_predict_dot_predict__pb2.RecommendRequest.SerializeToString,
                 response_deserializer=v1_dot_predict_dot_predict__pb2.RecommendResponse.FromString,
                 _registered_method=True)
+        self.RecommendForUser = channel.unary_unary(
+                '/v1.predict.Recommendation/RecommendForUser',
+                request_serializer=v1_dot_predict_dot_predict__pb2.UserRecommendRequest.SerializeToString,
+                response_deserializer=v1_dot_predict_dot_predict__pb2.RecommendResponse.FromString,
+                _registered_method=True)
 
 
 class RecommendationServicer(object):
@@ -50,6 +55,12 @@ class RecommendationServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def RecommendForUser(self, request, context):
+        """Missing associated documentation comment in .proto file."""
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_RecommendationServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -58,6 +69,11 @@ def add_RecommendationServicer_to_server(servicer, server):
                     request_deserializer=v1_dot_predict_dot_predict__pb2.RecommendRequest.FromString,
                     response_serializer=v1_dot_predict_dot_predict__pb2.RecommendResponse.SerializeToString,
             ),
+            'RecommendForUser': grpc.unary_unary_rpc_method_handler(
+                    servicer.RecommendForUser,
+                    request_deserializer=v1_dot_predict_dot_predict__pb2.UserRecommendRequest.FromString,
+                    response_serializer=v1_dot_predict_dot_predict__pb2.RecommendResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'v1.predict.Recommendation', rpc_method_handlers)
@@ -95,3 +111,30 @@ class Recommendation(object):
             timeout,
             metadata,
             _registered_method=True)
+
+    @staticmethod
+    def RecommendForUser(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(
+            request,
+            target,
+            '/v1.predict.Recommendation/RecommendForUser',
+            v1_dot_predict_dot_predict__pb2.UserRecommendRequest.SerializeToString,
+            v1_dot_predict_dot_predict__pb2.RecommendResponse.FromString,
+            options,
+            channel_credentials,
+            insecure,
+            call_credentials,
+            compression,
+            wait_for_ready,
+            timeout,
+            metadata,
+            _registered_method=True)